@@ -0,0 +1,119 @@
+package config
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Tag theo Accept-Language (BCP 47) khác với mã ngôn ngữ trong hệ thống
+var languageAliases = map[string]string{
+	"vi": "vn",
+	"ja": "jp",
+}
+
+// Languages ngôn ngữ hỗ trợ (LANG_LIST, vd: vn,en,jp,ko), thứ tự cũng là thứ tự fallback sau ngôn ngữ mặc định
+func Languages() []string {
+	var languages []string
+	for _, lang := range strings.Split(Config("LANG_LIST"), ",") {
+		if lang = languageCode(lang); len(lang) > 0 {
+			languages = append(languages, lang)
+		}
+	}
+	if len(languages) == 0 {
+		return []string{"vn", "en", "jp"}
+	}
+
+	return languages
+}
+
+// IsLanguage true nếu lang nằm trong LANG_LIST
+func IsLanguage(lang string) bool {
+	for _, code := range Languages() {
+		if code == lang {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DefaultLanguage ngôn ngữ mặc định (LANG_DEFAULT), bắt buộc có bản dịch.
+// Chưa cấu hình hoặc không nằm trong LANG_LIST thì lấy ngôn ngữ đầu tiên của LANG_LIST
+func DefaultLanguage() string {
+	if lang := languageCode(Config("LANG_DEFAULT")); IsLanguage(lang) {
+		return lang
+	}
+
+	return Languages()[0]
+}
+
+func languageCode(tag string) string {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if code, ok := languageAliases[tag]; ok {
+		return code
+	}
+
+	return tag
+}
+
+// NegotiateLanguage chọn ngôn ngữ theo query lang, sau đó Accept-Language (theo q), cuối cùng là mặc định
+func NegotiateLanguage(lang, acceptLanguage string) string {
+	if code := languageCode(lang); IsLanguage(code) {
+		return code
+	}
+
+	type candidate struct {
+		code    string
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		code := languageCode(strings.SplitN(fields[0], "-", 2)[0])
+		if !IsLanguage(code) {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality > 0 {
+			candidates = append(candidates, candidate{code, quality})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+	if len(candidates) > 0 {
+		return candidates[0].code
+	}
+
+	return DefaultLanguage()
+}
+
+// LocalValue giá trị theo ngôn ngữ lang trong values (ngôn ngữ → giá trị).
+// Trống thì lấy theo ngôn ngữ mặc định, rồi lần lượt theo LANG_LIST
+func LocalValue(lang string, values map[string]string) string {
+	if len(values[lang]) > 0 {
+		return values[lang]
+	}
+
+	for _, code := range append([]string{DefaultLanguage()}, Languages()...) {
+		if len(values[code]) > 0 {
+			return values[code]
+		}
+	}
+
+	return ""
+}
+
+// LocalName tên theo ngôn ngữ lang khi chỉ có 3 cột vn / en / jp
+func LocalName(lang, nameVN, nameEN, nameJP string) string {
+	return LocalValue(lang, map[string]string{"vn": nameVN, "en": nameEN, "jp": nameJP})
+}
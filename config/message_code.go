@@ -7,16 +7,48 @@ var messageList = map[string]string{
 	"FORMAT_NUMBER": "MSG_V0004", // param is number
 	"FORMAT_DATE":   "MSG_V0003", // param format date is YYYY-MM-DD. Ex: 2023-01-01
 	"REQUIRE":       "MSG_V0001", // Param require
+	"VALUE_INVALID": "MSG_V0005", // param is not one of the allowed values
+	"CODE_EXISTS":   "MSG_V0006", // code already used by another record
+	"HAS_CHILDREN":  "MSG_V0007", // record still has active child records
+	"FORMAT_TIME":   "MSG_V0008", // param format time is HH:MM. Ex: 08:30
+	"BREAK_OUTSIDE_SHIFT": "MSG_V0009", // break window is not inside the shift block
+	"SHIFT_OVERLAP":       "MSG_V0010", // shift time blocks overlap
+	"SHIFT_CONFLICT":            "MSG_V0011", // employee already has a shift at that time
+	"REST_TOO_SHORT":            "MSG_V0012", // rest between two shifts is shorter than the labor rule
+	"WEEK_HOURS_EXCEEDED":       "MSG_V0013", // working time of the week is over the labor rule
+	"CONSECUTIVE_DAYS_EXCEEDED": "MSG_V0014", // too many consecutive working days
+	"NO_SHIFT":                  "MSG_V0015", // employee has no shift going on at that time
+	"ATTENDANCE_EXISTS":         "MSG_V0016", // already checked in / out for the shift, or payload already used
+	"NOT_CHECKED_IN":            "MSG_V0017", // check-out without check-in
+	"OUTSIDE_SITE":              "MSG_V0018", // coordinates outside every attendance site
+	"FORMAT_EMAIL":              "MSG_V0019", // param is not an email address. Ex: name@example.com
 
 	"KEY_NOT_FOUND":   "MSG_S0000",      // key error not found
 	"SYSTEM_ERROR":    "MSG_S0001",      // system error
 	"TOKEN_INCORRECT": "MSG_S0002",      // token invalid
+	"PERMISSION_DENIED": "MSG_S0003",    // token has no permission for this route
+	"KEY_SCOPE_DENIED":  "MSG_S0004",    // api key has no scope for this route
+	"IMPERSONATION_DENIED": "MSG_S0005", // not allowed while acting as another user
 	"GET_DATA_FAIL":   "MSG_RE0001",     //get data fail
 	"CREATE_SUCCESS":	"MSG_CI0001", //Create new data success
 	"NOT_ID_EXISTS" : "MSG_RE0002",//No item with that Id exists 
 	"GET_DATA_SUCCESS": "MSG_RI0001", //Get data success
 
 	"USERNAME_PASSWORD_INCORRECT": "MSG_N0000",
+	"ACCOUNT_LOCKED":              "MSG_N0008", // too many failed logins for the username or IP
+	"SSO_VERIFY_FAIL":    "MSG_N0001",
+	"SSO_USER_NOT_FOUND": "MSG_N0002",
+	"SSO_USER_NOT_LINKED": "MSG_N0009", // local account exists, an admin has to link it to the SSO identity
+	"REFRESH_TOKEN_REUSED": "MSG_N0003",
+	"MAIL_SENT":            "MSG_NI0004",
+	"TWO_FACTOR_REQUIRED":       "MSG_N0004", // login needs the TOTP step
+	"TWO_FACTOR_SETUP_REQUIRED": "MSG_N0005", // role policy requires TOTP, user has not enrolled
+	"TWO_FACTOR_INCORRECT":      "MSG_N0006",
+	"TWO_FACTOR_ENABLED":        "MSG_N0007", // TOTP already enabled, disable it before enrolling again
+	"PERMISSION_NOT_FOUND": "MSG_V1001",
+	"LOGIN_SUCCESS":   "MSG_NI0001",
+	"LOGOUT_SUCCESS":  "MSG_NI0002",
+	"REFRESH_SUCCESS": "MSG_NI0003",
 	"MISSING_FIELDS": "MSG_V1000",
 	"UPDATE_SUCCESS": "MSG_UI0001",
 	"DELETE_SUCCESS": "MSG_DI0001",
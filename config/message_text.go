@@ -0,0 +1,77 @@
+package config
+
+// Nội dung message theo ngôn ngữ. Key là giá trị GetMessageCode trả về (mã MSG_..., hoặc chính key nếu chưa có mã)
+var messageText = map[string]map[string]string{
+	"MSG_V0000":  {"vn": "Tham số không hợp lệ", "en": "Invalid parameters", "jp": "パラメータが不正です"},
+	"MSG_V0001":  {"vn": "Bắt buộc nhập hoặc vượt quá độ dài cho phép", "en": "Required or longer than allowed", "jp": "必須項目、または最大文字数を超えています"},
+	"MSG_V0002":  {"vn": "Độ dài không đúng", "en": "Invalid length", "jp": "桁数が正しくありません"},
+	"MSG_V0003":  {"vn": "Ngày phải có dạng YYYY-MM-DD", "en": "Date must be in YYYY-MM-DD format", "jp": "日付はYYYY-MM-DD形式で入力してください"},
+	"MSG_V0004":  {"vn": "Giá trị phải là số", "en": "Value must be a number", "jp": "数値で入力してください"},
+	"MSG_V0005":  {"vn": "Giá trị không hợp lệ", "en": "Value is not allowed", "jp": "許可されていない値です"},
+	"MSG_V0006":  {"vn": "Mã đã được sử dụng", "en": "Code is already in use", "jp": "コードは既に使用されています"},
+	"MSG_V0007":  {"vn": "Vẫn còn dữ liệu cấp dưới đang hoạt động", "en": "Record still has active child records", "jp": "有効な下位データが存在します"},
+	"MSG_V0008":  {"vn": "Giờ phải có dạng HH:MM", "en": "Time must be in HH:MM format", "jp": "時刻はHH:MM形式で入力してください"},
+	"MSG_V0009":  {"vn": "Giờ nghỉ phải nằm trong ca", "en": "Break must be inside the shift", "jp": "休憩時間はシフト内に設定してください"},
+	"MSG_V0010":  {"vn": "Các khung giờ ca bị trùng nhau", "en": "Shift times overlap", "jp": "シフトの時間帯が重複しています"},
+	"MSG_V0011":  {"vn": "Nhân viên đã có ca khác trong thời gian này", "en": "Employee already has a shift at this time", "jp": "この時間帯には既に別のシフトがあります"},
+	"MSG_V0012":  {"vn": "Thời gian nghỉ giữa hai ca quá ngắn", "en": "Rest between shifts is too short", "jp": "シフト間の休息時間が短すぎます"},
+	"MSG_V0013":  {"vn": "Vượt quá số giờ làm tối đa trong tuần", "en": "Weekly working hours exceeded", "jp": "週の最大労働時間を超えています"},
+	"MSG_V0014":  {"vn": "Vượt quá số ngày làm liên tiếp tối đa", "en": "Too many consecutive working days", "jp": "連続勤務日数の上限を超えています"},
+	"MSG_V0015":  {"vn": "Không có ca làm việc vào thời điểm này", "en": "No shift at this time", "jp": "この時間帯にシフトがありません"},
+	"MSG_V0016":  {"vn": "Đã chấm công cho ca này", "en": "Already recorded for this shift", "jp": "このシフトは既に打刻済みです"},
+	"MSG_V0017":  {"vn": "Chưa chấm công vào ca", "en": "Not checked in yet", "jp": "まだ出勤打刻されていません"},
+	"MSG_V0018":  {"vn": "Vị trí nằm ngoài địa điểm chấm công", "en": "Location is outside the attendance site", "jp": "打刻可能な場所の範囲外です"},
+	"MSG_V0019":  {"vn": "Email không hợp lệ", "en": "Invalid email address", "jp": "メールアドレスが正しくありません"},
+	"MSG_V1000":  {"vn": "Thiếu hoặc sai thông tin bắt buộc", "en": "Missing or invalid fields", "jp": "必須項目が不足しているか不正です"},
+	"MSG_V1001":  {"vn": "Không tìm thấy quyền", "en": "Permission not found", "jp": "権限が見つかりません"},
+	"MSG_S0000":  {"vn": "Không tìm thấy API key", "en": "API key not found", "jp": "APIキーが見つかりません"},
+	"MSG_S0001":  {"vn": "Lỗi hệ thống", "en": "System error", "jp": "システムエラーが発生しました"},
+	"MSG_S0002":  {"vn": "Token không hợp lệ", "en": "Invalid token", "jp": "トークンが無効です"},
+	"MSG_S0003":  {"vn": "Không có quyền thực hiện", "en": "Permission denied", "jp": "権限がありません"},
+	"MSG_S0004":  {"vn": "API key không được dùng cho chức năng này", "en": "API key is not allowed for this route", "jp": "APIキーにこの機能の権限がありません"},
+	"MSG_S0005":  {"vn": "Không được phép khi đang đăng nhập thay người khác", "en": "Not allowed while impersonating another user", "jp": "代理ログイン中は実行できません"},
+	"MSG_RE0001": {"vn": "Lấy dữ liệu thất bại", "en": "Failed to get data", "jp": "データの取得に失敗しました"},
+	"MSG_RE0002": {"vn": "Không tồn tại dữ liệu với ID này", "en": "No item with that ID exists", "jp": "該当IDのデータが存在しません"},
+	"MSG_RI0001": {"vn": "Lấy dữ liệu thành công", "en": "Data retrieved successfully", "jp": "データを取得しました"},
+	"MSG_CI0001": {"vn": "Tạo mới thành công", "en": "Created successfully", "jp": "作成しました"},
+	"MSG_UI0001": {"vn": "Cập nhật thành công", "en": "Updated successfully", "jp": "更新しました"},
+	"MSG_DI0001": {"vn": "Xoá thành công", "en": "Deleted successfully", "jp": "削除しました"},
+	"MSG_N0000":  {"vn": "Tên đăng nhập hoặc mật khẩu không đúng", "en": "Incorrect username or password", "jp": "ユーザー名またはパスワードが正しくありません"},
+	"MSG_N0001":  {"vn": "Xác thực SSO thất bại", "en": "SSO verification failed", "jp": "SSO認証に失敗しました"},
+	"MSG_N0002":  {"vn": "Không tìm thấy người dùng SSO", "en": "SSO user not found", "jp": "SSOユーザーが見つかりません"},
+	"MSG_N0003":  {"vn": "Refresh token đã được sử dụng", "en": "Refresh token has already been used", "jp": "リフレッシュトークンは既に使用されています"},
+	"MSG_N0004":  {"vn": "Cần nhập mã xác thực hai bước", "en": "Two-factor code required", "jp": "二段階認証コードが必要です"},
+	"MSG_N0005":  {"vn": "Cần cài đặt xác thực hai bước", "en": "Two-factor authentication must be set up", "jp": "二段階認証の設定が必要です"},
+	"MSG_N0006":  {"vn": "Mã xác thực hai bước không đúng", "en": "Incorrect two-factor code", "jp": "二段階認証コードが正しくありません"},
+	"MSG_N0007":  {"vn": "Xác thực hai bước đã được bật", "en": "Two-factor authentication is already enabled", "jp": "二段階認証は既に有効です"},
+	"MSG_N0008":  {"vn": "Tài khoản tạm thời bị khoá do đăng nhập sai nhiều lần", "en": "Temporarily locked after too many failed logins", "jp": "ログイン失敗が多すぎるため一時的にロックされています"},
+	"MSG_N0009":  {"vn": "Tài khoản chưa được liên kết với SSO", "en": "Account is not linked to SSO", "jp": "アカウントがSSOに連携されていません"},
+	"MSG_NI0001": {"vn": "Đăng nhập thành công", "en": "Logged in", "jp": "ログインしました"},
+	"MSG_NI0002": {"vn": "Đăng xuất thành công", "en": "Logged out", "jp": "ログアウトしました"},
+	"MSG_NI0003": {"vn": "Làm mới token thành công", "en": "Token refreshed", "jp": "トークンを更新しました"},
+	"MSG_NI0004": {"vn": "Đã gửi mail", "en": "Mail sent", "jp": "メールを送信しました"},
+
+	// Key chưa có mã
+	"CREATE_FAIL":        {"vn": "Tạo mới thất bại", "en": "Failed to create", "jp": "作成に失敗しました"},
+	"AFTER_CREATE_FAIL":  {"vn": "Tạo mới thất bại", "en": "Failed to create", "jp": "作成に失敗しました"},
+	"RESTORE_SUCCESS":    {"vn": "Khôi phục thành công", "en": "Restored successfully", "jp": "復元しました"},
+	"RESTORE_FAIL":       {"vn": "Khôi phục thất bại", "en": "Failed to restore", "jp": "復元に失敗しました"},
+	"ERROR_GET_USERNAME": {"vn": "Không xác định được người dùng đăng nhập", "en": "Could not identify the logged-in user", "jp": "ログインユーザーを特定できません"},
+}
+
+// GetMessageText nội dung của message (kết quả GetMessageCode) theo ngôn ngữ lang.
+// Chưa dịch sang lang thì lấy ngôn ngữ mặc định, rồi tiếng Anh
+func GetMessageText(message, lang string) string {
+	texts, ok := messageText[message]
+	if !ok {
+		return ""
+	}
+
+	for _, code := range []string{lang, DefaultLanguage(), "en"} {
+		if text, ok := texts[code]; ok {
+			return text
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"sort"
+	"strings"
+)
+
+// Mỗi quyền tương ứng một bit trong claim `permission` của access token
+var permissionList = map[string]int{
+	"team:read":    1 << 0,
+	"team:write":   1 << 1,
+	"team:delete":  1 << 2,
+	"team:restore": 1 << 3,
+
+	"group:read":    1 << 4,
+	"group:write":   1 << 5,
+	"group:delete":  1 << 6,
+	"group:restore": 1 << 7,
+
+	"role:write":       1 << 8,
+	"client:write":     1 << 9,
+	"user:unlock":      1 << 10,
+	"user:impersonate": 1 << 11,
+
+	"department:read":    1 << 12,
+	"department:write":   1 << 13,
+	"department:delete":  1 << 14,
+	"department:restore": 1 << 15,
+
+	"employee:read":    1 << 16,
+	"employee:write":   1 << 17,
+	"employee:delete":  1 << 18,
+	"employee:restore": 1 << 19,
+
+	"org:read":  1 << 20,
+	"org:write": 1 << 21,
+
+	"translation:read":  1 << 22,
+	"translation:write": 1 << 23,
+
+	"shift:read":    1 << 24,
+	"shift:write":   1 << 25,
+	"shift:delete":  1 << 26,
+	"shift:restore": 1 << 27,
+
+	"roster:read":  1 << 28,
+	"roster:write": 1 << 29,
+
+	"labor:read":  1 << 30,
+	"labor:write": 1 << 31,
+
+	"attendance:read":  1 << 32,
+	"attendance:write": 1 << 33,
+
+	"site:read":  1 << 34,
+	"site:write": 1 << 35,
+
+	"user:write": 1 << 36, // liên kết tài khoản với SSO
+}
+
+func GetPermissionBit(key string) int {
+	return permissionList[key]
+}
+
+// PermissionMask gộp danh sách quyền thành giá trị claim `permission`
+func PermissionMask(keys []string) int {
+	mask := 0
+	for _, key := range keys {
+		mask |= permissionList[key]
+	}
+
+	return mask
+}
+
+// ReadPermissionMask chỉ giữ lại các quyền :read trong mask (dùng cho session giả danh)
+func ReadPermissionMask(mask int) int {
+	readMask := 0
+	for key, bit := range permissionList {
+		if strings.HasSuffix(key, ":read") {
+			readMask |= bit
+		}
+	}
+
+	return mask & readMask
+}
+
+func PermissionKeys() []string {
+	keys := make([]string, 0, len(permissionList))
+	for key := range permissionList {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
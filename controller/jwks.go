@@ -0,0 +1,23 @@
+package controller
+
+import (
+	"app/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Jwks Công khai các khoá RS256 / EdDSA để service khác xác thực access token
+// @Summary JSON Web Key Set
+// @Description Returns the public keys of the access token key ring
+// @Tags Authen
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /.well-known/jwks.json [get]
+func Jwks(c *fiber.Ctx) error {
+	keys, err := utils.Jwks()
+	if err != nil {
+		return fiber.ErrInternalServerError
+	}
+
+	return c.JSON(fiber.Map{"keys": keys})
+}
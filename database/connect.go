@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/storage/postgres"
 	postgresDriver "gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -14,7 +15,7 @@ import (
 )
 
 var DB *gorm.DB
-var Store *postgres.Storage
+var Store fiber.Storage // postgres, test dùng store trong bộ nhớ (database/testdb)
 
 //--------------------------- TYPE STRUCT -------------------------------
 
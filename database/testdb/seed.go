@@ -0,0 +1,99 @@
+package testdb
+
+import (
+	departmentModel "app/modules/department/model"
+	employeeModel "app/modules/employee/model"
+	groupModel "app/modules/group/model"
+	shiftModel "app/modules/shift/model"
+	teamModel "app/modules/team/model"
+	translationModel "app/modules/translation/model"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Các nhóm model hay dùng, truyền thẳng cho Open (vd: Open(t, OrgModels, EmployeeModels, &model.Site{}))
+var (
+	// Cây tổ chức cũ, kèm tbl_translation vì AfterSave đồng bộ tên sang đó
+	OrgModels      = []interface{}{&translationModel.Translation{}, &departmentModel.Department{}, &groupModel.Group{}, &teamModel.Team{}}
+	EmployeeModels = []interface{}{&employeeModel.Employee{}, &employeeModel.TeamMembership{}}
+	ShiftModels    = []interface{}{&shiftModel.Shift{}, &shiftModel.ShiftChild{}}
+)
+
+// Org một nhánh department → group → team
+type Org struct {
+	Department departmentModel.Department
+	Group      groupModel.Group
+	Team       teamModel.Team
+}
+
+// SeedOrg tạo department "Production", group "Assembly" và team "Shift A"
+func SeedOrg(t *testing.T, db *gorm.DB) Org {
+	t.Helper()
+
+	var org Org
+	org.Department = departmentModel.Department{DepartmentNameVN: "Sản xuất", DepartmentNameEN: "Production", DepartmentNameJP: "製造"}
+	create(t, db, &org.Department)
+	org.Group = groupModel.Group{DepartmentID: int(org.Department.ID), GroupNameVN: "Lắp ráp", GroupNameEN: "Assembly", GroupNameJP: "組立"}
+	create(t, db, &org.Group)
+	org.Team = SeedTeam(t, db, org.Group.ID, "Shift A")
+
+	return org
+}
+
+// SeedTeam tạo team tên name (mọi ngôn ngữ) thuộc group groupID
+func SeedTeam(t *testing.T, db *gorm.DB, groupID uint, name string) teamModel.Team {
+	t.Helper()
+
+	team := teamModel.Team{GroupID: int(groupID), TeamNameVN: name, TeamNameEN: name, TeamNameJP: name}
+	create(t, db, &team)
+
+	return team
+}
+
+// SeedEmployee tạo nhân viên mã code, teamID là team hiện tại (không tạo membership)
+func SeedEmployee(t *testing.T, db *gorm.DB, code string, teamID *uint) employeeModel.Employee {
+	t.Helper()
+
+	employee := employeeModel.Employee{EmployeeCode: code, EmployeeNameVN: code, EmployeeNameEN: code, EmployeeNameJP: code, TeamID: teamID}
+	create(t, db, &employee)
+
+	return employee
+}
+
+// SeedMembership thành viên của team từ ngày validFrom, chưa kết thúc
+func SeedMembership(t *testing.T, db *gorm.DB, employeeID, teamID uint, validFrom time.Time) employeeModel.TeamMembership {
+	t.Helper()
+
+	membership := employeeModel.TeamMembership{EmployeeID: employeeID, TeamID: teamID, Role: employeeModel.RoleMember, ValidFrom: validFrom}
+	create(t, db, &membership)
+
+	return membership
+}
+
+// SeedShift tạo mẫu ca tên name (mọi ngôn ngữ) với các khung giờ "HH:MM-HH:MM", vd: SeedShift(t, db, "Night", "22:00-06:00")
+func SeedShift(t *testing.T, db *gorm.DB, name string, blocks ...string) shiftModel.Shift {
+	t.Helper()
+
+	shift := shiftModel.Shift{ShiftNameVN: name, ShiftNameEN: name, ShiftNameJP: name}
+	for _, block := range blocks {
+		times := strings.Split(block, "-")
+		if len(times) != 2 {
+			t.Fatalf("shift block %q is not HH:MM-HH:MM", block)
+		}
+		shift.Children = append(shift.Children, shiftModel.ShiftChild{TimeStart: times[0], TimeEnd: times[1]})
+	}
+	create(t, db, &shift)
+
+	return shift
+}
+
+func create(t *testing.T, db *gorm.DB, value interface{}) {
+	t.Helper()
+
+	if err := db.Create(value).Error; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,143 @@
+// Package testdb chỉ dùng trong test: thay database.DB bằng sqlite trong bộ nhớ và database.Store bằng map
+package testdb
+
+import (
+	"app/database"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Open tạo CSDL mới cho test t với các bảng của models (có thể truyền cả nhóm như OrgModels, xem seed.go).
+// Thư mục làm việc chuyển sang thư mục tạm có .env rỗng và assets/log (config.Config, core.WriteLog),
+// giá trị cấu hình đặt bằng t.Setenv trước khi gọi
+func Open(t *testing.T, models ...interface{}) *gorm.DB {
+	t.Helper()
+
+	Chdir(t)
+
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	db, err := gorm.Open(sqlite.Open(fmt.Sprintf("file:%s?mode=memory&cache=shared", name)), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(flatten(models)...); err != nil {
+		t.Fatal(err)
+	}
+
+	previousDB, previousStore := database.DB, database.Store
+	database.DB, database.Store = db, NewStore()
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		database.DB, database.Store = previousDB, previousStore
+	})
+
+	return db
+}
+
+// Nhóm model ([]interface{}, vd: OrgModels) được trải ra thành từng model
+func flatten(models []interface{}) []interface{} {
+	flat := []interface{}{}
+	for _, item := range models {
+		if group, ok := item.([]interface{}); ok {
+			flat = append(flat, flatten(group)...)
+		} else {
+			flat = append(flat, item)
+		}
+	}
+
+	return flat
+}
+
+// Chdir chuyển thư mục làm việc sang thư mục tạm có .env rỗng và assets/log, trả lại khi test xong
+func Chdir(t *testing.T) {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "assets", "log"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	previous, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(previous) })
+}
+
+// Store fiber.Storage trong bộ nhớ
+type Store struct {
+	mu      sync.Mutex
+	values  map[string][]byte
+	expires map[string]time.Time
+}
+
+func NewStore() *Store {
+	return &Store{values: map[string][]byte{}, expires: map[string]time.Time{}}
+}
+
+func (s *Store) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if expire, ok := s.expires[key]; ok && time.Now().After(expire) {
+		delete(s.values, key)
+		delete(s.expires, key)
+	}
+
+	return s.values[key], nil
+}
+
+func (s *Store) Set(key string, value []byte, exp time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+	delete(s.expires, key)
+	if exp > 0 {
+		s.expires[key] = time.Now().Add(exp)
+	}
+
+	return nil
+}
+
+func (s *Store) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.values, key)
+	delete(s.expires, key)
+
+	return nil
+}
+
+func (s *Store) Reset() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values, s.expires = map[string][]byte{}, map[string]time.Time{}
+
+	return nil
+}
+
+func (s *Store) Close() error {
+	return nil
+}
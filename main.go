@@ -3,7 +3,10 @@ package main
 import (
 	"app/config"
 	"app/database"
+	"app/middleware"
 	"app/modules"
+	"app/modules/mail"
+	"app/modules/org"
 	"app/routes"
 	"fmt"
 	"log"
@@ -38,6 +41,9 @@ func main() {
 		AllowMethods: "GET,POST,PATCH,PUT,DELETE",
 	}))
 
+	// Language of names and messages (?lang= or Accept-Language)
+	app.Use(middleware.Language)
+
 	// Static folder
 	app.Static("/assets", "./assets")
 
@@ -46,6 +52,12 @@ func main() {
 		return
 	}
 
+	// Send queued mail (tbl_mail_outbox)
+	go mail.StartWorker(time.Minute)
+
+	// Apply scheduled org changes (tbl_org_change_set)
+	go org.StartScheduler(time.Minute)
+
 	// Init router
 	routes.InitRoutes(app)
 	modules.InitRoutes(app)
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"app/config"
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Response dạng config.DataResponse, giữ nguyên data / validate_error và thêm message_text
+type localizedResponse struct {
+	Status        json.RawMessage `json:"status"`
+	Message       string          `json:"message"`
+	MessageText   string          `json:"message_text,omitempty"`
+	Data          json.RawMessage `json:"data"`
+	ValidateError json.RawMessage `json:"validate_error"`
+}
+
+// Language chọn ngôn ngữ theo ?lang= hoặc Accept-Language, lưu vào c.Locals("lang")
+// và thêm message_text (nội dung message theo ngôn ngữ đó) vào response JSON
+func Language(c *fiber.Ctx) error {
+	lang := config.NegotiateLanguage(c.Query("lang"), c.Get("Accept-Language"))
+	c.Locals("lang", lang)
+	c.Vary(fiber.HeaderAcceptLanguage)
+
+	if err := c.Next(); err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(string(c.Response().Header.ContentType()), fiber.MIMEApplicationJSON) {
+		return nil
+	}
+
+	// Chỉ xử lý response có đúng các trường của config.DataResponse
+	var response localizedResponse
+	decoder := json.NewDecoder(bytes.NewReader(c.Response().Body()))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&response); err != nil || len(response.Message) == 0 {
+		return nil
+	}
+
+	response.MessageText = config.GetMessageText(response.Message, lang)
+	if len(response.MessageText) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		return nil
+	}
+	c.Response().SetBodyRaw(body)
+
+	return nil
+}
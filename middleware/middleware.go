@@ -3,33 +3,108 @@ package middleware
 import (
 	"app/config"
 	"app/database"
+	authenModel "app/modules/authen/model"
+	clientModel "app/modules/client/model"
 	"app/utils"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-// Check Key App
+// Check Key App: resolve the client owning x-csv-key and store it in c.Locals("client")
 func AppInfo(c *fiber.Ctx) error {
 	appKey := c.Get("x-csv-key")
 	response := new(config.DataResponse)
 
-	if len(appKey) == 0 || appKey != config.Config("APP_KEY") {
+	client := findClient(appKey)
+	if client == nil {
 		response.Status = false
 		response.Message = config.GetMessageCode("KEY_NOT_FOUND")
 		return c.JSON(response)
 	}
 
+	scope := strings.Split(strings.Trim(c.Path(), "/"), "/")[0]
+	if !client.HasScope(scope) {
+		response.Status = false
+		response.Message = config.GetMessageCode("KEY_SCOPE_DENIED")
+		return c.JSON(response)
+	}
+
+	c.Locals("client", client)
 	return c.Next()
 }
 
+// GetClient lấy client đã được AppInfo xác định
+func GetClient(c *fiber.Ctx) *clientModel.ApiClient {
+	client, _ := c.Locals("client").(*clientModel.ApiClient)
+	return client
+}
+
+func findClient(appKey string) *clientModel.ApiClient {
+	if len(appKey) == 0 {
+		return nil
+	}
+
+	// APP_KEY chỉ dùng để khởi tạo client đầu tiên: đăng nhập và tạo client khi tbl_api_client còn trống
+	// (tính cả client đã thu hồi / xoá), để trống để tắt
+	legacyKey := config.Config("APP_KEY")
+	if len(legacyKey) > 0 && appKey == legacyKey {
+		var count int64
+		if err := database.DB.Unscoped().Model(&clientModel.ApiClient{}).Count(&count).Error; err != nil || count > 0 {
+			return nil
+		}
+		return &clientModel.ApiClient{ClientName: "bootstrap", Scopes: "authen,client"}
+	}
+
+	var client clientModel.ApiClient
+	if err := database.DB.Where("key_hash = ?", utils.HashKey(appKey)).First(&client).Error; err != nil || !client.Active() {
+		return nil
+	}
+
+	// Chỉ ghi last_used_at tối đa mỗi phút một lần
+	now := time.Now()
+	if client.LastUsedAt == nil || now.Sub(*client.LastUsedAt) > time.Minute {
+		database.DB.Model(&client).UpdateColumn("last_used_at", now)
+		client.LastUsedAt = &now
+	}
+
+	return &client
+}
+
 // Authen
 func AppAuthen(c *fiber.Ctx) error {
-	store := database.Store
-	isError := 0
 	response := new(config.DataResponse)
 
-	// Check token valid
+	tokenData, ok := CheckSession(c)
+	if !ok {
+		response.Status = false
+		response.Message = config.GetMessageCode("TOKEN_INCORRECT")
+		return c.JSON(response)
+	}
+
+	if !tokenData.Impersonated() {
+		return c.Next()
+	}
+
+	// Session giả danh: ghi lại mọi request kèm admin thật
+	err := c.Next()
+	database.DB.Create(&authenModel.AuditLog{
+		SessionID:  tokenData.SessionID,
+		Actor:      tokenData.Actor,
+		Username:   tokenData.Username,
+		Method:     c.Method(),
+		Path:       c.OriginalURL(),
+		StatusCode: c.Response().StatusCode(),
+	})
+
+	return err
+}
+
+// NoImpersonation chặn các thao tác trên chính tài khoản (email, TOTP, session...) khi đang giả danh
+func NoImpersonation(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
 	tokenData, err := utils.ExtractTokenData(c)
 	if err != nil {
 		response.Status = false
@@ -37,17 +112,53 @@ func AppAuthen(c *fiber.Ctx) error {
 		return c.JSON(response)
 	}
 
+	if tokenData.Impersonated() {
+		response.Status = false
+		response.Message = config.GetMessageCode("IMPERSONATION_DENIED")
+		return c.JSON(response)
+	}
+
+	return c.Next()
+}
+
+// CheckSession kiểm tra token hợp lệ và còn session trong database.Store
+// (one session per device, keyed by the `sid` claim)
+func CheckSession(c *fiber.Ctx) (*utils.TokenData, bool) {
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		return nil, false
+	}
+
 	// Check session Exist and comparse token
-	sess, err := store.Get(tokenData.Username)
+	sess, err := database.Store.Get(tokenData.SessionID)
 	authen := strings.Split(c.Get("x-csv-token"), " ")
 	if err != nil || len(sess) == 0 || string(sess) != authen[1] {
-		isError = 1
+		return nil, false
 	}
 
-	if isError == 1 {
-		response.Status = false
-		response.Message = config.GetMessageCode("TOKEN_INCORRECT")
-		return c.JSON(response)
+	return tokenData, true
+}
+
+// Require kiểm tra quyền trong claim `permission` của token.
+// Quyền được tính lúc đăng nhập nên thay đổi role chỉ có hiệu lực sau khi login / refresh lại.
+func Require(permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		response := new(config.DataResponse)
+
+		tokenData, err := utils.ExtractTokenData(c)
+		if err != nil {
+			response.Status = false
+			response.Message = config.GetMessageCode("TOKEN_INCORRECT")
+			return c.JSON(response)
+		}
+
+		bit := config.GetPermissionBit(permission)
+		if bit == 0 || tokenData.Permission&bit == 0 {
+			response.Status = false
+			response.Message = config.GetMessageCode("PERMISSION_DENIED")
+			return c.JSON(response)
+		}
+
+		return c.Next()
 	}
-	return c.Next()
 }
@@ -0,0 +1,68 @@
+package attendance
+
+import (
+	"app/config"
+	"app/modules/roster"
+	rosterModel "app/modules/roster/model"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FindShift ca của nhân viên đang diễn ra lúc at: từ ATTENDANCE_EARLY_MINUTES phút trước giờ bắt đầu
+// đến ATTENDANCE_LATE_MINUTES phút sau giờ kết thúc. shiftID > 0 thì chỉ xét mẫu ca đó.
+// Nhiều ca cùng khớp thì lấy ca có giờ bắt đầu gần at nhất, nil nếu không có ca nào
+func FindShift(db *gorm.DB, employeeID, shiftID uint, at time.Time) (*rosterModel.ShiftInstance, error) {
+	// Ca qua đêm của hôm trước vẫn có thể đang diễn ra
+	date := at.In(roster.Location())
+	instances, err := roster.Expand(db, date.AddDate(0, 0, -1), date, employeeID, 0, "")
+	if err != nil {
+		return nil, err
+	}
+
+	early := time.Duration(envInt("ATTENDANCE_EARLY_MINUTES", 60)) * time.Minute
+	late := time.Duration(envInt("ATTENDANCE_LATE_MINUTES", 120)) * time.Minute
+
+	var found *rosterModel.ShiftInstance
+	for i, instance := range instances {
+		if shiftID > 0 && instance.ShiftID != shiftID {
+			continue
+		}
+		if at.Before(instance.Start.Add(-early)) || at.After(instance.End.Add(late)) {
+			continue
+		}
+		if found == nil || distance(instance.Start, at) < distance(found.Start, at) {
+			found = &instances[i]
+		}
+	}
+
+	return found, nil
+}
+
+// TokenTTL số phút payload chấm công còn hiệu lực kể từ lúc ký (ATTENDANCE_TOKEN_TTL, mặc định 2)
+func TokenTTL() int {
+	minutes := envInt("ATTENDANCE_TOKEN_TTL", 2)
+	if minutes == 0 {
+		return 2
+	}
+
+	return minutes
+}
+
+func distance(a, b time.Time) time.Duration {
+	if a.After(b) {
+		return a.Sub(b)
+	}
+
+	return b.Sub(a)
+}
+
+func envInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(config.Config(key))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+
+	return value
+}
@@ -0,0 +1,62 @@
+package attendance
+
+import (
+	"app/database/testdb"
+	rosterModel "app/modules/roster/model"
+	"testing"
+	"time"
+)
+
+func TestFindShift(t *testing.T) {
+	t.Setenv("APP_TIME_ZONE", "UTC")
+	t.Setenv("ATTENDANCE_EARLY_MINUTES", "60")
+	t.Setenv("ATTENDANCE_LATE_MINUTES", "120")
+	db := testdb.Open(t, testdb.OrgModels, testdb.EmployeeModels, testdb.ShiftModels, &rosterModel.Roster{}, &rosterModel.RosterException{})
+
+	start, _ := time.Parse("2006-01-02", "2024-01-01")
+	day := testdb.SeedEmployee(t, db, "1001", nil)
+	night := testdb.SeedEmployee(t, db, "1002", nil)
+	morning := testdb.SeedShift(t, db, "Morning", "08:00-17:00")
+	evening := testdb.SeedShift(t, db, "Evening", "14:00-22:00")
+	overnight := testdb.SeedShift(t, db, "Night", "22:00-06:00")
+	db.Create(&[]rosterModel.Roster{
+		{ShiftID: morning.ID, EmployeeID: &day.ID, RRule: "FREQ=DAILY", StartDate: start},
+		{ShiftID: evening.ID, EmployeeID: &day.ID, RRule: "FREQ=DAILY", StartDate: start},
+		{ShiftID: overnight.ID, EmployeeID: &night.ID, RRule: "FREQ=DAILY", StartDate: start},
+	})
+
+	cases := []struct {
+		name       string
+		employeeID uint
+		shiftID    uint
+		at         string
+		want       uint   // mẫu ca tìm được, 0 là không có ca
+		date       string // ngày của ca
+	}{
+		{"too early", day.ID, 0, "2024-01-03 06:59", 0, ""},
+		{"early window", day.ID, 0, "2024-01-03 07:00", morning.ID, "2024-01-03"},
+		{"closest start", day.ID, 0, "2024-01-03 13:30", evening.ID, "2024-01-03"},
+		{"chosen shift", day.ID, morning.ID, "2024-01-03 13:30", morning.ID, "2024-01-03"},
+		{"late window", day.ID, 0, "2024-01-04 00:00", evening.ID, "2024-01-03"},
+		{"too late", day.ID, 0, "2024-01-04 00:01", 0, ""},
+		{"other shift chosen", night.ID, morning.ID, "2024-01-03 23:00", 0, ""},
+		// Ca qua đêm: chấm ra sáng hôm sau vẫn là ca của ngày bắt đầu
+		{"overnight next morning", night.ID, 0, "2024-01-04 07:30", overnight.ID, "2024-01-03"},
+		{"overnight too late", night.ID, 0, "2024-01-04 08:01", 0, ""},
+	}
+
+	for _, item := range cases {
+		at, _ := time.Parse("2006-01-02 15:04", item.at)
+		instance, err := FindShift(db, item.employeeID, item.shiftID, at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, date := uint(0), ""
+		if instance != nil {
+			got, date = instance.ShiftID, instance.Date
+		}
+		if got != item.want || date != item.date {
+			t.Errorf("%s: got shift %d on %q, want %d on %q", item.name, got, date, item.want, item.date)
+		}
+	}
+}
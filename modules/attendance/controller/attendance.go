@@ -0,0 +1,411 @@
+package controller
+
+import (
+	"app/config"
+	"app/database"
+	"app/modules/attendance"
+	"app/modules/attendance/model"
+	employeeModel "app/modules/employee/model"
+	"app/modules/site"
+	"app/utils"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetAttendance Lấy danh sách chấm công
+// @Summary Get Attendance events
+// @Description Returns the check-in / check-out events whose shift date is between from and to
+// @Tags Attendance
+// @Accept json
+// @Produce json
+// @Param from query string true "First shift date (YYYY-MM-DD)"
+// @Param to query string true "Last shift date (YYYY-MM-DD)"
+// @Param employee_id query int false "Only events of this Employee"
+// @Param geo_status query string false "inside | unchecked | review | approved | rejected"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /attendance [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetAttendance(c *fiber.Ctx) error {
+	return listAttendance(c, uint(c.QueryInt("employee_id")))
+}
+
+// GetMyAttendance Lấy danh sách chấm công của người đăng nhập
+// @Summary Get my Attendance events
+// @Description Returns the check-in / check-out events of the logged-in Employee whose shift date is between from and to
+// @Tags Attendance
+// @Accept json
+// @Produce json
+// @Param from query string true "First shift date (YYYY-MM-DD)"
+// @Param to query string true "Last shift date (YYYY-MM-DD)"
+// @Param geo_status query string false "inside | unchecked | review | approved | rejected"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /attendance/me [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetMyAttendance(c *fiber.Ctx) error {
+	employee, err := currentEmployee(c)
+	if err != nil {
+		response := new(config.DataResponse)
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	return listAttendance(c, employee.ID)
+}
+
+// CreateAttendanceToken Cấp payload QR chấm công
+// @Summary Get a signed Attendance payload
+// @Description Signs the logged-in Employee, the current time, the coordinates and the shift going on now (see utils.EncodeDataTokenMobile).
+// @Description The mobile app shows it as a QR code and sends it to /attendance/check-in or /attendance/check-out. It can be used once, within ATTENDANCE_TOKEN_TTL minutes
+// @Tags Attendance
+// @Accept json
+// @Produce json
+// @Param body body model.AttendanceTokenModel true "Coordinates of the device, optional shift_id when several shifts are going on"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /attendance/token [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func CreateAttendanceToken(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.AttendanceTokenModel)
+	if err := c.BodyParser(payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	vItem := map[string]string{"Coordinates": payload.Coordinates}
+	errors := utils.RequireCheck([]string{"Coordinates"}, vItem, map[string]string{})
+	errors = utils.MaxLengthCheck([]string{"Coordinates:50"}, vItem, errors)
+	if len(errors) > 0 {
+		response.Status = false
+		response.Message = config.GetMessageCode("MISSING_FIELDS")
+		response.ValidateError = errors
+		return c.JSON(response)
+	}
+
+	employee, err := currentEmployee(c)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	now := time.Now()
+	instance, err := attendance.FindShift(database.DB, employee.ID, payload.ShiftID, now)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+	if instance == nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NO_SHIFT")
+		return c.JSON(response)
+	}
+
+	token, err := utils.EncodeDataTokenMobile(strconv.FormatUint(uint64(employee.ID), 10), now.Unix(), payload.Coordinates, int(instance.ShiftID), attendance.TokenTTL())
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Data = map[string]interface{}{
+		"data":  token,
+		"shift": instance,
+	}
+	response.Status = true
+	response.Message = config.GetMessageCode("CREATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// CheckIn Chấm công vào ca
+// @Summary Check in
+// @Description Verifies the signed payload of the logged-in Employee (at most ATTENDANCE_TOKEN_TTL minutes old), binds it to the shift going on now and stores a check_in event at the time it was received.
+// @Description The coordinates are checked against the Sites of the Employee's Team or Department: outside every Site, the policy of the nearest one rejects the check-in or stores it with geo_status review
+// @Tags Attendance
+// @Accept json
+// @Produce json
+// @Param body body model.AttendanceModel true "Payload from /attendance/token"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /attendance/check-in [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func CheckIn(c *fiber.Ctx) error {
+	return attend(c, model.EventCheckIn)
+}
+
+// CheckOut Chấm công ra ca
+// @Summary Check out
+// @Description Like check-in (also the Site check), the shift must already have a check_in event
+// @Tags Attendance
+// @Accept json
+// @Produce json
+// @Param body body model.AttendanceModel true "Payload from /attendance/token"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /attendance/check-out [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func CheckOut(c *fiber.Ctx) error {
+	return attend(c, model.EventCheckOut)
+}
+
+// ReviewAttendance duyệt chấm công ngoài địa điểm
+// @Summary Review Attendance events
+// @Description Approves or rejects events stored with geo_status review (outside every Site with policy review)
+// @Tags Attendance
+// @Accept json
+// @Produce json
+// @Param body body []model.ReviewAttendanceModel true "Reviews"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /attendance/review [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func ReviewAttendance(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.ReviewAttendanceModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		var event model.AttendanceEvent
+		if err := tx.Where("geo_status = ?", site.StatusReview).First(&event, item.AttendanceEventID).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+			return c.JSON(response)
+		}
+
+		now := time.Now()
+		event.GeoStatus = model.GeoRejected
+		if item.Approved {
+			event.GeoStatus = model.GeoApproved
+		}
+		event.ReviewedBy = getUsername(c)
+		event.ReviewedAt = &now
+
+		if err := tx.Model(&event).Select("geo_status", "reviewed_by", "reviewed_at").Updates(&event).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			return c.JSON(response)
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+func attend(c *fiber.Ctx, eventType string) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.AttendanceModel)
+	if err := c.BodyParser(payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	// Giờ chấm công là lúc nhận, payload chỉ dùng được trong ATTENDANCE_TOKEN_TTL phút sau khi ký
+	eventTime := time.Now()
+	data, err := utils.DecodeData(payload.Data)
+	if err == nil && eventTime.Sub(time.Unix(data.DateInSeconds, 0)) > time.Duration(attendance.TokenTTL())*time.Minute {
+		err = fmt.Errorf("data is expired")
+	}
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("MISSING_FIELDS")
+		response.ValidateError = map[string]string{"Data": config.GetMessageCode("VALUE_INVALID")}
+		return c.JSON(response)
+	}
+
+	// Payload phải là của chính người đăng nhập
+	employee, err := currentEmployee(c)
+	if err != nil || data.EmployeeId != strconv.FormatUint(uint64(employee.ID), 10) {
+		response.Status = false
+		response.Message = config.GetMessageCode("PERMISSION_DENIED")
+		return c.JSON(response)
+	}
+
+	instance, err := attendance.FindShift(database.DB, employee.ID, uint(data.ShiftId), eventTime)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+	if instance == nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NO_SHIFT")
+		return c.JSON(response)
+	}
+	workDate, _ := time.Parse("2006-01-02", instance.Date)
+
+	// Vị trí so với các địa điểm của nhân viên, ngoài địa điểm thì từ chối hoặc chờ duyệt theo policy
+	located, err := site.Locate(database.DB, employee.ID, data.Coordinates)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+	if located.Status == site.StatusReject {
+		response.Data = located
+		response.Status = false
+		response.Message = config.GetMessageCode("OUTSIDE_SITE")
+		response.ValidateError = map[string]string{"Coordinates": config.GetMessageCode("OUTSIDE_SITE")}
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	var events []model.AttendanceEvent
+	if err := tx.Where("employee_id = ? AND roster_id = ? AND work_date = ?", employee.ID, instance.RosterID, workDate).Find(&events).Error; err != nil {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+	var checkIn *model.AttendanceEvent
+	for i, event := range events {
+		if event.EventType == eventType {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("ATTENDANCE_EXISTS")
+			return c.JSON(response)
+		}
+		if event.EventType == model.EventCheckIn {
+			checkIn = &events[i]
+		}
+	}
+	if eventType == model.EventCheckOut && (checkIn == nil || eventTime.Before(checkIn.EventTime)) {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_CHECKED_IN")
+		return c.JSON(response)
+	}
+
+	event := model.AttendanceEvent{
+		EmployeeID:  employee.ID,
+		RosterID:    instance.RosterID,
+		WorkDate:    workDate,
+		EventType:   eventType,
+		ShiftID:     instance.ShiftID,
+		ShiftStart:  instance.Start,
+		ShiftEnd:    instance.End,
+		EventTime:   eventTime,
+		Coordinates: data.Coordinates,
+		GeoStatus:   located.Status,
+		SiteID:      located.SiteID,
+		Distance:    located.Distance,
+		TokenID:     data.TokenID,
+		IPAddress:   c.IP(),
+	}
+
+	// Payload đã dùng (kể cả cho loại chấm công khác) thì không nhận lại
+	var used int64
+	if err := tx.Model(&model.AttendanceEvent{}).Where("token_id = ?", data.TokenID).Count(&used).Error; err != nil || used > 0 {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("ATTENDANCE_EXISTS")
+		return c.JSON(response)
+	}
+
+	if err := tx.Create(&event).Error; err != nil {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("CREATE_FAIL")
+		return c.JSON(response)
+	}
+
+	tx.Commit()
+
+	response.Data = event
+	response.Status = true
+	response.Message = config.GetMessageCode("CREATE_SUCCESS")
+	return c.JSON(response)
+}
+
+func listAttendance(c *fiber.Ctx, employeeID uint) error {
+	response := new(config.DataResponse)
+
+	vItem := map[string]string{"From": c.Query("from"), "To": c.Query("to")}
+	errors := utils.RequireCheck([]string{"From", "To"}, vItem, map[string]string{})
+	errors = utils.DateFormatCheck([]string{"From", "To"}, vItem, errors)
+	if len(errors) > 0 {
+		response.Status = false
+		response.Message = config.GetMessageCode("MISSING_FIELDS")
+		response.ValidateError = errors
+		return c.JSON(response)
+	}
+
+	query := database.DB.Preload("Employee.Translations").Preload("Shift.Translations").Preload("Site").
+		Where("work_date BETWEEN ? AND ?", vItem["From"], vItem["To"])
+	if employeeID > 0 {
+		query = query.Where("employee_id = ?", employeeID)
+	}
+	if geoStatus := c.Query("geo_status"); len(geoStatus) > 0 {
+		query = query.Where("geo_status = ?", geoStatus)
+	}
+
+	var events []model.AttendanceEvent
+	if err := query.Order("event_time, attendance_event_id").Find(&events).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	for i := range events {
+		events[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = events
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// Nhân viên của người đăng nhập (mã nhân viên trùng username), chưa nghỉ việc
+func currentEmployee(c *fiber.Ctx) (*employeeModel.Employee, error) {
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var employee employeeModel.Employee
+	if err := database.DB.Where("employee_code = ? AND status <> ?", tokenData.Username, employeeModel.StatusTerminated).First(&employee).Error; err != nil {
+		return nil, err
+	}
+
+	return &employee, nil
+}
+
+func getUsername(c *fiber.Ctx) string {
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		return ""
+	}
+
+	return tokenData.Username
+}
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"app/config"
+	"app/database/testdb"
+	"app/modules/attendance/model"
+	rosterModel "app/modules/roster/model"
+	siteModel "app/modules/site/model"
+	"app/utils"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// nightZone đặt APP_TIME_ZONE (Etc/GMT±N) sao cho giờ địa phương hiện tại là khoảng 2 giờ sáng,
+// giữa ca đêm 22:00-06:00 bắt đầu từ hôm qua
+func nightZone(t *testing.T) *time.Location {
+	offset := (2 - time.Now().UTC().Hour() + 24) % 24
+	if offset > 14 {
+		offset -= 24
+	}
+	name := "UTC"
+	switch {
+	case offset > 0:
+		name = fmt.Sprintf("Etc/GMT-%d", offset)
+	case offset < 0:
+		name = fmt.Sprintf("Etc/GMT+%d", -offset)
+	}
+	t.Setenv("APP_TIME_ZONE", name)
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skip(err)
+	}
+
+	return location
+}
+
+func TestAttend(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	t.Setenv("JWT_EXPIRED_TIME", "15")
+	t.Setenv("JWT_DATA_SECRET_KEY", "test-data-secret")
+	t.Setenv("ATTENDANCE_TOKEN_TTL", "2")
+	location := nightZone(t)
+	db := testdb.Open(t, testdb.OrgModels, testdb.EmployeeModels, testdb.ShiftModels, &rosterModel.Roster{}, &rosterModel.RosterException{},
+		&siteModel.Site{}, &siteModel.SiteAssignment{}, &model.AttendanceEvent{})
+
+	team := testdb.SeedOrg(t, db).Team
+	employee := testdb.SeedEmployee(t, db, "1001", &team.ID)
+	other := testdb.SeedEmployee(t, db, "1002", &team.ID)
+	yesterday := time.Now().In(location).AddDate(0, 0, -1).Format("2006-01-02")
+	start, _ := time.Parse("2006-01-02", yesterday)
+	testdb.SeedMembership(t, db, employee.ID, team.ID, start.AddDate(0, 0, -7))
+	testdb.SeedMembership(t, db, other.ID, team.ID, start.AddDate(0, 0, -7))
+	night := testdb.SeedShift(t, db, "Night", "22:00-06:00")
+	db.Create(&rosterModel.Roster{ShiftID: night.ID, TeamID: &team.ID, RRule: "FREQ=DAILY", StartDate: start.AddDate(0, 0, -7)})
+
+	accessToken, err := utils.GenerateAccessToken("session", employee.EmployeeCode, "test", "127.0.0.1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New()
+	app.Post("/attendance/check-in", CheckIn)
+	app.Post("/attendance/check-out", CheckOut)
+
+	// Payload ký lúc signedAt cho nhân viên employeeID
+	payload := func(employeeID uint, signedAt time.Time) string {
+		data, err := utils.EncodeDataTokenMobile(fmt.Sprint(employeeID), signedAt.Unix(), "10,106", int(night.ID), 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	send := func(target, data string) (config.DataResponse, model.AttendanceEvent) {
+		t.Helper()
+
+		request := httptest.NewRequest(http.MethodPost, target, strings.NewReader(`{"data":"`+data+`"}`))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("x-csv-token", "Bearer "+accessToken)
+		resp, err := app.Test(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		var response config.DataResponse
+		var event model.AttendanceEvent
+		json.NewDecoder(resp.Body).Decode(&response)
+		if encoded, err := json.Marshal(response.Data); err == nil {
+			json.Unmarshal(encoded, &event)
+		}
+		return response, event
+	}
+	fail := func(name string, response config.DataResponse, message string) {
+		t.Helper()
+
+		if response.Status || response.Message != config.GetMessageCode(message) {
+			t.Errorf("%s: got %v %s %v, want %s", name, response.Status, response.Message, response.ValidateError, message)
+		}
+	}
+
+	response, _ := send("/attendance/check-out", payload(employee.ID, time.Now()))
+	fail("check-out before check-in", response, "NOT_CHECKED_IN")
+
+	response, _ = send("/attendance/check-in", payload(other.ID, time.Now()))
+	fail("payload of another employee", response, "PERMISSION_DENIED")
+
+	response, _ = send("/attendance/check-in", payload(employee.ID, time.Now().Add(-3*time.Minute)))
+	fail("payload older than the ttl", response, "MISSING_FIELDS")
+
+	// Giờ chấm công là lúc nhận, không phải lúc ký payload
+	signedAt := time.Now().Add(-time.Minute)
+	checkIn := payload(employee.ID, signedAt)
+	response, event := send("/attendance/check-in", checkIn)
+	if !response.Status || event.EventType != model.EventCheckIn || event.WorkDate.Format("2006-01-02") != yesterday {
+		t.Fatalf("check-in: got %v %s %+v, want a check-in of the shift on %s", response.Status, response.Message, event, yesterday)
+	}
+	if event.EventTime.Before(signedAt.Add(59 * time.Second)) {
+		t.Errorf("check-in at %s, want the time it was received, not %s", event.EventTime, signedAt)
+	}
+
+	response, _ = send("/attendance/check-out", checkIn)
+	fail("replayed jti", response, "ATTENDANCE_EXISTS")
+
+	response, _ = send("/attendance/check-in", payload(employee.ID, time.Now()))
+	fail("second check-in", response, "ATTENDANCE_EXISTS")
+
+	// Ca đêm bắt đầu hôm qua, chấm ra hôm nay vẫn thuộc ca của hôm qua
+	response, event = send("/attendance/check-out", payload(employee.ID, time.Now()))
+	if !response.Status || event.EventType != model.EventCheckOut || event.WorkDate.Format("2006-01-02") != yesterday ||
+		event.EventTime.In(location).Format("2006-01-02") == yesterday {
+		t.Fatalf("overnight check-out: got %v %s %+v, want a check-out of the shift on %s", response.Status, response.Message, event, yesterday)
+	}
+
+	var count int64
+	db.Model(&model.AttendanceEvent{}).Count(&count)
+	if count != 2 {
+		t.Fatalf("got %d events, want 2", count)
+	}
+}
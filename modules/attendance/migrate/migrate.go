@@ -0,0 +1,14 @@
+package attendanceMigrate
+
+import (
+	"app/database"
+	model "app/modules/attendance/model"
+)
+
+func MigrateTbl() bool {
+	db := database.DB
+
+	db.AutoMigrate(&model.AttendanceEvent{})
+
+	return true
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"time"
+
+	employeeModel "app/modules/employee/model"
+	shiftModel "app/modules/shift/model"
+	siteModel "app/modules/site/model"
+)
+
+// Loại sự kiện chấm công
+const (
+	EventCheckIn  = "check_in"
+	EventCheckOut = "check_out"
+)
+
+// Kết quả duyệt chấm công ngoài địa điểm (GeoStatus review)
+const (
+	GeoApproved = "approved"
+	GeoRejected = "rejected"
+)
+
+// AttendanceEvent một lần chấm công vào / ra bằng payload QR (utils.EncodeDataTokenMobile), gắn với một ca cụ thể của roster.
+// WorkDate là ngày của ca (ca qua đêm chấm ra ngày hôm sau vẫn là ngày bắt đầu ca), mỗi ca chỉ một lần vào và một lần ra
+type AttendanceEvent struct {
+	ID          uint                    `gorm:"primarykey;column:attendance_event_id;<-:create" json:"attendance_event_id"`
+	EmployeeID  uint                    `gorm:"column:employee_id;not null;uniqueIndex:idx_attendance_event" json:"employee_id"`
+	Employee    *employeeModel.Employee `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"employee,omitempty"`
+	RosterID    uint                    `gorm:"column:roster_id;not null;uniqueIndex:idx_attendance_event" json:"roster_id"`
+	WorkDate    time.Time               `gorm:"column:work_date;type:date;not null;uniqueIndex:idx_attendance_event;index" json:"work_date"`
+	EventType   string                  `gorm:"column:event_type;size:10;not null;uniqueIndex:idx_attendance_event" json:"event_type"`
+	ShiftID     uint                    `gorm:"column:shift_id;not null;index" json:"shift_id"`
+	Shift       *shiftModel.Shift       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"shift,omitempty"`
+	ShiftStart  time.Time               `gorm:"column:shift_start;not null" json:"shift_start"`
+	ShiftEnd    time.Time               `gorm:"column:shift_end;not null" json:"shift_end"`
+	EventTime   time.Time               `gorm:"column:event_time;not null" json:"event_time"` // thời điểm server nhận chấm công
+	Coordinates string                  `gorm:"column:coordinates;size:50" json:"coordinates"`
+	GeoStatus   string                  `gorm:"column:geo_status;size:10;not null;default:unchecked;index" json:"geo_status"` // inside / unchecked / review (xem site.Status...), approved / rejected sau khi duyệt
+	SiteID      *uint                   `gorm:"column:site_id;index" json:"site_id"`                                          // địa điểm gần nhất
+	Site        *siteModel.Site         `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"site,omitempty"`
+	Distance    float64                 `gorm:"column:distance;not null;default:0" json:"distance"` // mét tới địa điểm gần nhất, 0 nếu ở trong
+	ReviewedBy  string                  `gorm:"column:reviewed_by;size:15" json:"reviewed_by"`
+	ReviewedAt  *time.Time              `json:"reviewed_at"`
+	TokenID     string                  `gorm:"column:token_id;size:32;not null;uniqueIndex" json:"-"` // jti của payload, không dùng lại được
+	IPAddress   string                  `gorm:"column:ip_address;size:50" json:"ip_address"`
+	CreatedAt   time.Time               `json:"created_at"`
+}
+
+// Localize điền Name của nhân viên và mẫu ca đã preload
+func (e *AttendanceEvent) Localize(lang string, all bool) {
+	if e.Employee != nil {
+		e.Employee.Localize(lang, all)
+	}
+	if e.Shift != nil {
+		e.Shift.Localize(lang, all)
+	}
+}
+
+type AttendanceTokenModel struct {
+	Coordinates string `json:"coordinates" validate:"required"`
+	ShiftID     uint   `json:"shift_id"`
+}
+
+type AttendanceModel struct {
+	Data string `json:"data" validate:"required"`
+}
+
+type ReviewAttendanceModel struct {
+	AttendanceEventID uint `json:"attendance_event_id" validate:"required"`
+	Approved          bool `json:"approved"`
+}
+
+// Tên bảng trong CSDL
+func (AttendanceEvent) TableName() string {
+	return "tbl_attendance_event"
+}
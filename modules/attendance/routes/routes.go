@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"app/middleware"
+
+	"app/modules/attendance/controller"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func InitAttendanceRoutes(app *fiber.App) {
+	attendance := app.Group("/attendance", middleware.AppInfo, middleware.AppAuthen)
+
+	attendance.Get("/", middleware.Require("attendance:read"), controller.GetAttendance)
+	attendance.Get("/me", controller.GetMyAttendance)
+	attendance.Put("/review", middleware.Require("attendance:write"), controller.ReviewAttendance)
+
+	// Nhân viên tự chấm công, không cho phép khi đang giả danh
+	attendance.Post("/token", middleware.NoImpersonation, controller.CreateAttendanceToken)
+	attendance.Post("/check-in", middleware.NoImpersonation, controller.CheckIn)
+	attendance.Post("/check-out", middleware.NoImpersonation, controller.CheckOut)
+}
@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"app/config"
+	"app/core"
+	"app/database"
+	"app/modules/authen/model"
+	"app/utils"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Login Đăng nhập bằng username / password
+// @Summary Login
+// @Description Verifies the credentials, issues an access token and a refresh token and stores the session.
+// @Description Accounts with TOTP get an mfa_token instead and finish with /authen/login/totp
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Param body body model.LoginModel true "Login information"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/login [post]
+// @Security ApiKeyAuth
+func Login(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.LoginModel)
+	if err := c.BodyParser(payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	listCheck := []string{"Username", "Password"}
+	vItem := map[string]string{
+		"Username": payload.Username,
+		"Password": payload.Password,
+	}
+	errors := utils.RequireCheck(listCheck, vItem, map[string]string{})
+
+	if len(errors) > 0 {
+		response.Status = false
+		response.Message = config.GetMessageCode("MISSING_FIELDS")
+		response.ValidateError = errors
+		return c.JSON(response)
+	}
+
+	if lockedUntil := loginLocked(payload.Username, c.IP()); lockedUntil != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("ACCOUNT_LOCKED")
+		response.Data = model.LockModel{LockedUntil: *lockedUntil}
+		return c.JSON(response)
+	}
+
+	var user model.User
+	results := database.DB.Where("username = ?", payload.Username).First(&user)
+	if results.Error != nil || !utils.CheckPassword(user.Password, payload.Password) {
+		loginFailed(payload.Username, c.IP())
+		response.Status = false
+		response.Message = config.GetMessageCode("USERNAME_PASSWORD_INCORRECT")
+		return c.JSON(response)
+	}
+
+	return completeLogin(c, &user)
+}
+
+// Logout Đăng xuất, xoá session hiện tại
+// @Summary Logout
+// @Description Removes the session of the current token only, other devices stay logged in
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/logout [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func Logout(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("TOKEN_INCORRECT")
+		return c.JSON(response)
+	}
+
+	if err := revokeSession(tokenData.SessionID); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("LOGOUT_SUCCESS")
+	return c.JSON(response)
+}
+
+// Refresh Đổi refresh token lấy cặp access token / refresh token mới
+// @Summary Refresh token
+// @Description Exchanges a single-use refresh token for a new access token and a new refresh token.
+// @Description Presenting an already-used refresh token revokes the whole token family and its session
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Param body body model.RefreshModel true "Refresh token"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/refresh [post]
+// @Security ApiKeyAuth
+func Refresh(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.RefreshModel)
+	if err := c.BodyParser(payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	var refreshToken model.RefreshToken
+	results := database.DB.Where("token_hash = ?", utils.HashKey(payload.RefreshToken)).First(&refreshToken)
+	if results.Error != nil || refreshToken.RevokedAt != nil || refreshToken.ExpiresAt.Before(time.Now()) {
+		response.Status = false
+		response.Message = config.GetMessageCode("TOKEN_INCORRECT")
+		return c.JSON(response)
+	}
+
+	// Đánh dấu đã dùng, chỉ một request được đổi token thành công
+	used := database.DB.Model(&refreshToken).Where("used_at IS NULL").Update("used_at", time.Now())
+	if used.Error != nil || used.RowsAffected == 0 {
+		core.WriteLog("WARNING | REFRESH TOKEN REUSED | " + refreshToken.Username + " | family " + refreshToken.FamilyID)
+		revokeFamily(refreshToken.FamilyID)
+		response.Status = false
+		response.Message = config.GetMessageCode("REFRESH_TOKEN_REUSED")
+		return c.JSON(response)
+	}
+
+	token, err := createSession(c, refreshToken.Username, refreshToken.SessionID, refreshToken.FamilyID)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Data = token
+	response.Status = true
+	response.Message = config.GetMessageCode("REFRESH_SUCCESS")
+	return c.JSON(response)
+}
+
+// Gộp quyền của tất cả role mà user đang có thành claim `permission`
+func userPermission(username string) (int, error) {
+	var codes []string
+	results := database.DB.Model(&model.RolePermission{}).
+		Joins("JOIN tbl_role ON tbl_role.role_id = tbl_role_permission.role_id AND tbl_role.deleted_at IS NULL").
+		Joins("JOIN tbl_user_role ON tbl_user_role.role_id = tbl_role.role_id").
+		Joins("JOIN tbl_user ON tbl_user.user_id = tbl_user_role.user_id").
+		Where("tbl_user.username = ?", username).
+		Distinct().
+		Pluck("tbl_role_permission.permission_code", &codes)
+	if results.Error != nil {
+		return 0, results.Error
+	}
+
+	return config.PermissionMask(codes), nil
+}
+
+func getUsername(c *fiber.Ctx) string {
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		return ""
+	}
+
+	return tokenData.Username
+}
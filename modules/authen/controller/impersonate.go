@@ -0,0 +1,145 @@
+package controller
+
+import (
+	"app/config"
+	"app/core"
+	"app/database"
+	"app/modules/authen/model"
+	"app/utils"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Impersonate Admin đăng nhập dưới danh nghĩa user khác ("act as")
+// @Summary Act as another user
+// @Description Issues a short-lived access token for the given user that also carries the real admin (claim `act`).
+// @Description The session is read-only (only :read permissions of the user), has no refresh token, and every request is written to the audit log.
+// @Description Call /authen/logout with the issued token to end it
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Param body body model.ImpersonateModel true "Username to act as"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/impersonate [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func Impersonate(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.ImpersonateModel)
+	if err := c.BodyParser(payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("TOKEN_INCORRECT")
+		return c.JSON(response)
+	}
+
+	if payload.Username == tokenData.Username {
+		response.Status = false
+		response.Message = config.GetMessageCode("IMPERSONATION_DENIED")
+		return c.JSON(response)
+	}
+
+	var user model.User
+	if err := database.DB.Where("username = ?", payload.Username).First(&user).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	permission, err := userPermission(user.Username)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	sessionID, err := utils.RandomString(16)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	minutes := envInt("IMPERSONATE_EXPIRED_TIME", 30)
+	token, err := utils.GenerateImpersonationToken(sessionID, user.Username, tokenData.Username, c.Get("User-Agent"), c.IP(), config.ReadPermissionMask(permission), minutes)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	expire := time.Minute * time.Duration(minutes)
+	session := model.Session{
+		SessionID:      sessionID,
+		Username:       user.Username,
+		UserAgent:      c.Get("User-Agent"),
+		IPAddress:      c.IP(),
+		ImpersonatedBy: tokenData.Username,
+		ExpiresAt:      time.Now().Add(expire),
+	}
+	if err := database.DB.Create(&session).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	if err := database.Store.Set(sessionID, []byte(token), expire); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	core.WriteLog(fmt.Sprintf("IMPERSONATE | START | %s as %s | session %s", tokenData.Username, user.Username, sessionID))
+
+	response.Data = model.TokenModel{Token: token}
+	response.Status = true
+	response.Message = config.GetMessageCode("LOGIN_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetImpersonateLog Lấy nhật ký request của các session giả danh
+// @Summary Get impersonation audit log
+// @Description Returns the requests made in impersonated sessions, newest first. Filter with actor or username
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Param actor query string false "Admin who impersonated"
+// @Param username query string false "Impersonated user"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/impersonate/log [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetImpersonateLog(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	query := database.DB.Order("audit_log_id DESC").Limit(500)
+	if actor := c.Query("actor"); len(actor) > 0 {
+		query = query.Where("actor = ?", actor)
+	}
+	if username := c.Query("username"); len(username) > 0 {
+		query = query.Where("username = ?", username)
+	}
+
+	var logs []model.AuditLog
+	if err := query.Find(&logs).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+
+	response.Data = logs
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
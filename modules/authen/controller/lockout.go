@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"app/config"
+	"app/core"
+	"app/database"
+	"app/modules/authen/model"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm/clause"
+)
+
+// Khoá tối đa 1 ngày dù số lần sai tiếp tục tăng
+const maxLockDuration = 24 * time.Hour
+
+// GetLock Lấy danh sách username / IP đang bị khoá đăng nhập
+// @Summary Get locked logins
+// @Description Returns the usernames and IP addresses currently locked out of login
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/lock [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetLock(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var attempts []model.LoginAttempt
+	results := database.DB.Where("locked_until > ?", time.Now()).Order("locked_until DESC").Find(&attempts)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	response.Data = attempts
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// UnlockUser Mở khoá đăng nhập cho một username
+// @Summary Unlock a username
+// @Description Clears the failure counter and lockout of a username
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Param username path string true "Username"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/lock/user/{username} [delete]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func UnlockUser(c *fiber.Ctx) error {
+	return unlock(c, "user:"+c.Params("username"))
+}
+
+// UnlockIP Mở khoá đăng nhập cho một địa chỉ IP
+// @Summary Unlock an IP address
+// @Description Clears the failure counter and lockout of an IP address
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Param ip path string true "IP address"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/lock/ip/{ip} [delete]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func UnlockIP(c *fiber.Ctx) error {
+	return unlock(c, "ip:"+c.Params("ip"))
+}
+
+func unlock(c *fiber.Ctx, key string) error {
+	response := new(config.DataResponse)
+
+	results := database.DB.Where("attempt_key = ?", key).Delete(&model.LoginAttempt{})
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+	if results.RowsAffected == 0 {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	core.WriteLog(fmt.Sprintf("LOGIN | UNLOCK | %s | by %s", key, getUsername(c)))
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// Trả về thời điểm hết khoá nếu username hoặc IP đang bị khoá
+func loginLocked(username, ip string) *time.Time {
+	var attempt model.LoginAttempt
+	results := database.DB.Where("attempt_key IN ? AND locked_until > ?", []string{"user:" + username, "ip:" + ip}, time.Now()).
+		Order("locked_until DESC").
+		First(&attempt)
+	if results.Error != nil {
+		return nil
+	}
+
+	core.WriteLog(fmt.Sprintf("LOGIN | BLOCKED | %s | %s | until %s", username, ip, attempt.LockedUntil.Format("2006-01-02 15:04:05")))
+	return attempt.LockedUntil
+}
+
+// Ghi nhận đăng nhập sai cho cả username và IP
+func loginFailed(username, ip string) {
+	core.WriteLog(fmt.Sprintf("LOGIN | FAILED | %s | %s", username, ip))
+
+	recordFailure("user:"+username, envInt("LOGIN_MAX_FAILURE", 5))
+	recordFailure("ip:"+ip, envInt("LOGIN_IP_MAX_FAILURE", 20))
+}
+
+// Đăng nhập thành công (sau bước TOTP nếu có): ghi log và xoá bộ đếm sai của username
+func loginSucceeded(username, ip string) {
+	core.WriteLog(fmt.Sprintf("LOGIN | SUCCESS | %s | %s", username, ip))
+
+	database.DB.Where("attempt_key = ?", "user:"+username).Delete(&model.LoginAttempt{})
+}
+
+// Từ lần sai thứ `threshold` trở đi khoá LOGIN_LOCK_TIME phút, mỗi lần sai tiếp theo thời gian khoá gấp đôi.
+// Không sai thêm trong LOGIN_FAILURE_WINDOW phút thì bộ đếm về 0.
+func recordFailure(key string, threshold int) {
+	now := time.Now()
+	window := time.Minute * time.Duration(envInt("LOGIN_FAILURE_WINDOW", 60))
+	base := time.Minute * time.Duration(envInt("LOGIN_LOCK_TIME", 1))
+
+	tx := database.DB.Begin()
+
+	var attempt model.LoginAttempt
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("attempt_key = ?", key).First(&attempt).Error; err != nil {
+		attempt = model.LoginAttempt{AttemptKey: key}
+	}
+
+	if (attempt.LockedUntil == nil || attempt.LockedUntil.Before(now)) && now.Sub(attempt.LastFailedAt) > window {
+		attempt.FailCount = 0
+	}
+
+	attempt.FailCount++
+	attempt.LastFailedAt = now
+
+	if attempt.FailCount >= threshold {
+		lock := maxLockDuration
+		if shift := attempt.FailCount - threshold; shift < 20 && base<<uint(shift) < maxLockDuration {
+			lock = base << uint(shift)
+		}
+		lockedUntil := now.Add(lock)
+		attempt.LockedUntil = &lockedUntil
+
+		core.WriteLog(fmt.Sprintf("LOGIN | LOCKED | %s | %d failures | until %s", key, attempt.FailCount, lockedUntil.Format("2006-01-02 15:04:05")))
+	}
+
+	if err := tx.Save(&attempt).Error; err != nil {
+		tx.Rollback()
+		return
+	}
+
+	tx.Commit()
+}
+
+func envInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(config.Config(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+
+	return value
+}
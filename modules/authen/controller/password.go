@@ -0,0 +1,261 @@
+package controller
+
+import (
+	"app/config"
+	"app/core"
+	"app/database"
+	"app/modules/authen/model"
+	"app/modules/mail"
+	"app/utils"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ForgotPassword Gửi thư chứa liên kết đặt lại mật khẩu
+// @Summary Request a password reset
+// @Description Sends a signed, time-limited reset link to the user's verified email. Always succeeds so usernames cannot be probed
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Param body body model.ForgotPasswordModel true "Username and mail language (vn, en, jp)"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/password/forgot [post]
+// @Security ApiKeyAuth
+func ForgotPassword(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.ForgotPasswordModel)
+	if err := c.BodyParser(payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	var user model.User
+	results := database.DB.Where("username = ?", payload.Username).First(&user)
+	// Chỉ gửi tới email đã xác thực (/authen/email/verify)
+	if results.Error == nil && len(user.Email) > 0 && user.EmailVerifiedAt != nil {
+		minutes := mailTokenMinutes()
+		token, err := utils.EncodePurposeToken("password_reset", user.Username, fingerprint(user.Password), minutes)
+		if err == nil {
+			err = mail.Queue(user.Email, "password_reset", payload.Lang, map[string]interface{}{
+				"Username": user.Username,
+				"Minutes":  minutes,
+				"Link":     config.Config("APP_URL") + "/reset-password?token=" + token,
+			})
+		}
+		if err != nil {
+			core.WriteLog("ERROR | PASSWORD RESET MAIL | " + err.Error())
+		}
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("MAIL_SENT")
+	return c.JSON(response)
+}
+
+// ResetPassword Đặt mật khẩu mới bằng token trong thư
+// @Summary Reset the password
+// @Description Sets a new password using the token from the reset email and logs out every session of the user
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Param body body model.ResetPasswordModel true "Reset token and new password"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/password/reset [post]
+// @Security ApiKeyAuth
+func ResetPassword(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.ResetPasswordModel)
+	if err := c.BodyParser(payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	listCheck := []string{"Token", "Password"}
+	vItem := map[string]string{"Token": payload.Token, "Password": payload.Password}
+	errors := utils.RequireCheck(listCheck, vItem, map[string]string{})
+	if len(errors) > 0 {
+		response.Status = false
+		response.Message = config.GetMessageCode("MISSING_FIELDS")
+		response.ValidateError = errors
+		return c.JSON(response)
+	}
+
+	username, fp, err := utils.DecodePurposeToken(payload.Token, "password_reset")
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("TOKEN_INCORRECT")
+		return c.JSON(response)
+	}
+
+	// Mật khẩu đã đổi thì fingerprint khác, token không dùng lại được
+	var user model.User
+	results := database.DB.Where("username = ?", username).First(&user)
+	if results.Error != nil || fingerprint(user.Password) != fp {
+		response.Status = false
+		response.Message = config.GetMessageCode("TOKEN_INCORRECT")
+		return c.JSON(response)
+	}
+
+	hash, err := utils.HashPassword(payload.Password)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	if err := database.DB.Model(&user).Updates(model.User{Password: hash, UpdatedBy: user.Username}).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	var sessionIDs []string
+	database.DB.Model(&model.Session{}).Where("username = ?", user.Username).Pluck("session_id", &sessionIDs)
+	for _, sessionID := range sessionIDs {
+		revokeSession(sessionID)
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// UpdateEmail Đổi email của user hiện tại và gửi thư xác thực
+// @Summary Set my email
+// @Description Sets the email of the current user and sends a verification link
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Param body body model.EmailModel true "Email and mail language (vn, en, jp)"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/email [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func UpdateEmail(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.EmailModel)
+	if err := c.BodyParser(payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	// Kiểm tra trước khi lưu và xếp thư vào outbox
+	vItem := map[string]string{"Email": payload.Email}
+	errors := utils.RequireCheck([]string{"Email"}, vItem, map[string]string{})
+	errors = utils.MaxLengthCheck([]string{"Email:100"}, vItem, errors)
+	errors = utils.EmailFormatCheck([]string{"Email"}, vItem, errors)
+	if len(errors) > 0 {
+		response.Status = false
+		response.Message = config.GetMessageCode("MISSING_FIELDS")
+		response.ValidateError = errors
+		return c.JSON(response)
+	}
+
+	var user model.User
+	if err := database.DB.Where("username = ?", getUsername(c)).First(&user).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	err := database.DB.Model(&user).Updates(map[string]interface{}{
+		"email":             payload.Email,
+		"email_verified_at": nil,
+		"updated_by":        user.Username,
+	}).Error
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	minutes := mailTokenMinutes()
+	token, err := utils.EncodePurposeToken("email_verify", user.Username, fingerprint(payload.Email), minutes)
+	if err == nil {
+		err = mail.Queue(payload.Email, "email_verify", payload.Lang, map[string]interface{}{
+			"Username": user.Username,
+			"Minutes":  minutes,
+			"Link":     config.Config("APP_URL") + "/verify-email?token=" + token,
+		})
+	}
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("MAIL_SENT")
+	return c.JSON(response)
+}
+
+// VerifyEmail Xác thực email bằng token trong thư
+// @Summary Verify an email address
+// @Description Marks the user's email as verified using the token from the verification email
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Param body body model.VerifyEmailModel true "Verification token"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/email/verify [post]
+// @Security ApiKeyAuth
+func VerifyEmail(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.VerifyEmailModel)
+	if err := c.BodyParser(payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	username, fp, err := utils.DecodePurposeToken(payload.Token, "email_verify")
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("TOKEN_INCORRECT")
+		return c.JSON(response)
+	}
+
+	var user model.User
+	results := database.DB.Where("username = ?", username).First(&user)
+	if results.Error != nil || fingerprint(user.Email) != fp {
+		response.Status = false
+		response.Message = config.GetMessageCode("TOKEN_INCORRECT")
+		return c.JSON(response)
+	}
+
+	if err := database.DB.Model(&user).Update("email_verified_at", time.Now()).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+func mailTokenMinutes() int {
+	minutes, err := strconv.Atoi(config.Config("MAIL_TOKEN_EXPIRED_TIME"))
+	if err != nil || minutes <= 0 {
+		minutes = 30
+	}
+
+	return minutes
+}
+
+func fingerprint(value string) string {
+	return utils.HashKey(value)[:16]
+}
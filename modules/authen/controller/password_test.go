@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"app/config"
+	"app/database/testdb"
+	"app/modules/authen/model"
+	"app/modules/mail"
+	mailModel "app/modules/mail/model"
+	"app/utils"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeSender struct {
+	sent []mail.Message
+}
+
+func (s *fakeSender) Send(msg mail.Message) error {
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func postJSON(t *testing.T, app *fiber.App, path, body string) config.DataResponse {
+	t.Helper()
+
+	request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var response config.DataResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	return response
+}
+
+func TestForgotPassword(t *testing.T) {
+	t.Setenv("JWT_DATA_SECRET_KEY", "test-secret")
+	t.Setenv("MAIL_TOKEN_EXPIRED_TIME", "30")
+	t.Setenv("APP_URL", "https://app.example.com")
+	db := testdb.Open(t, &model.User{}, &model.Session{}, &model.RefreshToken{}, &mailModel.MailOutbox{})
+
+	password, _ := utils.HashPassword("old-password")
+	verifiedAt := time.Now()
+	db.Create(&model.User{Username: "verified", Password: password, Email: "verified@example.com", EmailVerifiedAt: &verifiedAt})
+	db.Create(&model.User{Username: "unverified", Password: password, Email: "unverified@example.com"})
+
+	sender := &fakeSender{}
+	mail.SetSender(sender)
+	t.Cleanup(func() { mail.SetSender(nil) })
+
+	app := fiber.New()
+	app.Post("/authen/password/forgot", ForgotPassword)
+	app.Post("/authen/password/reset", ResetPassword)
+
+	// Luôn trả MAIL_SENT, chỉ email đã xác thực nhận được thư
+	for _, username := range []string{"verified", "unverified", "nobody"} {
+		response := postJSON(t, app, "/authen/password/forgot", `{"username":"`+username+`","lang":"en"}`)
+		if !response.Status || response.Message != config.GetMessageCode("MAIL_SENT") {
+			t.Fatalf("%s: got %v %s, want MAIL_SENT", username, response.Status, response.Message)
+		}
+	}
+	mail.ProcessOutbox()
+
+	if len(sender.sent) != 1 || sender.sent[0].To != "verified@example.com" {
+		t.Fatalf("sent %+v, want one mail to verified@example.com", sender.sent)
+	}
+	link := regexp.MustCompile(`https://app\.example\.com/reset-password\?token=(\S+)`).FindStringSubmatch(sender.sent[0].Body)
+	if link == nil {
+		t.Fatalf("no reset link in %q", sender.sent[0].Body)
+	}
+
+	body := `{"token":"` + link[1] + `","password":"new-password"}`
+	if response := postJSON(t, app, "/authen/password/reset", body); !response.Status {
+		t.Fatalf("reset: got %s", response.Message)
+	}
+	var user model.User
+	db.Where("username = ?", "verified").First(&user)
+	if !utils.CheckPassword(user.Password, "new-password") {
+		t.Fatal("password not changed")
+	}
+
+	// Mật khẩu đã đổi nên token không dùng lại được
+	if response := postJSON(t, app, "/authen/password/reset", body); response.Status || response.Message != config.GetMessageCode("TOKEN_INCORRECT") {
+		t.Fatalf("reused token: got %v %s, want TOKEN_INCORRECT", response.Status, response.Message)
+	}
+}
+
+func TestUpdateEmail(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	t.Setenv("JWT_EXPIRED_TIME", "15")
+	t.Setenv("JWT_DATA_SECRET_KEY", "test-secret")
+	db := testdb.Open(t, &model.User{}, &mailModel.MailOutbox{})
+
+	db.Create(&model.User{Username: "alice", Password: "-", Email: "alice@example.com"})
+	token, err := utils.GenerateAccessToken("session", "alice", "test", "127.0.0.1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New()
+	app.Put("/authen/email", UpdateEmail)
+
+	update := func(email string) config.DataResponse {
+		t.Helper()
+
+		request := httptest.NewRequest(http.MethodPut, "/authen/email", strings.NewReader(`{"email":"`+email+`","lang":"en"}`))
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("x-csv-token", "Bearer "+token)
+		resp, err := app.Test(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		var response config.DataResponse
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			t.Fatal(err)
+		}
+
+		return response
+	}
+
+	// Email sai thì không lưu, không xếp thư
+	cases := []struct {
+		name, email, want string
+	}{
+		{"empty", "", "REQUIRE"},
+		{"no domain", "alice", "FORMAT_EMAIL"},
+		{"display name", "Alice <alice@example.net>", "FORMAT_EMAIL"},
+		{"two addresses", "alice@example.net, bob@example.net", "FORMAT_EMAIL"},
+		{"too long", strings.Repeat("a", 89) + "@example.net", "MAX_LENGTH"},
+	}
+	for _, item := range cases {
+		response := update(item.email)
+		if errors, _ := response.ValidateError.(map[string]interface{}); response.Status || errors["Email"] != config.GetMessageCode(item.want) {
+			t.Errorf("%s: got %v %v, want %s", item.name, response.Status, response.ValidateError, item.want)
+		}
+	}
+
+	var user model.User
+	var queued int64
+	db.Where("username = ?", "alice").First(&user)
+	db.Model(&mailModel.MailOutbox{}).Count(&queued)
+	if user.Email != "alice@example.com" || queued != 0 {
+		t.Fatalf("invalid emails: got email %s and %d queued mails", user.Email, queued)
+	}
+
+	if response := update("alice@example.net"); !response.Status || response.Message != config.GetMessageCode("MAIL_SENT") {
+		t.Fatalf("valid email: got %v %s", response.Status, response.Message)
+	}
+	db.Where("username = ?", "alice").First(&user)
+	db.Model(&mailModel.MailOutbox{}).Count(&queued)
+	if user.Email != "alice@example.net" || queued != 1 {
+		t.Fatalf("valid email: got email %s and %d queued mails", user.Email, queued)
+	}
+}
@@ -0,0 +1,302 @@
+package controller
+
+import (
+	"app/config"
+	"app/database"
+	"app/modules/authen/model"
+	"app/utils"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// GetRole Lấy danh sách tất cả role
+// @Summary Get all Roles
+// @Description Returns a list of all Roles with their permissions
+// @Tags Role
+// @Accept json
+// @Produce json
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /role [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetRole(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var roles []model.Role
+	results := database.DB.Preload("Permissions").Order("role_id").Find(&roles)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	response.Data = roles
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetPermission Lấy danh sách mã quyền có thể gán cho role
+// @Summary Get all permission codes
+// @Description Returns every permission code that can be granted to a Role
+// @Tags Role
+// @Accept json
+// @Produce json
+// @Success 200 {object} config.DataResponse
+// @Router /role/permission [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetPermission(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	response.Data = config.PermissionKeys()
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// CreateRole Tạo mới role
+// @Summary Create new Roles
+// @Description Creates Roles with a list of permission codes
+// @Tags Role
+// @Accept json
+// @Produce json
+// @Param body body []model.CreateRoleModel true "New Role information"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /role [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func CreateRole(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.CreateRoleModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		errors := utils.RequireCheck([]string{"RoleName"}, map[string]string{"RoleName": item.RoleName}, map[string]string{})
+		errors = permissionCheck(item.Permissions, errors)
+
+		if len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("MISSING_FIELDS")
+			response.ValidateError = errors
+			return c.JSON(response)
+		}
+
+		newRole := model.Role{
+			RoleName:         item.RoleName,
+			Permissions:      toRolePermission(item.Permissions),
+			RequireTwoFactor: item.RequireTwoFactor,
+			CreatedBy:        getUsername(c),
+		}
+
+		if err := tx.Create(&newRole).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("CREATE_FAIL")
+			return c.JSON(response)
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("CREATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// UpdateRole cập nhật tên và danh sách quyền của role
+// @Summary Update Roles
+// @Description Replaces the name and permissions of Roles, or soft deletes them when is_deleted is set
+// @Tags Role
+// @Accept json
+// @Produce json
+// @Param body body []model.UpdateRoleModel true "Role information to update"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /role [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func UpdateRole(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.UpdateRoleModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		errors := utils.RequireCheck([]string{"RoleName"}, map[string]string{"RoleName": item.RoleName}, map[string]string{})
+		errors = permissionCheck(item.Permissions, errors)
+
+		if len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("MISSING_FIELDS")
+			response.ValidateError = errors
+			return c.JSON(response)
+		}
+
+		var role model.Role
+		if err := tx.First(&role, item.RoleID).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+			return c.JSON(response)
+		}
+
+		if item.IsDeleted {
+			role.DeletedBy = getUsername(c)
+			role.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		} else {
+			role.RoleName = item.RoleName
+			role.RequireTwoFactor = item.RequireTwoFactor
+			role.UpdatedBy = getUsername(c)
+			role.LogVersion++
+
+			if err := tx.Where("role_id = ?", role.ID).Delete(&model.RolePermission{}).Error; err != nil {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("SYSTEM_ERROR")
+				return c.JSON(response)
+			}
+			role.Permissions = toRolePermission(item.Permissions)
+		}
+
+		if err := tx.Save(&role).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			return c.JSON(response)
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// DeleteRole xóa một Role dựa trên ID
+// @Summary Delete Role
+// @Description Soft deletes a Role based on its ID
+// @Tags Role
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Role"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /role/{id} [delete]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func DeleteRole(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var role model.Role
+	if err := database.DB.First(&role, c.Params("id")).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	role.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	role.DeletedBy = getUsername(c)
+
+	if err := database.DB.Model(&role).Updates(&role).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("DELETE_SUCCESS")
+	return c.JSON(response)
+}
+
+// AssignRole gán danh sách role cho user
+// @Summary Assign Roles to a user
+// @Description Replaces the Roles of a user. Takes effect on the user's next login or refresh
+// @Tags Role
+// @Accept json
+// @Produce json
+// @Param body body model.AssignRoleModel true "Username and role IDs"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /role/user [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func AssignRole(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.AssignRoleModel)
+	if err := c.BodyParser(payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	var user model.User
+	if err := database.DB.Where("username = ?", payload.Username).First(&user).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	var roles []model.Role
+	if len(payload.RoleIDs) > 0 {
+		if err := database.DB.Where("role_id IN ?", payload.RoleIDs).Find(&roles).Error; err != nil || len(roles) != len(payload.RoleIDs) {
+			response.Status = false
+			response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+			return c.JSON(response)
+		}
+	}
+
+	if err := database.DB.Model(&user).Association("Roles").Replace(roles); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// Kiểm tra mã quyền có tồn tại trong config.permissionList
+func permissionCheck(codes []string, errors map[string]string) map[string]string {
+	for i, code := range codes {
+		if config.GetPermissionBit(code) == 0 {
+			errors[fmt.Sprintf("Permissions[%d]", i)] = config.GetMessageCode("PERMISSION_NOT_FOUND")
+		}
+	}
+
+	return errors
+}
+
+func toRolePermission(codes []string) []model.RolePermission {
+	permissions := make([]model.RolePermission, 0, len(codes))
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		permissions = append(permissions, model.RolePermission{PermissionCode: code})
+	}
+
+	return permissions
+}
@@ -0,0 +1,238 @@
+package controller
+
+import (
+	"app/config"
+	"app/database"
+	"app/modules/authen/model"
+	"app/utils"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetSession Lấy danh sách session đang hoạt động của user hiện tại
+// @Summary Get my sessions
+// @Description Returns the active sessions (devices) of the current user
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/session [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetSession(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("TOKEN_INCORRECT")
+		return c.JSON(response)
+	}
+
+	var sessions []model.Session
+	results := database.DB.Where("username = ? AND expires_at > ?", tokenData.Username, time.Now()).Order("created_at DESC").Find(&sessions)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+
+	data := make([]model.SessionModel, 0, len(sessions))
+	for _, session := range sessions {
+		data = append(data, model.SessionModel{Session: session, Current: session.SessionID == tokenData.SessionID})
+	}
+
+	response.Data = data
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// RevokeSession Thu hồi một session của user hiện tại
+// @Summary Revoke a session
+// @Description Logs out one of the current user's sessions
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Param id path string true "ID of the session"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/session/{id} [delete]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func RevokeSession(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("TOKEN_INCORRECT")
+		return c.JSON(response)
+	}
+
+	var session model.Session
+	results := database.DB.Where("session_id = ? AND username = ?", c.Params("id"), tokenData.Username).First(&session)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	if err := revokeSession(session.SessionID); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("DELETE_SUCCESS")
+	return c.JSON(response)
+}
+
+// RevokeOtherSession Thu hồi tất cả session khác của user hiện tại
+// @Summary Revoke all other sessions
+// @Description Logs out every session of the current user except the one making the call
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/session [delete]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func RevokeOtherSession(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("TOKEN_INCORRECT")
+		return c.JSON(response)
+	}
+
+	var sessionIDs []string
+	database.DB.Model(&model.Session{}).Where("username = ? AND session_id <> ?", tokenData.Username, tokenData.SessionID).Pluck("session_id", &sessionIDs)
+
+	for _, sessionID := range sessionIDs {
+		if err := revokeSession(sessionID); err != nil {
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			return c.JSON(response)
+		}
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("DELETE_SUCCESS")
+	return c.JSON(response)
+}
+
+// Tạo access token + refresh token và lưu session để middleware.AppAuthen kiểm tra.
+// sessionID rỗng thì mở session mới, ngược lại cấp lại token cho session đó.
+// familyID rỗng thì bắt đầu một chuỗi refresh token mới.
+func createSession(c *fiber.Ctx, username, sessionID, familyID string) (*model.TokenModel, error) {
+	permission, err := userPermission(username)
+	if err != nil {
+		return nil, err
+	}
+
+	isNew := len(sessionID) == 0
+	if isNew {
+		if sessionID, err = utils.RandomString(16); err != nil {
+			return nil, err
+		}
+	}
+
+	token, err := utils.GenerateAccessToken(sessionID, username, c.Get("User-Agent"), c.IP(), permission)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := issueRefreshToken(username, sessionID, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	minutesCount, _ := strconv.Atoi(config.Config("JWT_EXPIRED_TIME"))
+	expire := time.Minute * time.Duration(minutesCount)
+
+	// Session sống theo refresh token, access token trong store hết hạn sớm hơn
+	session := model.Session{
+		SessionID: sessionID,
+		Username:  username,
+		UserAgent: c.Get("User-Agent"),
+		IPAddress: c.IP(),
+		ExpiresAt: time.Now().Add(refreshExpire()),
+	}
+	if isNew {
+		err = database.DB.Create(&session).Error
+	} else {
+		err = database.DB.Model(&session).Select("user_agent", "ip_address", "expires_at").Updates(&session).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := database.Store.Set(sessionID, []byte(token), expire); err != nil {
+		return nil, err
+	}
+
+	return &model.TokenModel{Token: token, RefreshToken: refreshToken}, nil
+}
+
+// Refresh token là chuỗi ngẫu nhiên dùng một lần, CSDL chỉ lưu sha256
+func issueRefreshToken(username, sessionID, familyID string) (string, error) {
+	token, err := utils.RandomString(32)
+	if err != nil {
+		return "", err
+	}
+
+	if len(familyID) == 0 {
+		if familyID, err = utils.RandomString(16); err != nil {
+			return "", err
+		}
+	}
+
+	refreshToken := model.RefreshToken{
+		TokenHash: utils.HashKey(token),
+		FamilyID:  familyID,
+		SessionID: sessionID,
+		Username:  username,
+		ExpiresAt: time.Now().Add(refreshExpire()),
+	}
+	if err := database.DB.Create(&refreshToken).Error; err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func refreshExpire() time.Duration {
+	minutesCount, _ := strconv.Atoi(config.Config("JWT_REFRESH_EXPIRED_TIME"))
+	return time.Minute * time.Duration(minutesCount)
+}
+
+// Refresh token bị dùng lại: thu hồi cả chuỗi token và các session của chuỗi đó
+func revokeFamily(familyID string) {
+	var sessionIDs []string
+	database.DB.Model(&model.RefreshToken{}).Where("family_id = ?", familyID).Distinct().Pluck("session_id", &sessionIDs)
+
+	for _, sessionID := range sessionIDs {
+		revokeSession(sessionID)
+	}
+}
+
+func revokeSession(sessionID string) error {
+	if err := database.Store.Delete(sessionID); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := database.DB.Model(&model.RefreshToken{}).Where("session_id = ? AND revoked_at IS NULL", sessionID).Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+
+	return database.DB.Where("session_id = ?", sessionID).Delete(&model.Session{}).Error
+}
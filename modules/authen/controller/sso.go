@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"app/config"
+	"app/core"
+	"app/database"
+	"app/modules/authen/model"
+	"app/modules/authen/sso"
+	employeeModel "app/modules/employee/model"
+	"app/utils"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// LoginSSO Đăng nhập bằng ticket của CubeSystem SSO
+// @Summary Login with SSO
+// @Description Verifies an SSO ticket against SSO_BASE_URL, maps it to an active Employee by employee code or email and issues an access token.
+// @Description The Employee's account is created on the first login; an existing account must first be linked by an admin (/authen/sso/link)
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Param body body model.SsoLoginModel true "SSO ticket"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/sso [post]
+// @Security ApiKeyAuth
+func LoginSSO(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.SsoLoginModel)
+	if err := c.BodyParser(payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	errors := utils.RequireCheck([]string{"Ticket"}, map[string]string{"Ticket": payload.Ticket}, map[string]string{})
+	if len(errors) > 0 {
+		response.Status = false
+		response.Message = config.GetMessageCode("MISSING_FIELDS")
+		response.ValidateError = errors
+		return c.JSON(response)
+	}
+
+	identity, err := sso.GetClient().Verify(payload.Ticket)
+	if err != nil {
+		core.WriteLog("ERROR | SSO VERIFY | " + err.Error())
+		response.Status = false
+		response.Message = config.GetMessageCode("SSO_VERIFY_FAIL")
+		return c.JSON(response)
+	}
+
+	user, err := findSsoUser(identity)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SSO_USER_NOT_FOUND")
+		if err == errSsoNotLinked {
+			response.Message = config.GetMessageCode("SSO_USER_NOT_LINKED")
+		}
+		return c.JSON(response)
+	}
+
+	return completeLogin(c, user)
+}
+
+// Mật khẩu của tài khoản tạo khi đăng nhập SSO lần đầu, không phải hash bcrypt nên không đăng nhập bằng mật khẩu được
+const ssoPassword = "-"
+
+var errSsoNotLinked = errors.New("local account exists but is not linked to this SSO identity")
+
+// Tìm tài khoản local theo sso_id. Chưa liên kết thì tìm nhân viên đang làm theo mã nhân viên (username SSO) hoặc email:
+// nhân viên chưa có tài khoản thì tạo tài khoản (username là mã nhân viên) gắn sso_id,
+// đã có tài khoản thì phải được admin liên kết qua /authen/sso/link, không tự gắn theo username
+func findSsoUser(identity *sso.Identity) (*model.User, error) {
+	db := database.DB
+
+	var user model.User
+	if err := db.Where("sso_id = ?", identity.UserID).First(&user).Error; err == nil {
+		return &user, nil
+	}
+
+	employee, err := ssoEmployee(db, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Where("username = ?", employee.EmployeeCode).First(&user).Error
+	switch {
+	case err == nil:
+		return nil, errSsoNotLinked
+	case err != gorm.ErrRecordNotFound:
+		return nil, err
+	}
+
+	user = model.User{Username: employee.EmployeeCode, Password: ssoPassword, SsoID: identity.UserID, Email: employee.Email, CreatedBy: "sso"}
+	if err := db.Create(&user).Error; err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// Nhân viên chưa nghỉ việc có mã nhân viên là username SSO, không có thì theo email (chỉ khi email không trùng)
+func ssoEmployee(db *gorm.DB, identity *sso.Identity) (*employeeModel.Employee, error) {
+	active := func(db *gorm.DB) *gorm.DB {
+		return db.Where("status <> ?", employeeModel.StatusTerminated)
+	}
+
+	var employee employeeModel.Employee
+	if len(identity.Username) > 0 {
+		if err := db.Scopes(active).Where("employee_code = ?", identity.Username).First(&employee).Error; err == nil {
+			return &employee, nil
+		}
+	}
+	if len(identity.Email) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var employees []employeeModel.Employee
+	if err := db.Scopes(active).Where("LOWER(email) = LOWER(?)", identity.Email).Limit(2).Find(&employees).Error; err != nil {
+		return nil, err
+	}
+	if len(employees) != 1 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &employees[0], nil
+}
+
+// LinkSSO Liên kết / bỏ liên kết tài khoản local với SSO
+// @Summary Link a user to an SSO identity
+// @Description Sets the SSO user_id of a local account, an empty sso_id removes the link.
+// @Description Accounts that already exist are only logged in by SSO after this link, SSO never binds to them by username
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Param body body model.SsoLinkModel true "Username and SSO user_id"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/sso/link [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func LinkSSO(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.SsoLinkModel)
+	if err := c.BodyParser(payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	vItem := map[string]string{"Username": payload.Username, "SsoID": payload.SsoID}
+	errors := utils.RequireCheck([]string{"Username"}, vItem, map[string]string{})
+	errors = utils.MaxLengthCheck([]string{"SsoID:50"}, vItem, errors)
+	if len(errors) > 0 {
+		response.Status = false
+		response.Message = config.GetMessageCode("MISSING_FIELDS")
+		response.ValidateError = errors
+		return c.JSON(response)
+	}
+
+	var user model.User
+	if err := database.DB.Where("username = ?", payload.Username).First(&user).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	if len(payload.SsoID) > 0 {
+		var count int64
+		database.DB.Model(&model.User{}).Where("sso_id = ? AND user_id <> ?", payload.SsoID, user.ID).Count(&count)
+		if count > 0 {
+			response.Status = false
+			response.Message = config.GetMessageCode("MISSING_FIELDS")
+			response.ValidateError = map[string]string{"SsoID": config.GetMessageCode("CODE_EXISTS")}
+			return c.JSON(response)
+		}
+	}
+
+	if err := database.DB.Model(&user).Updates(map[string]interface{}{
+		"sso_id":      payload.SsoID,
+		"log_version": gorm.Expr("log_version + 1"),
+		"updated_by":  getUsername(c),
+	}).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
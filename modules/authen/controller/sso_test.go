@@ -0,0 +1,167 @@
+package controller
+
+import (
+	"app/config"
+	"app/database/testdb"
+	"app/modules/authen/model"
+	"app/modules/authen/sso"
+	employeeModel "app/modules/employee/model"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SSO server giả lập: identities theo ticket, ticket khác báo lỗi
+var ssoIdentities = map[string]sso.Identity{
+	"good":       {UserID: "sso-1", Username: "1001"},
+	"email":      {UserID: "sso-2", Username: "b.binh", Email: "Binh@example.com"},
+	"existing":   {UserID: "sso-3", Username: "1003"},
+	"terminated": {UserID: "sso-4", Username: "1004"},
+	"admin":      {UserID: "sso-5", Username: "admin"},
+	"stranger":   {UserID: "sso-9", Username: "9999"},
+}
+
+func newSsoServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			AppID  string `json:"app_id"`
+			Ticket string `json:"ticket"`
+		}
+		if r.URL.Path != "/api/verify" || json.NewDecoder(r.Body).Decode(&request) != nil || request.AppID != "test-app" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if identity, ok := ssoIdentities[request.Ticket]; ok {
+			json.NewEncoder(w).Encode(map[string]interface{}{"status": true, "data": identity})
+		} else {
+			json.NewEncoder(w).Encode(map[string]interface{}{"status": false, "message": "ticket expired"})
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func postSso(t *testing.T, app *fiber.App, ticket string) config.DataResponse {
+	t.Helper()
+
+	return send(t, app, http.MethodPost, "/authen/sso", `{"ticket":"`+ticket+`"}`)
+}
+
+func send(t *testing.T, app *fiber.App, method, target, body string) config.DataResponse {
+	t.Helper()
+
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var response config.DataResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	return response
+}
+
+func TestLoginSSO(t *testing.T) {
+	t.Setenv("JWT_SECRET_KEY", "test-secret")
+	t.Setenv("JWT_EXPIRED_TIME", "15")
+	t.Setenv("JWT_REFRESH_EXPIRED_TIME", "60")
+	db := testdb.Open(t, testdb.OrgModels, testdb.EmployeeModels,
+		&model.User{}, &model.Role{}, &model.RolePermission{}, &model.Session{}, &model.RefreshToken{}, &model.LoginAttempt{})
+
+	testdb.SeedEmployee(t, db, "1001", nil)
+	second := testdb.SeedEmployee(t, db, "1002", nil)
+	db.Model(&second).Update("email", "binh@example.com")
+	testdb.SeedEmployee(t, db, "1003", nil)
+	terminated := testdb.SeedEmployee(t, db, "1004", nil)
+	db.Model(&terminated).Update("status", employeeModel.StatusTerminated)
+	// Tài khoản local có sẵn, chưa liên kết SSO
+	db.Create(&[]model.User{{Username: "admin", Password: "-"}, {Username: "1003", Password: "-"}})
+
+	previous := sso.GetClient()
+	sso.SetClient(sso.NewClient(newSsoServer(t).URL, "test-app", "web"))
+	t.Cleanup(func() { sso.SetClient(previous) })
+
+	app := fiber.New()
+	app.Post("/authen/sso", LoginSSO)
+	app.Put("/authen/sso/link", LinkSSO)
+
+	loggedIn := func(t *testing.T, ticket string) {
+		t.Helper()
+
+		response := postSso(t, app, ticket)
+		if !response.Status || response.Message != config.GetMessageCode("LOGIN_SUCCESS") {
+			t.Fatalf("%s: got %v %s, want LOGIN_SUCCESS", ticket, response.Status, response.Message)
+		}
+		token, _ := response.Data.(map[string]interface{})
+		if len(token) == 0 || token["token"] == "" || token["refresh_token"] == "" {
+			t.Fatalf("%s: no token in %v", ticket, response.Data)
+		}
+	}
+
+	for ticket, message := range map[string]string{
+		"expired":    "SSO_VERIFY_FAIL",
+		"stranger":   "SSO_USER_NOT_FOUND",
+		"terminated": "SSO_USER_NOT_FOUND",
+		// Không có nhân viên mã admin: không được nhận tài khoản admin theo username
+		"admin":    "SSO_USER_NOT_FOUND",
+		"existing": "SSO_USER_NOT_LINKED",
+	} {
+		response := postSso(t, app, ticket)
+		if response.Status || response.Message != config.GetMessageCode(message) {
+			t.Errorf("%s: got %v %s, want %s", ticket, response.Status, response.Message, message)
+		}
+	}
+	var admin model.User
+	if db.Where("username = 'admin'").First(&admin); admin.SsoID != "" {
+		t.Fatalf("admin linked to %q", admin.SsoID)
+	}
+
+	t.Run("employee code", func(t *testing.T) {
+		// Lần đầu tạo tài khoản cho nhân viên, lần sau tìm theo sso_id
+		loggedIn(t, "good")
+		loggedIn(t, "good")
+
+		var user model.User
+		db.Where("username = ?", "1001").First(&user)
+		if user.SsoID != "sso-1" {
+			t.Fatalf("sso_id = %q, want sso-1", user.SsoID)
+		}
+		var sessions int64
+		db.Model(&model.Session{}).Where("username = ?", "1001").Count(&sessions)
+		if sessions != 2 {
+			t.Fatalf("%d sessions, want 2", sessions)
+		}
+	})
+
+	t.Run("employee email", func(t *testing.T) {
+		loggedIn(t, "email")
+
+		var user model.User
+		if err := db.Where("sso_id = ?", "sso-2").First(&user).Error; err != nil || user.Username != "1002" {
+			t.Fatalf("got user %q, %v, want 1002", user.Username, err)
+		}
+	})
+
+	t.Run("linked by admin", func(t *testing.T) {
+		response := send(t, app, http.MethodPut, "/authen/sso/link", `{"username":"admin","sso_id":"sso-1"}`)
+		if errors, _ := response.ValidateError.(map[string]interface{}); response.Status || errors["SsoID"] != config.GetMessageCode("CODE_EXISTS") {
+			t.Fatalf("sso_id of another user: got %v %v", response.Status, response.ValidateError)
+		}
+
+		if response := send(t, app, http.MethodPut, "/authen/sso/link", `{"username":"1003","sso_id":"sso-3"}`); !response.Status {
+			t.Fatalf("link: got %s %v", response.Message, response.ValidateError)
+		}
+		loggedIn(t, "existing")
+	})
+}
@@ -0,0 +1,391 @@
+package controller
+
+import (
+	"app/config"
+	"app/database"
+	"app/middleware"
+	"app/modules/authen/model"
+	"app/utils"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Thời hạn (phút) của mfa_token giữa bước mật khẩu và bước TOTP
+const mfaTokenMinutes = 5
+
+// LoginTotp Bước 2 của đăng nhập: xác thực mã TOTP hoặc mã dự phòng
+// @Summary Login second step (TOTP)
+// @Description Exchanges the mfa_token from /authen/login and a TOTP or recovery code for an access token
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Param body body model.TotpLoginModel true "mfa_token and code"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/login/totp [post]
+// @Security ApiKeyAuth
+func LoginTotp(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.TotpLoginModel)
+	if err := c.BodyParser(payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	user, err := mfaUser(payload.MfaToken, "mfa_login")
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("TOKEN_INCORRECT")
+		return c.JSON(response)
+	}
+
+	if lockedUntil := loginLocked(user.Username, c.IP()); lockedUntil != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("ACCOUNT_LOCKED")
+		response.Data = model.LockModel{LockedUntil: *lockedUntil}
+		return c.JSON(response)
+	}
+
+	if !checkTotp(user, payload.Code) && !useRecoveryCode(user, payload.Code) {
+		loginFailed(user.Username, c.IP())
+		response.Status = false
+		response.Message = config.GetMessageCode("TWO_FACTOR_INCORRECT")
+		return c.JSON(response)
+	}
+
+	loginSucceeded(user.Username, c.IP())
+
+	token, err := createSession(c, user.Username, "", "")
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Data = token
+	response.Status = true
+	response.Message = config.GetMessageCode("LOGIN_SUCCESS")
+	return c.JSON(response)
+}
+
+// SetupTotp Tạo secret TOTP mới cho user hiện tại (chưa bật cho đến khi gọi enable)
+// @Summary Start TOTP enrolment
+// @Description Generates a new TOTP secret and its otpauth:// provisioning URI (render it as a QR code).
+// @Description Call it with a session token, or with the mfa_token returned when role policy requires TOTP
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Param body body model.TotpSetupModel false "mfa_token when not logged in"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/totp/setup [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func SetupTotp(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.TotpSetupModel)
+	c.BodyParser(payload)
+
+	user, err := totpUser(c, payload.MfaToken)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("TOKEN_INCORRECT")
+		return c.JSON(response)
+	}
+
+	if user.TotpEnabledAt != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("TWO_FACTOR_ENABLED")
+		return c.JSON(response)
+	}
+
+	secret, err := utils.GenerateTotpSecret()
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	if err := database.DB.Model(user).Update("totp_secret", secret).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Data = model.TotpSecretModel{
+		Secret: secret,
+		Uri:    utils.TotpURI(config.Config("TOTP_ISSUER"), user.Username, secret),
+	}
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// EnableTotp Xác nhận mã TOTP đầu tiên, bật 2FA và trả về mã dự phòng
+// @Summary Finish TOTP enrolment
+// @Description Verifies a code for the secret from /authen/totp/setup, enables TOTP and returns one-time recovery codes.
+// @Description When called with an mfa_token the login is completed and the access token is returned as well
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Param body body model.TotpEnableModel true "Code (and mfa_token when not logged in)"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/totp/enable [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func EnableTotp(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.TotpEnableModel)
+	if err := c.BodyParser(payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	user, err := totpUser(c, payload.MfaToken)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("TOKEN_INCORRECT")
+		return c.JSON(response)
+	}
+
+	if user.TotpEnabledAt != nil || len(user.TotpSecret) == 0 || !checkTotp(user, payload.Code) {
+		response.Status = false
+		response.Message = config.GetMessageCode("TWO_FACTOR_INCORRECT")
+		return c.JSON(response)
+	}
+
+	codes, err := resetRecoveryCode(user)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	if err := database.DB.Model(user).Update("totp_enabled_at", time.Now()).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	data := model.RecoveryCodeModel{RecoveryCodes: codes}
+	if len(payload.MfaToken) > 0 {
+		if data.Token, err = createSession(c, user.Username, "", ""); err != nil {
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			return c.JSON(response)
+		}
+	}
+
+	response.Data = data
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// DisableTotp Tắt 2FA của user hiện tại
+// @Summary Disable TOTP
+// @Description Disables TOTP for the current user. Not allowed when one of the user's roles requires it
+// @Tags Authen
+// @Accept json
+// @Produce json
+// @Param body body model.TotpCodeModel true "Current TOTP or recovery code"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /authen/totp [delete]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func DisableTotp(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.TotpCodeModel)
+	if err := c.BodyParser(payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	user, err := totpUser(c, "")
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("TOKEN_INCORRECT")
+		return c.JSON(response)
+	}
+
+	if requireTwoFactor(user) {
+		response.Status = false
+		response.Message = config.GetMessageCode("TWO_FACTOR_SETUP_REQUIRED")
+		return c.JSON(response)
+	}
+
+	if user.TotpEnabledAt == nil || (!checkTotp(user, payload.Code) && !useRecoveryCode(user, payload.Code)) {
+		response.Status = false
+		response.Message = config.GetMessageCode("TWO_FACTOR_INCORRECT")
+		return c.JSON(response)
+	}
+
+	err = database.DB.Model(user).Updates(map[string]interface{}{
+		"totp_secret":     "",
+		"totp_enabled_at": nil,
+	}).Error
+	if err == nil {
+		err = database.DB.Where("user_id = ?", user.ID).Delete(&model.RecoveryCode{}).Error
+	}
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// Sau khi xác thực mật khẩu / SSO: cấp session, hoặc yêu cầu bước TOTP trước khi ghi session vào database.Store
+func completeLogin(c *fiber.Ctx, user *model.User) error {
+	response := new(config.DataResponse)
+
+	purpose := ""
+	if user.TotpEnabledAt != nil {
+		purpose = "mfa_login"
+	} else if requireTwoFactor(user) {
+		purpose = "mfa_setup"
+	}
+
+	if len(purpose) > 0 {
+		mfaToken, err := utils.EncodePurposeToken(purpose, user.Username, fingerprint(user.Password), mfaTokenMinutes)
+		if err != nil {
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			return c.JSON(response)
+		}
+
+		response.Data = model.MfaModel{MfaToken: mfaToken}
+		response.Status = false
+		if purpose == "mfa_login" {
+			response.Message = config.GetMessageCode("TWO_FACTOR_REQUIRED")
+		} else {
+			response.Message = config.GetMessageCode("TWO_FACTOR_SETUP_REQUIRED")
+		}
+		return c.JSON(response)
+	}
+
+	loginSucceeded(user.Username, c.IP())
+
+	token, err := createSession(c, user.Username, "", "")
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Data = token
+	response.Status = true
+	response.Message = config.GetMessageCode("LOGIN_SUCCESS")
+	return c.JSON(response)
+}
+
+// User có role bắt buộc TOTP
+func requireTwoFactor(user *model.User) bool {
+	var count int64
+	database.DB.Model(&model.Role{}).
+		Joins("JOIN tbl_user_role ON tbl_user_role.role_id = tbl_role.role_id").
+		Where("tbl_user_role.user_id = ? AND tbl_role.require_two_factor = ?", user.ID, true).
+		Count(&count)
+
+	return count > 0
+}
+
+func mfaUser(mfaToken, purpose string) (*model.User, error) {
+	username, fp, err := utils.DecodePurposeToken(mfaToken, purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	var user model.User
+	if err := database.DB.Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, err
+	}
+
+	if fingerprint(user.Password) != fp {
+		return nil, fiber.ErrUnauthorized
+	}
+
+	return &user, nil
+}
+
+// User đang đăng ký TOTP: lấy từ mfa_token (mfa_setup) nếu có, không thì từ session hiện tại
+func totpUser(c *fiber.Ctx, mfaToken string) (*model.User, error) {
+	if len(mfaToken) > 0 {
+		return mfaUser(mfaToken, "mfa_setup")
+	}
+
+	// Session giả danh không được đổi 2FA của user
+	tokenData, ok := middleware.CheckSession(c)
+	if !ok || tokenData.Impersonated() {
+		return nil, fiber.ErrUnauthorized
+	}
+
+	var user model.User
+	if err := database.DB.Where("username = ?", tokenData.Username).First(&user).Error; err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func checkTotp(user *model.User, code string) bool {
+	step, ok := utils.VerifyTotp(user.TotpSecret, strings.TrimSpace(code), time.Now(), user.TotpLastStep)
+	if !ok {
+		return false
+	}
+
+	// Ghi lại bước đã dùng, mỗi mã chỉ dùng được một lần
+	results := database.DB.Model(user).Where("totp_last_step < ?", step).Update("totp_last_step", step)
+	return results.Error == nil && results.RowsAffected == 1
+}
+
+func useRecoveryCode(user *model.User, code string) bool {
+	results := database.DB.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, utils.HashKey(strings.TrimSpace(code))).
+		Update("used_at", time.Now())
+
+	return results.Error == nil && results.RowsAffected == 1
+}
+
+// Tạo lại 10 mã dự phòng, chỉ trả về một lần
+func resetRecoveryCode(user *model.User) ([]string, error) {
+	tx := database.DB.Begin()
+
+	if err := tx.Where("user_id = ?", user.ID).Delete(&model.RecoveryCode{}).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	codes := make([]string, 0, 10)
+	for i := 0; i < 10; i++ {
+		code, err := utils.RandomString(5)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if err := tx.Create(&model.RecoveryCode{UserID: user.ID, CodeHash: utils.HashKey(code)}).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+
+	tx.Commit()
+
+	return codes, nil
+}
@@ -0,0 +1,75 @@
+package migrate
+
+import (
+	"app/config"
+	"app/core"
+	"app/database"
+	model "app/modules/authen/model"
+	"app/utils"
+
+	"gorm.io/gorm/clause"
+)
+
+func MigrateAuthen() bool {
+	db := database.DB
+
+	db.AutoMigrate(&model.User{}, &model.Role{}, &model.RolePermission{}, &model.Session{}, &model.RefreshToken{}, &model.RecoveryCode{}, &model.LoginAttempt{}, &model.AuditLog{})
+
+	seedAdmin()
+	syncAdminPermissions()
+
+	return true
+}
+
+// Tạo tài khoản admin đầu tiên (role admin có toàn bộ quyền) từ ADMIN_USERNAME / ADMIN_PASSWORD khi bảng user còn trống
+func seedAdmin() {
+	db := database.DB
+
+	username := config.Config("ADMIN_USERNAME")
+	password := config.Config("ADMIN_PASSWORD")
+	if len(username) == 0 || len(password) == 0 {
+		return
+	}
+
+	var count int64
+	db.Model(&model.User{}).Count(&count)
+	if count > 0 {
+		return
+	}
+
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		core.WriteLog("ERROR | SEED ADMIN")
+		return
+	}
+
+	role := model.Role{RoleName: "admin", CreatedBy: username}
+	for _, code := range config.PermissionKeys() {
+		role.Permissions = append(role.Permissions, model.RolePermission{PermissionCode: code})
+	}
+
+	db.Create(&model.User{
+		Username:  username,
+		Password:  hash,
+		Roles:     []model.Role{role},
+		CreatedBy: username,
+	})
+}
+
+// Cấp cho role admin các quyền được thêm sau lần seed đầu tiên (module mới khai báo quyền trong config.permissionList)
+func syncAdminPermissions() {
+	db := database.DB
+
+	var role model.Role
+	if err := db.Where("role_name = ?", "admin").First(&role).Error; err != nil {
+		return
+	}
+
+	var permissions []model.RolePermission
+	for _, code := range config.PermissionKeys() {
+		permissions = append(permissions, model.RolePermission{RoleID: role.ID, PermissionCode: code})
+	}
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&permissions).Error; err != nil {
+		core.WriteLog("ERROR | SYNC ADMIN PERMISSION")
+	}
+}
@@ -0,0 +1,248 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Model struct {
+	ID        uint `gorm:"primarykey;column:user_id;<-:create"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// User tài khoản đăng nhập, mật khẩu lưu dạng bcrypt
+type User struct {
+	Model
+	Username        string     `gorm:"column:username;size:15;not null;uniqueIndex"`
+	Password        string     `gorm:"column:password;size:100;not null" json:"-"`
+	SsoID           string     `gorm:"column:sso_id;size:50;index"`
+	Email           string     `gorm:"column:email;size:100"`
+	EmailVerifiedAt *time.Time `gorm:"column:email_verified_at"`
+	TotpSecret      string     `gorm:"column:totp_secret;size:64" json:"-"`
+	TotpEnabledAt   *time.Time `gorm:"column:totp_enabled_at"`
+	TotpLastStep    int64      `gorm:"column:totp_last_step;default:0" json:"-"`
+	Roles           []Role     `gorm:"many2many:tbl_user_role"`
+	LogVersion      int64      `gorm:"column:log_version;default:0"`
+	CreatedBy       string     `gorm:"column:created_by;size:15"`
+	UpdatedBy       string     `gorm:"column:updated_by;size:15"`
+	DeletedBy       string     `gorm:"column:deleted_by;size:15"`
+}
+
+// Role nhóm quyền, mỗi quyền là một mã trong config.permissionList (vd: team:write)
+type Role struct {
+	ID          uint `gorm:"primarykey;column:role_id;<-:create"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   gorm.DeletedAt   `gorm:"index"`
+	RoleName    string           `gorm:"column:role_name;size:50;not null;uniqueIndex"`
+	Permissions []RolePermission `gorm:"foreignKey:RoleID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	// Bắt buộc TOTP với user có role này (thường bật cho role có quyền :write)
+	RequireTwoFactor bool   `gorm:"column:require_two_factor;default:false"`
+	LogVersion       int64  `gorm:"column:log_version;default:0"`
+	CreatedBy        string `gorm:"column:created_by;size:15"`
+	UpdatedBy        string `gorm:"column:updated_by;size:15"`
+	DeletedBy        string `gorm:"column:deleted_by;size:15"`
+}
+
+// Session một phiên đăng nhập (mỗi thiết bị một session), khoá trong database.Store là SessionID
+type Session struct {
+	SessionID string `gorm:"primaryKey;column:session_id;size:32" json:"session_id"`
+	Username  string `gorm:"column:username;size:15;not null;index" json:"username"`
+	UserAgent string `gorm:"column:user_agent;size:255" json:"user_agent"`
+	IPAddress string `gorm:"column:ip_address;size:45" json:"ip_address"`
+	// Admin đang giả danh user này, rỗng nếu là session đăng nhập thường
+	ImpersonatedBy string    `gorm:"column:impersonated_by;size:15" json:"impersonated_by"`
+	CreatedAt      time.Time `json:"created_at"`
+	ExpiresAt      time.Time `gorm:"column:expires_at;index" json:"expires_at"`
+}
+
+// RefreshToken dùng một lần, mỗi lần refresh sinh token mới cùng FamilyID
+type RefreshToken struct {
+	ID        uint   `gorm:"primarykey;column:refresh_token_id;<-:create"`
+	TokenHash string `gorm:"column:token_hash;size:64;not null;uniqueIndex"`
+	FamilyID  string `gorm:"column:family_id;size:32;not null;index"`
+	SessionID string `gorm:"column:session_id;size:32;not null;index"`
+	Username  string `gorm:"column:username;size:15;not null"`
+	CreatedAt time.Time
+	ExpiresAt time.Time  `gorm:"column:expires_at"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+}
+
+// RecoveryCode mã dự phòng dùng một lần khi mất thiết bị TOTP
+type RecoveryCode struct {
+	ID        uint       `gorm:"primarykey;column:recovery_code_id;<-:create"`
+	UserID    uint       `gorm:"column:user_id;not null;index"`
+	CodeHash  string     `gorm:"column:code_hash;size:64;not null"`
+	UsedAt    *time.Time `gorm:"column:used_at"`
+	CreatedAt time.Time
+}
+
+// LoginAttempt bộ đếm đăng nhập sai, AttemptKey là "user:<username>" hoặc "ip:<address>"
+type LoginAttempt struct {
+	AttemptKey   string     `gorm:"primaryKey;column:attempt_key;size:80" json:"attempt_key"`
+	FailCount    int        `gorm:"column:fail_count;default:0" json:"fail_count"`
+	LastFailedAt time.Time  `gorm:"column:last_failed_at" json:"last_failed_at"`
+	LockedUntil  *time.Time `gorm:"column:locked_until;index" json:"locked_until"`
+}
+
+// AuditLog mỗi request của session giả danh, ghi cả admin thật (Actor) và user bị giả danh
+type AuditLog struct {
+	ID         uint      `gorm:"primarykey;column:audit_log_id;<-:create" json:"audit_log_id"`
+	SessionID  string    `gorm:"column:session_id;size:32;not null;index" json:"session_id"`
+	Actor      string    `gorm:"column:actor;size:15;not null;index" json:"actor"`
+	Username   string    `gorm:"column:username;size:15;not null;index" json:"username"`
+	Method     string    `gorm:"column:method;size:10" json:"method"`
+	Path       string    `gorm:"column:path;size:255" json:"path"`
+	StatusCode int       `gorm:"column:status_code" json:"status_code"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type RolePermission struct {
+	RoleID         uint   `gorm:"primaryKey;column:role_id"`
+	PermissionCode string `gorm:"primaryKey;column:permission_code;size:50"`
+}
+
+type LoginModel struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type SsoLoginModel struct {
+	Ticket string `json:"ticket" validate:"required"`
+}
+
+// SsoLinkModel SsoID rỗng là bỏ liên kết
+type SsoLinkModel struct {
+	Username string `json:"username" validate:"required"`
+	SsoID    string `json:"sso_id"`
+}
+
+type CreateRoleModel struct {
+	RoleName         string   `json:"role_name" validate:"required"`
+	Permissions      []string `json:"permissions"`
+	RequireTwoFactor bool     `json:"require_two_factor"`
+}
+
+type UpdateRoleModel struct {
+	RoleID           uint     `json:"role_id" validate:"required"`
+	RoleName         string   `json:"role_name" validate:"required"`
+	Permissions      []string `json:"permissions"`
+	RequireTwoFactor bool     `json:"require_two_factor"`
+	IsDeleted        bool     `json:"is_deleted"`
+}
+
+type AssignRoleModel struct {
+	Username string `json:"username" validate:"required"`
+	RoleIDs  []uint `json:"role_ids"`
+}
+
+type SessionModel struct {
+	Session
+	Current bool `json:"current"`
+}
+
+type RefreshModel struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type ForgotPasswordModel struct {
+	Username string `json:"username" validate:"required"`
+	Lang     string `json:"lang"`
+}
+
+type ResetPasswordModel struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type EmailModel struct {
+	Email string `json:"email" validate:"required"`
+	Lang  string `json:"lang"`
+}
+
+type VerifyEmailModel struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type TotpLoginModel struct {
+	MfaToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type TotpSetupModel struct {
+	MfaToken string `json:"mfa_token"`
+}
+
+type TotpEnableModel struct {
+	MfaToken string `json:"mfa_token"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type TotpCodeModel struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// MfaModel trả về khi đăng nhập cần thêm bước TOTP (hoặc phải đăng ký TOTP trước)
+type MfaModel struct {
+	MfaToken string `json:"mfa_token"`
+}
+
+type TotpSecretModel struct {
+	Secret string `json:"secret"`
+	Uri    string `json:"uri"`
+}
+
+type RecoveryCodeModel struct {
+	RecoveryCodes []string    `json:"recovery_codes"`
+	Token         *TokenModel `json:"token,omitempty"`
+}
+
+type ImpersonateModel struct {
+	Username string `json:"username" validate:"required"`
+}
+
+type LockModel struct {
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+type TokenModel struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Tên bảng trong CSDL
+func (User) TableName() string {
+	return "tbl_user"
+}
+
+func (Role) TableName() string {
+	return "tbl_role"
+}
+
+func (Session) TableName() string {
+	return "tbl_session"
+}
+
+func (RefreshToken) TableName() string {
+	return "tbl_refresh_token"
+}
+
+func (RecoveryCode) TableName() string {
+	return "tbl_recovery_code"
+}
+
+func (LoginAttempt) TableName() string {
+	return "tbl_login_attempt"
+}
+
+func (AuditLog) TableName() string {
+	return "tbl_audit_log"
+}
+
+func (RolePermission) TableName() string {
+	return "tbl_role_permission"
+}
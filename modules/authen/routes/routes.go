@@ -0,0 +1,45 @@
+package routes
+
+import (
+	"app/middleware"
+
+	"app/modules/authen/controller"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func InitAuthenRoutes(app *fiber.App) {
+	authen := app.Group("/authen", middleware.AppInfo)
+
+	authen.Post("/login", controller.Login)
+	authen.Post("/sso", controller.LoginSSO)
+	authen.Put("/sso/link", middleware.AppAuthen, middleware.NoImpersonation, middleware.Require("user:write"), controller.LinkSSO)
+	authen.Post("/login/totp", controller.LoginTotp)
+	authen.Post("/totp/setup", controller.SetupTotp)
+	authen.Post("/totp/enable", controller.EnableTotp)
+	authen.Delete("/totp", middleware.AppAuthen, middleware.NoImpersonation, controller.DisableTotp)
+	authen.Post("/logout", middleware.AppAuthen, controller.Logout)
+	authen.Post("/refresh", controller.Refresh)
+	authen.Post("/password/forgot", controller.ForgotPassword)
+	authen.Post("/password/reset", controller.ResetPassword)
+	authen.Put("/email", middleware.AppAuthen, middleware.NoImpersonation, controller.UpdateEmail)
+	authen.Post("/email/verify", controller.VerifyEmail)
+	authen.Get("/session", middleware.AppAuthen, middleware.NoImpersonation, controller.GetSession)
+	authen.Delete("/session", middleware.AppAuthen, middleware.NoImpersonation, controller.RevokeOtherSession)
+	authen.Delete("/session/:id", middleware.AppAuthen, middleware.NoImpersonation, controller.RevokeSession)
+	authen.Post("/impersonate", middleware.AppAuthen, middleware.NoImpersonation, middleware.Require("user:impersonate"), controller.Impersonate)
+	authen.Get("/impersonate/log", middleware.AppAuthen, middleware.Require("user:impersonate"), controller.GetImpersonateLog)
+
+	lock := authen.Group("/lock", middleware.AppAuthen, middleware.Require("user:unlock"))
+	lock.Get("/", controller.GetLock)
+	lock.Delete("/user/:username", controller.UnlockUser)
+	lock.Delete("/ip/:ip", controller.UnlockIP)
+
+	role := app.Group("/role", middleware.AppInfo, middleware.AppAuthen, middleware.Require("role:write"))
+	role.Get("/", controller.GetRole)
+	role.Get("/permission", controller.GetPermission)
+	role.Post("/", controller.CreateRole)
+	role.Put("/", controller.UpdateRole)
+	role.Put("/user", controller.AssignRole)
+	role.Delete("/:id", controller.DeleteRole)
+}
@@ -0,0 +1,101 @@
+package sso
+
+import (
+	"app/config"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Identity thông tin người dùng do SSO server trả về
+type Identity struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// Client xác thực ticket/code với SSO server.
+// Tách thành interface để test có thể chạy với HTTP server giả lập.
+type Client interface {
+	Verify(ticket string) (*Identity, error)
+}
+
+type verifyRequest struct {
+	AppID   string `json:"app_id"`
+	AppType string `json:"app_type"`
+	Ticket  string `json:"ticket"`
+}
+
+type verifyResponse struct {
+	Status  bool      `json:"status"`
+	Message string    `json:"message"`
+	Data    *Identity `json:"data"`
+}
+
+type httpClient struct {
+	baseURL string
+	appID   string
+	appType string
+	http    *http.Client
+}
+
+var current Client
+
+func NewClient(baseURL, appID, appType string) Client {
+	return &httpClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		appID:   appID,
+		appType: appType,
+		http:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// GetClient trả về client đang dùng, mặc định đọc SSO_BASE_URL / SSO_APP_ID / SSO_APP_TYPE
+func GetClient() Client {
+	if current == nil {
+		current = NewClient(config.Config("SSO_BASE_URL"), config.Config("SSO_APP_ID"), config.Config("SSO_APP_TYPE"))
+	}
+
+	return current
+}
+
+// SetClient thay client mặc định (dùng cho test)
+func SetClient(client Client) {
+	current = client
+}
+
+func (s *httpClient) Verify(ticket string) (*Identity, error) {
+	if len(ticket) == 0 {
+		return nil, errors.New("ticket is empty")
+	}
+
+	body, err := json.Marshal(verifyRequest{AppID: s.appID, AppType: s.appType, Ticket: ticket})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.http.Post(s.baseURL+"/api/verify", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("sso verify: status %d", resp.StatusCode)
+	}
+
+	result := new(verifyResponse)
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return nil, err
+	}
+
+	if !result.Status || result.Data == nil || len(result.Data.UserID) == 0 {
+		return nil, fmt.Errorf("sso verify: %s", result.Message)
+	}
+
+	return result.Data, nil
+}
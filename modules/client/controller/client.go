@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"app/config"
+	"app/database"
+	"app/modules/client/model"
+	"app/utils"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetClient Lấy danh sách API client
+// @Summary Get all API clients
+// @Description Returns a list of all API clients (the key itself is never returned)
+// @Tags Client
+// @Accept json
+// @Produce json
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /client [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetClient(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var clients []model.ApiClient
+	results := database.DB.Order("client_id").Find(&clients)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	response.Data = clients
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// CreateClient Tạo mới API client
+// @Summary Create a new API client
+// @Description Creates an API client and returns its key. The key is shown only once
+// @Tags Client
+// @Accept json
+// @Produce json
+// @Param body body model.CreateClientModel true "New API client information"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /client [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func CreateClient(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.CreateClientModel)
+	if err := c.BodyParser(payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	errors := utils.RequireCheck([]string{"ClientName"}, map[string]string{"ClientName": payload.ClientName}, map[string]string{})
+	if len(errors) > 0 {
+		response.Status = false
+		response.Message = config.GetMessageCode("MISSING_FIELDS")
+		response.ValidateError = errors
+		return c.JSON(response)
+	}
+
+	key, err := utils.RandomString(24)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	newClient := model.ApiClient{
+		ClientName: payload.ClientName,
+		KeyPrefix:  key[:8],
+		KeyHash:    utils.HashKey(key),
+		Scopes:     strings.Join(payload.Scopes, ","),
+		ExpiresAt:  payload.ExpiresAt,
+		CreatedBy:  getUsername(c),
+	}
+
+	if err := database.DB.Create(&newClient).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("CREATE_FAIL")
+		return c.JSON(response)
+	}
+
+	response.Data = model.ClientKeyModel{ClientID: newClient.ID, Key: key}
+	response.Status = true
+	response.Message = config.GetMessageCode("CREATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// RotateClient Cấp key mới cho API client, key cũ hết hiệu lực ngay
+// @Summary Rotate the key of an API client
+// @Description Replaces the key of an API client and returns the new key. The old key stops working immediately
+// @Tags Client
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the API client"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /client/{id}/rotate [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func RotateClient(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var client model.ApiClient
+	if err := database.DB.First(&client, c.Params("id")).Error; err != nil || client.RevokedAt != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	key, err := utils.RandomString(24)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	client.KeyPrefix = key[:8]
+	client.KeyHash = utils.HashKey(key)
+	client.UpdatedBy = getUsername(c)
+	client.LogVersion++
+
+	if err := database.DB.Save(&client).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Data = model.ClientKeyModel{ClientID: client.ID, Key: key}
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// RevokeClient Thu hồi API client
+// @Summary Revoke an API client
+// @Description Revokes the key of an API client without affecting the other clients
+// @Tags Client
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the API client"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /client/{id} [delete]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func RevokeClient(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var client model.ApiClient
+	if err := database.DB.First(&client, c.Params("id")).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	now := time.Now()
+	client.RevokedAt = &now
+	client.RevokedBy = getUsername(c)
+
+	if err := database.DB.Save(&client).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("DELETE_SUCCESS")
+	return c.JSON(response)
+}
+
+func getUsername(c *fiber.Ctx) string {
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		return ""
+	}
+
+	return tokenData.Username
+}
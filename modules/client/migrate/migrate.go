@@ -0,0 +1,14 @@
+package clientMigrate
+
+import (
+	"app/database"
+	model "app/modules/client/model"
+)
+
+func MigrateTbl() bool {
+	db := database.DB
+
+	db.AutoMigrate(&model.ApiClient{})
+
+	return true
+}
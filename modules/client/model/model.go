@@ -0,0 +1,68 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Model struct {
+	ID        uint `gorm:"primarykey;column:client_id;<-:create"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// ApiClient một ứng dụng gọi API bằng header x-csv-key, key chỉ lưu dạng sha256
+type ApiClient struct {
+	Model
+	ClientName string     `gorm:"column:client_name;size:100;not null"`
+	KeyPrefix  string     `gorm:"column:key_prefix;size:8;index"`
+	KeyHash    string     `gorm:"column:key_hash;size:64;not null;uniqueIndex" json:"-"`
+	Scopes     string     `gorm:"column:scopes;size:255"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+	LogVersion int64      `gorm:"column:log_version;default:0"`
+	CreatedBy  string     `gorm:"column:created_by;size:15"`
+	UpdatedBy  string     `gorm:"column:updated_by;size:15"`
+	RevokedBy  string     `gorm:"column:revoked_by;size:15"`
+}
+
+type CreateClientModel struct {
+	ClientName string     `json:"client_name" validate:"required"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+}
+
+type ClientKeyModel struct {
+	ClientID uint   `json:"client_id"`
+	Key      string `json:"key"`
+}
+
+// HasScope scope là đoạn đầu của đường dẫn (team, group, ...), "*" cho phép tất cả
+func (a *ApiClient) HasScope(scope string) bool {
+	for _, item := range strings.Split(a.Scopes, ",") {
+		item = strings.TrimSpace(item)
+		if item == "*" || item == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Active key chưa bị thu hồi và chưa hết hạn
+func (a *ApiClient) Active() bool {
+	if a.RevokedAt != nil {
+		return false
+	}
+
+	return a.ExpiresAt == nil || a.ExpiresAt.After(time.Now())
+}
+
+// Tên bảng trong CSDL
+func (ApiClient) TableName() string {
+	return "tbl_api_client"
+}
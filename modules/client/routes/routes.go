@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"app/middleware"
+
+	"app/modules/client/controller"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func InitClientRoutes(app *fiber.App) {
+	client := app.Group("/client", middleware.AppInfo, middleware.AppAuthen, middleware.Require("client:write"))
+
+	client.Get("/", controller.GetClient)
+	client.Post("/", controller.CreateClient)
+	client.Put("/:id/rotate", controller.RotateClient)
+	client.Delete("/:id", controller.RevokeClient)
+}
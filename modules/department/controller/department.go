@@ -0,0 +1,366 @@
+package controller
+
+import (
+	"app/config"
+	"app/database"
+	"app/modules/department/model"
+	"app/modules/org"
+	"app/utils"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// GetDepartment Lấy danh sách tất cả department
+// @Summary Get all Departments
+// @Description Returns a list of all Departments
+// @Tags Department
+// @Accept json
+// @Produce json
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /department [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetDepartment(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var departments []model.Department
+	results := database.DB.Preload("Translations").Order("department_id").Find(&departments)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	for i := range departments {
+		departments[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = departments
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetAllDepartment Lấy danh sách các department đã bị xoá
+// @Summary Get all Departments (deleted)
+// @Description Returns a list of all Departments (soft-deleted)
+// @Tags Department
+// @Accept json
+// @Produce json
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /department/all [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetAllDepartment(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var departments []model.Department
+	results := database.DB.Unscoped().Preload("Translations").Where("deleted_at IS NOT NULL").Order("department_id").Find(&departments)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	for i := range departments {
+		departments[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = departments
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetDepartmentByID returns information about a Department based on its ID
+// @Summary Get a Department by ID
+// @Description Returns information about a Department based on its ID
+// @Tags Department
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Department"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /department/{id} [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetDepartmentByID(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var department model.Department
+	results := database.DB.Preload("Translations").Where("department_id = ?", c.Params("id")).First(&department)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	department.Localize(utils.Language(c), utils.AllTranslations(c))
+	response.Data = department
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// CreateDepartment Tạo mới department
+// @Summary Create new Departments
+// @Description Creates Departments in one transaction
+// @Tags Department
+// @Accept json
+// @Produce json
+// @Param body body []model.CreateDepartmentModel true "New Department information"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /department [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func CreateDepartment(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.CreateDepartmentModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		errors := checkDepartment(item.DepartmentNameVN, item.DepartmentNameEN, item.DepartmentNameJP, item.DepartmentShortcut)
+
+		if len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("MISSING_FIELDS")
+			response.ValidateError = errors
+			return c.JSON(response)
+		}
+
+		newDepartment := model.Department{
+			DepartmentNameVN:   item.DepartmentNameVN,
+			DepartmentNameEN:   item.DepartmentNameEN,
+			DepartmentNameJP:   item.DepartmentNameJP,
+			DepartmentShortcut: item.DepartmentShortcut,
+			CreatedBy:          getUsername(c),
+		}
+
+		if err := tx.Create(&newDepartment).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("CREATE_FAIL")
+			return c.JSON(response)
+		}
+
+		if err := org.SyncUnit(tx, "department", newDepartment.ID, newDepartment.CreatedBy); err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("CREATE_FAIL")
+			return c.JSON(response)
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("CREATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// UpdateDepartment cập nhật thông tin department
+// @Summary Update Departments
+// @Description Updates Departments based on their ID, or soft deletes them when is_deleted is set
+// @Tags Department
+// @Accept json
+// @Produce json
+// @Param body body []model.UpdateDepartmentModel true "Department information to update"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /department [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func UpdateDepartment(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.UpdateDepartmentModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		var department model.Department
+		if err := tx.First(&department, item.DepartmentID).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+			return c.JSON(response)
+		}
+
+		if item.IsDeleted {
+			department.DeletedBy = getUsername(c)
+			department.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		} else {
+			errors := checkDepartment(item.DepartmentNameVN, item.DepartmentNameEN, item.DepartmentNameJP, item.DepartmentShortcut)
+
+			if len(errors) > 0 {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("MISSING_FIELDS")
+				response.ValidateError = errors
+				return c.JSON(response)
+			}
+
+			department.DepartmentNameVN = item.DepartmentNameVN
+			department.DepartmentNameEN = item.DepartmentNameEN
+			department.DepartmentNameJP = item.DepartmentNameJP
+			department.DepartmentShortcut = item.DepartmentShortcut
+			department.UpdatedBy = getUsername(c)
+			department.LogVersion++
+		}
+
+		if err := tx.Save(&department).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			return c.JSON(response)
+		}
+
+		if err := org.SyncUnit(tx, "department", department.ID, getUsername(c)); err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			return c.JSON(response)
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// DeleteDepartment xóa một Department dựa trên ID
+// @Summary Delete Department
+// @Description Soft deletes a Department based on its ID
+// @Tags Department
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Department"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /department/{id} [delete]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func DeleteDepartment(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var department model.Department
+	if err := database.DB.First(&department, c.Params("id")).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	department.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	department.DeletedBy = getUsername(c)
+
+	tx := database.DB.Begin()
+
+	if err := tx.Model(&department).Updates(&department).Error; err != nil {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	if err := org.SyncUnit(tx, "department", department.ID, department.DeletedBy); err != nil {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("DELETE_SUCCESS")
+	return c.JSON(response)
+}
+
+// RestoreDepartment khôi phục một Department dựa trên ID
+// @Summary Restore Department
+// @Description Restores a soft-deleted Department based on its ID
+// @Tags Department
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Department"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /department/restore/{id} [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func RestoreDepartment(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var department model.Department
+	if err := database.DB.Unscoped().Where("deleted_at IS NOT NULL").First(&department, c.Params("id")).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	results := tx.Unscoped().Model(&department).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"deleted_by": "",
+		"updated_by": getUsername(c),
+	})
+	if results.Error != nil {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("RESTORE_FAIL")
+		return c.JSON(response)
+	}
+
+	if err := org.SyncUnit(tx, "department", department.ID, getUsername(c)); err != nil {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("RESTORE_FAIL")
+		return c.JSON(response)
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("RESTORE_SUCCESS")
+	return c.JSON(response)
+}
+
+func checkDepartment(nameVN, nameEN, nameJP, shortcut string) map[string]string {
+	vItem := map[string]string{
+		"DepartmentNameVN":   nameVN,
+		"DepartmentNameEN":   nameEN,
+		"DepartmentNameJP":   nameJP,
+		"DepartmentShortcut": shortcut,
+	}
+	errors := utils.RequireCheck([]string{"DepartmentNameVN", "DepartmentNameEN", "DepartmentNameJP"}, vItem, map[string]string{})
+
+	return utils.MaxLengthCheck([]string{"DepartmentNameVN:100", "DepartmentNameEN:100", "DepartmentNameJP:100", "DepartmentShortcut:5"}, vItem, errors)
+}
+
+func getUsername(c *fiber.Ctx) string {
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		return ""
+	}
+
+	return tokenData.Username
+}
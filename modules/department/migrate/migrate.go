@@ -0,0 +1,14 @@
+package departmentMigrate
+
+import (
+	"app/database"
+	model "app/modules/department/model"
+)
+
+func MigrateTbl() bool {
+	db := database.DB
+
+	db.AutoMigrate(&model.Department{})
+
+	return true
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"app/config"
+	"app/modules/translation"
+	translationModel "app/modules/translation/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Model struct {
+	ID        uint `gorm:"primarykey;column:department_id;<-:create"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// Department phòng ban, cấp trên của Group
+type Department struct {
+	Model
+	DepartmentNameVN   string                         `gorm:"column:department_name_vn;size:100;not null"`
+	DepartmentNameEN   string                         `gorm:"column:department_name_en;size:100;not null"`
+	DepartmentNameJP   string                         `gorm:"column:department_name_jp;size:100;not null"`
+	Name               string                         `gorm:"-" json:"name"`            // tên theo ngôn ngữ của request, xem Localize
+	Names              map[string]string              `gorm:"-" json:"names,omitempty"` // mọi bản dịch của tên (ngôn ngữ → tên), chỉ trả về khi all
+	Translations       []translationModel.Translation `gorm:"polymorphic:Entity;polymorphicValue:department;constraint:-" json:"-"`
+	DepartmentShortcut string                         `gorm:"column:department_shortcut;size:5"`
+	LogVersion         int64                          `gorm:"column:log_version;default:0"`
+	CreatedBy          string                         `gorm:"column:created_by;size:15"`
+	UpdatedBy          string                         `gorm:"column:updated_by;size:15"`
+	DeletedBy          string                         `gorm:"column:deleted_by;size:15"`
+}
+
+// Localize điền Name theo ngôn ngữ lang (có fallback), all thì trả thêm Names
+func (d *Department) Localize(lang string, all bool) {
+	names := d.names()
+	d.Name = config.LocalValue(lang, names)
+	if all {
+		d.Names = names
+	}
+}
+
+// LocalName tên theo ngôn ngữ lang
+func (d *Department) LocalName(lang string) string {
+	return config.LocalValue(lang, d.names())
+}
+
+// Tên theo các cột vn / en / jp, ghi đè bằng Translations nếu đã preload
+func (d *Department) names() map[string]string {
+	return translation.Values(d.Translations, "name", map[string]string{"vn": d.DepartmentNameVN, "en": d.DepartmentNameEN, "jp": d.DepartmentNameJP})
+}
+
+// AfterSave đồng bộ các cột tên sang tbl_translation
+func (d *Department) AfterSave(tx *gorm.DB) error {
+	return translation.Save(tx, "department", d.ID, "name", map[string]string{"vn": d.DepartmentNameVN, "en": d.DepartmentNameEN, "jp": d.DepartmentNameJP}, d.UpdatedBy)
+}
+
+type CreateDepartmentModel struct {
+	DepartmentNameVN   string `json:"department_name_vn" validate:"required"`
+	DepartmentNameEN   string `json:"department_name_en" validate:"required"`
+	DepartmentNameJP   string `json:"department_name_jp" validate:"required"`
+	DepartmentShortcut string `json:"department_shortcut"`
+}
+
+type UpdateDepartmentModel struct {
+	DepartmentID       uint   `json:"department_id" validate:"required"`
+	DepartmentNameVN   string `json:"department_name_vn" validate:"required"`
+	DepartmentNameEN   string `json:"department_name_en" validate:"required"`
+	DepartmentNameJP   string `json:"department_name_jp" validate:"required"`
+	DepartmentShortcut string `json:"department_shortcut"`
+	IsDeleted          bool   `json:"is_deleted"`
+}
+
+// Tên bảng trong CSDL
+func (Department) TableName() string {
+	return "tbl_department"
+}
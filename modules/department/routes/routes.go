@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"app/middleware"
+
+	"app/modules/department/controller"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func InitDepartmentRoutes(app *fiber.App) {
+	department := app.Group("/department", middleware.AppInfo, middleware.AppAuthen)
+
+	getList := department.Group("")
+	getList.Get("/", middleware.Require("department:read"), controller.GetDepartment)
+	getList.Get("/all", middleware.Require("department:read"), controller.GetAllDepartment) // khai báo trước /:id
+	getList.Get("/:id", middleware.Require("department:read"), controller.GetDepartmentByID)
+
+	department.Post("/", middleware.Require("department:write"), controller.CreateDepartment)
+	department.Put("/", middleware.Require("department:write"), controller.UpdateDepartment)
+	department.Delete("/:id", middleware.Require("department:delete"), controller.DeleteDepartment)
+	department.Put("/restore/:id", middleware.Require("department:restore"), controller.RestoreDepartment)
+}
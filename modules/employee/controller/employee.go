@@ -0,0 +1,497 @@
+package controller
+
+import (
+	"app/config"
+	"app/database"
+	"app/modules/employee/model"
+	teamModel "app/modules/team/model"
+	"app/utils"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// GetEmployee Tìm kiếm nhân viên
+// @Summary Get Employees
+// @Description Returns Employees. keyword matches the employee code exactly or any of the three names partially
+// @Tags Employee
+// @Accept json
+// @Produce json
+// @Param keyword query string false "Employee code or name"
+// @Param team_id query int false "ID of the Team"
+// @Param status query string false "active | leave | terminated"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /employee [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetEmployee(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	query := database.DB.Preload("Translations").Preload("Team.Translations").Order("employee_code")
+	if keyword := c.Query("keyword"); len(keyword) > 0 {
+		like := "%" + keyword + "%"
+		query = query.Where("employee_code = ? OR employee_name_vn ILIKE ? OR employee_name_en ILIKE ? OR employee_name_jp ILIKE ?", keyword, like, like, like)
+	}
+	if teamID := c.Query("team_id"); len(teamID) > 0 {
+		query = query.Where("team_id = ?", teamID)
+	}
+	if status := c.Query("status"); len(status) > 0 {
+		query = query.Where("status = ?", status)
+	}
+
+	var employees []model.Employee
+	if err := query.Find(&employees).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	for i := range employees {
+		employees[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = employees
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetAllEmployee Lấy danh sách các nhân viên đã bị xoá
+// @Summary Get all Employees (deleted)
+// @Description Returns a list of all Employees (soft-deleted)
+// @Tags Employee
+// @Accept json
+// @Produce json
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /employee/all [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetAllEmployee(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var employees []model.Employee
+	results := database.DB.Unscoped().Preload("Translations").Preload("Team.Translations").Where("deleted_at IS NOT NULL").Order("employee_code").Find(&employees)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	for i := range employees {
+		employees[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = employees
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetEmployeeByID returns information about an Employee based on its ID
+// @Summary Get an Employee by ID
+// @Description Returns information about an Employee based on its ID
+// @Tags Employee
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Employee"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /employee/{id} [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetEmployeeByID(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var employee model.Employee
+	results := database.DB.Preload("Translations").Preload("Team.Translations").Where("employee_id = ?", c.Params("id")).First(&employee)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	employee.Localize(utils.Language(c), utils.AllTranslations(c))
+	response.Data = employee
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// CreateEmployee Tạo mới nhân viên
+// @Summary Create new Employees
+// @Description Creates Employees in one transaction
+// @Tags Employee
+// @Accept json
+// @Produce json
+// @Param body body []model.CreateEmployeeModel true "New Employee information"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /employee [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func CreateEmployee(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.CreateEmployeeModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		errors := checkEmployee(tx, item, 0)
+
+		if len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("MISSING_FIELDS")
+			response.ValidateError = errors
+			return c.JSON(response)
+		}
+
+		newEmployee := model.Employee{CreatedBy: getUsername(c)}
+		fillEmployee(&newEmployee, item)
+
+		if err := tx.Create(&newEmployee).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("CREATE_FAIL")
+			return c.JSON(response)
+		}
+
+		// Membership đầu tiên tính từ ngày vào công ty
+		if newEmployee.TeamID != nil {
+			validFrom := today()
+			if newEmployee.HireDate != nil {
+				validFrom = newEmployee.HireDate
+			}
+			if err := transfer(tx, &newEmployee, newEmployee.TeamID, model.RoleMember, *validFrom, getUsername(c)); err != nil {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("CREATE_FAIL")
+				return c.JSON(response)
+			}
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("CREATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// UpdateEmployee cập nhật thông tin nhân viên
+// @Summary Update Employees
+// @Description Updates Employees based on their ID, or soft deletes them when is_deleted is set
+// @Description A Team change is checked against the labor rules like /employee/transfer
+// @Description Deleting or terminating an Employee ends its Team membership (the day after termination_date, or today)
+// @Tags Employee
+// @Accept json
+// @Produce json
+// @Param body body []model.UpdateEmployeeModel true "Employee information to update"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /employee [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func UpdateEmployee(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.UpdateEmployeeModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		var employee model.Employee
+		moved := false
+		if err := tx.First(&employee, item.EmployeeID).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+			return c.JSON(response)
+		}
+
+		if item.IsDeleted {
+			employee.DeletedBy = getUsername(c)
+			employee.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+			if err := leave(tx, &employee, *today(), getUsername(c)); err != nil {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("SYSTEM_ERROR")
+				return c.JSON(response)
+			}
+		} else {
+			errors := checkEmployee(tx, &item.CreateEmployeeModel, employee.ID)
+
+			if len(errors) > 0 {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("MISSING_FIELDS")
+				response.ValidateError = errors
+				return c.JSON(response)
+			}
+
+			currentTeamID := employee.TeamID
+			fillEmployee(&employee, &item.CreateEmployeeModel)
+			employee.Team = nil
+
+			// Đổi team qua API cập nhật: chuyển team từ hôm nay, giữ lịch sử membership
+			moved = !sameTeam(currentTeamID, employee.TeamID)
+			if moved {
+				newTeamID := employee.TeamID
+				employee.TeamID = currentTeamID
+				if err := transfer(tx, &employee, newTeamID, model.RoleMember, *today(), getUsername(c)); err != nil {
+					tx.Rollback()
+					response.Status = false
+					response.Message = config.GetMessageCode("SYSTEM_ERROR")
+					return c.JSON(response)
+				}
+			}
+
+			// Nghỉ việc: rời team từ ngày sau ngày nghỉ (chưa có ngày nghỉ thì từ hôm nay)
+			if employee.TerminationDate != nil || employee.Status == model.StatusTerminated {
+				from := *today()
+				if employee.TerminationDate != nil {
+					from = employee.TerminationDate.AddDate(0, 0, 1)
+				}
+				if err := leave(tx, &employee, from, getUsername(c)); err != nil {
+					tx.Rollback()
+					response.Status = false
+					response.Message = config.GetMessageCode("SYSTEM_ERROR")
+					return c.JSON(response)
+				}
+			}
+			employee.UpdatedBy = getUsername(c)
+			employee.LogVersion++
+		}
+
+		if err := tx.Save(&employee).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			return c.JSON(response)
+		}
+
+		if moved {
+			if errors, err := checkLabor(tx, employee.ID, employee.TeamID, *today()); err != nil || len(errors) > 0 {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("SYSTEM_ERROR")
+				if err == nil {
+					response.Message = config.GetMessageCode("MISSING_FIELDS")
+					response.ValidateError = errors
+				}
+				return c.JSON(response)
+			}
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// DeleteEmployee xóa một Employee dựa trên ID
+// @Summary Delete Employee
+// @Description Soft deletes an Employee based on its ID and ends its Team membership today
+// @Tags Employee
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Employee"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /employee/{id} [delete]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func DeleteEmployee(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var employee model.Employee
+	if err := database.DB.First(&employee, c.Params("id")).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	employee.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	employee.DeletedBy = getUsername(c)
+
+	// Nhân viên bị xoá không còn thuộc team nào từ hôm nay
+	err := leave(tx, &employee, *today(), employee.DeletedBy)
+	if err == nil {
+		err = tx.Model(&employee).Select("team_id", "deleted_at", "deleted_by").Updates(&employee).Error
+	}
+	if err != nil {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("DELETE_SUCCESS")
+	return c.JSON(response)
+}
+
+// RestoreEmployee khôi phục một Employee dựa trên ID
+// @Summary Restore Employee
+// @Description Restores a soft-deleted Employee based on its ID
+// @Tags Employee
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Employee"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /employee/restore/{id} [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func RestoreEmployee(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var employee model.Employee
+	if err := database.DB.Unscoped().Where("deleted_at IS NOT NULL").First(&employee, c.Params("id")).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	// Mã nhân viên có thể đã được cấp lại cho người khác
+	var count int64
+	database.DB.Model(&model.Employee{}).Where("employee_code = ?", employee.EmployeeCode).Count(&count)
+	if count > 0 {
+		response.Status = false
+		response.Message = config.GetMessageCode("CODE_EXISTS")
+		return c.JSON(response)
+	}
+
+	results := database.DB.Unscoped().Model(&employee).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"deleted_by": "",
+		"updated_by": getUsername(c),
+	})
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("RESTORE_FAIL")
+		return c.JSON(response)
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("RESTORE_SUCCESS")
+	return c.JSON(response)
+}
+
+// Kiểm tra dữ liệu nhân viên, excludeID là nhân viên đang được cập nhật (0 khi tạo mới)
+func checkEmployee(tx *gorm.DB, item *model.CreateEmployeeModel, excludeID uint) map[string]string {
+	vItem := map[string]string{
+		"EmployeeCode":    item.EmployeeCode,
+		"EmployeeNameVN":  item.EmployeeNameVN,
+		"EmployeeNameEN":  item.EmployeeNameEN,
+		"EmployeeNameJP":  item.EmployeeNameJP,
+		"Email":           item.Email,
+		"Phone":           item.Phone,
+		"HireDate":        item.HireDate,
+		"TerminationDate": item.TerminationDate,
+	}
+	errors := utils.RequireCheck([]string{"EmployeeCode", "EmployeeNameVN", "EmployeeNameEN", "EmployeeNameJP"}, vItem, map[string]string{})
+	errors = utils.NumberCheck([]string{"EmployeeCode"}, vItem, errors)
+	errors = utils.MaxLengthCheck([]string{"EmployeeCode:15", "EmployeeNameVN:100", "EmployeeNameEN:100", "EmployeeNameJP:100", "Email:100", "Phone:20"}, vItem, errors)
+
+	for _, key := range []string{"HireDate", "TerminationDate"} {
+		if len(vItem[key]) > 0 {
+			errors = utils.DateFormatCheck([]string{key}, vItem, errors)
+		}
+	}
+
+	hireDate, terminationDate := parseDate(item.HireDate), parseDate(item.TerminationDate)
+	if _, ok := errors["TerminationDate"]; !ok && hireDate != nil && terminationDate != nil && terminationDate.Before(*hireDate) {
+		errors["TerminationDate"] = config.GetMessageCode("VALUE_INVALID")
+	}
+
+	switch item.Status {
+	case "", model.StatusActive, model.StatusLeave, model.StatusTerminated:
+	default:
+		errors["Status"] = config.GetMessageCode("VALUE_INVALID")
+	}
+
+	if _, ok := errors["EmployeeCode"]; !ok {
+		var count int64
+		tx.Model(&model.Employee{}).Where("employee_code = ? AND employee_id <> ?", item.EmployeeCode, excludeID).Count(&count)
+		if count > 0 {
+			errors["EmployeeCode"] = config.GetMessageCode("CODE_EXISTS")
+		}
+	}
+
+	if item.TeamID != nil {
+		var count int64
+		tx.Model(&teamModel.Team{}).Where("team_id = ?", *item.TeamID).Count(&count)
+		if count == 0 {
+			errors["TeamID"] = config.GetMessageCode("NOT_ID_EXISTS")
+		}
+	}
+
+	return errors
+}
+
+func fillEmployee(employee *model.Employee, item *model.CreateEmployeeModel) {
+	employee.EmployeeCode = item.EmployeeCode
+	employee.EmployeeNameVN = item.EmployeeNameVN
+	employee.EmployeeNameEN = item.EmployeeNameEN
+	employee.EmployeeNameJP = item.EmployeeNameJP
+	employee.Email = item.Email
+	employee.Phone = item.Phone
+	employee.HireDate = parseDate(item.HireDate)
+	employee.TerminationDate = parseDate(item.TerminationDate)
+	employee.TeamID = item.TeamID
+
+	// Có ngày nghỉ việc mà không gửi trạng thái thì coi như đã nghỉ việc
+	employee.Status = item.Status
+	if len(employee.Status) == 0 {
+		employee.Status = model.StatusActive
+		if employee.TerminationDate != nil {
+			employee.Status = model.StatusTerminated
+		}
+	}
+}
+
+func sameTeam(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+func parseDate(value string) *time.Time {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil
+	}
+
+	return &date
+}
+
+func getUsername(c *fiber.Ctx) string {
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		return ""
+	}
+
+	return tokenData.Username
+}
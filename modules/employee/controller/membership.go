@@ -0,0 +1,325 @@
+package controller
+
+import (
+	"app/config"
+	"app/database"
+	"app/modules/employee/model"
+	"app/modules/labor"
+	teamModel "app/modules/team/model"
+	"app/utils"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errTransferDate = errors.New("valid_from is before the current membership")
+
+// GetTeamMember Danh sách thành viên của team tại một ngày
+// @Summary Get members of a Team on a date
+// @Description Returns who was in the Team on the given date (default today) with their role. team_id is required
+// @Tags Employee
+// @Accept json
+// @Produce json
+// @Param team_id query int true "ID of the Team"
+// @Param date query string false "YYYY-MM-DD"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /employee/membership [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetTeamMember(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	teamID, err := strconv.Atoi(c.Query("team_id"))
+	if err != nil || teamID <= 0 {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	date := today()
+	if len(c.Query("date")) > 0 {
+		if date = parseDate(c.Query("date")); date == nil {
+			response.Status = false
+			response.Message = config.GetMessageCode("FORMAT_DATE")
+			return c.JSON(response)
+		}
+	}
+
+	var memberships []model.TeamMembership
+	results := database.DB.Preload("Employee.Translations").
+		Where("team_id = ? AND valid_from <= ? AND (valid_to IS NULL OR valid_to >= ?)", teamID, *date, *date).
+		Order("role DESC, employee_id").
+		Find(&memberships)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	for i := range memberships {
+		memberships[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = memberships
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetEmployeeMembership Lịch sử team của nhân viên
+// @Summary Get the Team history of an Employee
+// @Description Returns the Teams the Employee belonged to during the period. Without from / to returns the whole history
+// @Tags Employee
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Employee"
+// @Param from query string false "YYYY-MM-DD"
+// @Param to query string false "YYYY-MM-DD"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /employee/{id}/membership [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetEmployeeMembership(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	query := database.DB.Preload("Team.Translations").Where("employee_id = ?", c.Params("id")).Order("valid_from")
+	if from := c.Query("from"); len(from) > 0 {
+		date := parseDate(from)
+		if date == nil {
+			response.Status = false
+			response.Message = config.GetMessageCode("FORMAT_DATE")
+			return c.JSON(response)
+		}
+		query = query.Where("valid_to IS NULL OR valid_to >= ?", *date)
+	}
+	if to := c.Query("to"); len(to) > 0 {
+		date := parseDate(to)
+		if date == nil {
+			response.Status = false
+			response.Message = config.GetMessageCode("FORMAT_DATE")
+			return c.JSON(response)
+		}
+		query = query.Where("valid_from <= ?", *date)
+	}
+
+	var memberships []model.TeamMembership
+	if err := query.Find(&memberships).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	for i := range memberships {
+		memberships[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = memberships
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// TransferEmployee Chuyển team / đổi vai trò
+// @Summary Transfer Employees
+// @Description Closes the current membership the day before valid_from and opens the new one, all items in one transaction.
+// @Description Send the same team_id with another role to change the role, or no team_id to leave the team.
+// @Description The shifts of the new Team's rosters are checked against the labor rules (see /labor), violations are returned per Employee and date in validate_error
+// @Tags Employee
+// @Accept json
+// @Produce json
+// @Param body body []model.TransferModel true "Transfers"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /employee/transfer [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func TransferEmployee(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.TransferModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		validFrom := today()
+		errors := map[string]string{}
+		if len(item.ValidFrom) > 0 {
+			if validFrom = parseDate(item.ValidFrom); validFrom == nil {
+				errors["ValidFrom"] = config.GetMessageCode("FORMAT_DATE")
+			}
+		}
+		errors = checkMembership(tx, item.TeamID, item.Role, errors)
+
+		if len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("MISSING_FIELDS")
+			response.ValidateError = errors
+			return c.JSON(response)
+		}
+
+		var employee model.Employee
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&employee, item.EmployeeID).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+			return c.JSON(response)
+		}
+
+		if err := transfer(tx, &employee, item.TeamID, item.Role, *validFrom, getUsername(c)); err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			if err == errTransferDate {
+				response.Message = config.GetMessageCode("VALUE_INVALID")
+				response.ValidateError = map[string]string{"ValidFrom": config.GetMessageCode("VALUE_INVALID")}
+			}
+			return c.JSON(response)
+		}
+
+		if err := tx.Model(&employee).Select("team_id").Updates(&employee).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			return c.JSON(response)
+		}
+
+		if errors, err := checkLabor(tx, employee.ID, item.TeamID, *validFrom); err != nil || len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			if err == nil {
+				response.Message = config.GetMessageCode("MISSING_FIELDS")
+				response.ValidateError = errors
+			}
+			return c.JSON(response)
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// Kiểm tra luật lao động (labor.Check) cho các ca của team mới từ validFrom, membership đã lưu trong tx.
+// teamID nil (rời team) thì không có ca mới để kiểm tra
+func checkLabor(tx *gorm.DB, employeeID uint, teamID *uint, validFrom time.Time) (map[string]string, error) {
+	if teamID == nil {
+		return map[string]string{}, nil
+	}
+	from, to, _ := labor.Window(validFrom, nil)
+
+	return labor.Check(tx, labor.ByTeam(*teamID), []uint{employeeID}, from, to, map[string]string{})
+}
+
+func checkMembership(tx *gorm.DB, teamID *uint, role string, errors map[string]string) map[string]string {
+	switch role {
+	case "", model.RoleMember, model.RoleLeader:
+	default:
+		errors["Role"] = config.GetMessageCode("VALUE_INVALID")
+	}
+
+	if teamID != nil {
+		var count int64
+		tx.Model(&teamModel.Team{}).Where("team_id = ?", *teamID).Count(&count)
+		if count == 0 {
+			errors["TeamID"] = config.GetMessageCode("NOT_ID_EXISTS")
+		}
+	}
+
+	return errors
+}
+
+// Đóng membership đang mở vào ngày trước validFrom và mở membership mới (teamID nil: chỉ đóng).
+// employee.TeamID là team hiện tại, chỉ đổi khi validFrom không ở tương lai. Caller lưu employee.
+func transfer(tx *gorm.DB, employee *model.Employee, teamID *uint, role string, validFrom time.Time, username string) error {
+	if len(role) == 0 {
+		role = model.RoleMember
+	}
+
+	// Đã có membership bắt đầu sau validFrom thì không chèn vào giữa lịch sử
+	var later int64
+	tx.Model(&model.TeamMembership{}).Where("employee_id = ? AND valid_from > ?", employee.ID, validFrom).Count(&later)
+	if later > 0 {
+		return errTransferDate
+	}
+
+	var current model.TeamMembership
+	err := tx.Where("employee_id = ? AND valid_to IS NULL", employee.ID).First(&current).Error
+	switch {
+	case err == gorm.ErrRecordNotFound:
+	case err != nil:
+		return err
+	case teamID != nil && current.TeamID == *teamID && current.Role == role:
+		return nil
+	case current.ValidFrom.Equal(validFrom):
+		// Mở và đóng cùng ngày: membership cũ chưa có hiệu lực ngày nào, xoá luôn
+		if err := tx.Delete(&current).Error; err != nil {
+			return err
+		}
+	default:
+		validTo := validFrom.AddDate(0, 0, -1)
+		if err := tx.Model(&current).Updates(model.TeamMembership{ValidTo: &validTo, UpdatedBy: username}).Error; err != nil {
+			return err
+		}
+	}
+
+	if teamID != nil {
+		membership := model.TeamMembership{
+			EmployeeID: employee.ID,
+			TeamID:     *teamID,
+			Role:       role,
+			ValidFrom:  validFrom,
+			CreatedBy:  username,
+		}
+		if err := tx.Create(&membership).Error; err != nil {
+			return err
+		}
+	}
+
+	if !validFrom.After(*today()) {
+		employee.TeamID = teamID
+	}
+
+	return nil
+}
+
+// Nhân viên nghỉ việc / bị xoá: từ ngày from không còn thuộc team nào.
+// Membership bắt đầu từ from trở đi (chuyển team đã lên lịch) bị xoá, các membership còn lại kết thúc trước from.
+func leave(tx *gorm.DB, employee *model.Employee, from time.Time, username string) error {
+	if err := tx.Where("employee_id = ? AND valid_from >= ?", employee.ID, from).Delete(&model.TeamMembership{}).Error; err != nil {
+		return err
+	}
+
+	validTo := from.AddDate(0, 0, -1)
+	err := tx.Model(&model.TeamMembership{}).
+		Where("employee_id = ? AND (valid_to IS NULL OR valid_to >= ?)", employee.ID, from).
+		Updates(map[string]interface{}{"valid_to": validTo, "updated_by": username}).Error
+	if err != nil {
+		return err
+	}
+
+	if !from.After(*today()) {
+		employee.TeamID = nil
+	}
+
+	return nil
+}
+
+func today() *time.Time {
+	date, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	return &date
+}
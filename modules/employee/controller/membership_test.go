@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"app/config"
+	"app/database/testdb"
+	"app/modules/employee/model"
+	laborModel "app/modules/labor/model"
+	rosterModel "app/modules/roster/model"
+	teamModel "app/modules/team/model"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestGetTeamMemberParam(t *testing.T) {
+	testdb.Chdir(t)
+
+	app := fiber.New()
+	app.Get("/employee/membership", GetTeamMember)
+
+	for _, query := range []string{"", "?team_id=", "?team_id=abc", "?team_id=0", "?team_id=-1&date=2024-01-01"} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/employee/membership"+query, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var response config.DataResponse
+		json.NewDecoder(resp.Body).Decode(&response)
+		resp.Body.Close()
+		if response.Status || response.Message != config.GetMessageCode("PARAM_ERROR") {
+			t.Errorf("%q: got %v %s, want PARAM_ERROR", query, response.Status, response.Message)
+		}
+	}
+}
+
+func TestTransferEmployeeLabor(t *testing.T) {
+	t.Setenv("APP_TIME_ZONE", "UTC")
+	db := testdb.Open(t, testdb.OrgModels, testdb.EmployeeModels, testdb.ShiftModels, &rosterModel.Roster{}, &rosterModel.RosterException{}, &laborModel.LaborRule{})
+
+	org := testdb.SeedOrg(t, db)
+	teams := []teamModel.Team{org.Team, testdb.SeedTeam(t, db, org.Group.ID, "Night"), testdb.SeedTeam(t, db, org.Group.ID, "Spare")}
+	employee := testdb.SeedEmployee(t, db, "1001", &teams[0].ID)
+	testdb.SeedMembership(t, db, employee.ID, teams[0].ID, today().AddDate(0, 0, -7))
+
+	morning := testdb.SeedShift(t, db, "Morning", "08:00-17:00")
+	night := testdb.SeedShift(t, db, "Night", "22:00-06:00")
+	// Nhân viên có ca sáng riêng, team đêm làm ca đêm mỗi ngày
+	db.Create(&[]rosterModel.Roster{
+		{ShiftID: morning.ID, EmployeeID: &employee.ID, RRule: "FREQ=DAILY", StartDate: *today()},
+		{ShiftID: night.ID, TeamID: &teams[1].ID, RRule: "FREQ=DAILY", StartDate: *today()},
+	})
+
+	app := fiber.New()
+	app.Post("/employee/transfer", TransferEmployee)
+	transfer := func(teamID uint) config.DataResponse {
+		body := fmt.Sprintf(`[{"employee_id":%d,"team_id":%d}]`, employee.ID, teamID)
+		request := httptest.NewRequest(http.MethodPost, "/employee/transfer", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		var response config.DataResponse
+		json.NewDecoder(resp.Body).Decode(&response)
+		return response
+	}
+
+	// Ca đêm của team mới chỉ cách ca sáng 5 tiếng: rollback cả membership
+	response := transfer(teams[1].ID)
+	key := fmt.Sprintf("Employee[%d].Date[%s]", employee.ID, today().Format("2006-01-02"))
+	errors, _ := response.ValidateError.(map[string]interface{})
+	if response.Status || response.Message != config.GetMessageCode("MISSING_FIELDS") || errors[key] != config.GetMessageCode("REST_TOO_SHORT") {
+		t.Fatalf("night team: got %v %s %v, want REST_TOO_SHORT on %s", response.Status, response.Message, response.ValidateError, key)
+	}
+	var count int64
+	db.Model(&model.TeamMembership{}).Where("employee_id = ? AND team_id = ?", employee.ID, teams[1].ID).Count(&count)
+	if db.First(&employee, employee.ID); count > 0 || *employee.TeamID != teams[0].ID {
+		t.Fatalf("transfer not rolled back: team %d, %d memberships", *employee.TeamID, count)
+	}
+
+	// Team không có roster thì chuyển được
+	if response := transfer(teams[2].ID); !response.Status {
+		t.Fatalf("spare team: got %s %v", response.Message, response.ValidateError)
+	}
+}
+
+func TestLeave(t *testing.T) {
+	t.Setenv("APP_TIME_ZONE", "UTC")
+	db := testdb.Open(t, testdb.OrgModels, testdb.EmployeeModels, &rosterModel.Roster{}, &rosterModel.RosterException{}, &laborModel.LaborRule{})
+
+	org := testdb.SeedOrg(t, db)
+	night := testdb.SeedTeam(t, db, org.Group.ID, "Night")
+	employees := []model.Employee{}
+	for _, code := range []string{"1001", "1002", "1003"} {
+		employee := testdb.SeedEmployee(t, db, code, &org.Team.ID)
+		// Đang ở team A, đã lên lịch chuyển sang team đêm sau 10 ngày
+		first := testdb.SeedMembership(t, db, employee.ID, org.Team.ID, today().AddDate(0, 0, -30))
+		db.Model(&first).Update("valid_to", today().AddDate(0, 0, 9))
+		testdb.SeedMembership(t, db, employee.ID, night.ID, today().AddDate(0, 0, 10))
+		employees = append(employees, employee)
+	}
+
+	app := fiber.New()
+	app.Put("/employee", UpdateEmployee)
+	app.Delete("/employee/:id", DeleteEmployee)
+	send := func(method, target, body string) {
+		t.Helper()
+
+		request := httptest.NewRequest(method, target, strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		var response config.DataResponse
+		json.NewDecoder(resp.Body).Decode(&response)
+		if !response.Status {
+			t.Fatalf("%s %s: got %s %v", method, target, response.Message, response.ValidateError)
+		}
+	}
+
+	send(http.MethodDelete, fmt.Sprintf("/employee/%d", employees[0].ID), "")
+	send(http.MethodPut, "/employee", fmt.Sprintf(`[{"employee_id":%d,"is_deleted":true}]`, employees[1].ID))
+	// Nghỉ việc sau 5 ngày: vẫn ở team A đến hết ngày nghỉ
+	terminationDate := today().AddDate(0, 0, 5).Format("2006-01-02")
+	send(http.MethodPut, "/employee", fmt.Sprintf(`[{"employee_id":%d,"employee_code":"1003","employee_name_vn":"1003","employee_name_en":"1003","employee_name_jp":"1003","team_id":%d,"termination_date":"%s"}]`,
+		employees[2].ID, org.Team.ID, terminationDate))
+
+	cases := []struct {
+		name    string
+		ends    string
+		stillIn bool
+	}{
+		{"delete", today().AddDate(0, 0, -1).Format("2006-01-02"), false},
+		{"update is_deleted", today().AddDate(0, 0, -1).Format("2006-01-02"), false},
+		{"terminate", terminationDate, true},
+	}
+	for i, item := range cases {
+		var memberships []model.TeamMembership
+		db.Where("employee_id = ?", employees[i].ID).Find(&memberships)
+		if len(memberships) != 1 || memberships[0].TeamID != org.Team.ID || memberships[0].ValidTo == nil || memberships[0].ValidTo.Format("2006-01-02") != item.ends {
+			t.Errorf("%s: got memberships %+v, want one ending %s", item.name, memberships, item.ends)
+		}
+
+		var employee model.Employee
+		db.Unscoped().First(&employee, employees[i].ID)
+		if (employee.TeamID != nil) != item.stillIn {
+			t.Errorf("%s: got team %v, want still in team = %v", item.name, employee.TeamID, item.stillIn)
+		}
+	}
+}
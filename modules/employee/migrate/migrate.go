@@ -1,14 +1,30 @@
-package teamMigrate
+package employeeMigrate
 
 import (
 	"app/database"
-	model "app/modules/team/model"
+	model "app/modules/employee/model"
 )
 
 func MigrateTbl() bool {
 	db := database.DB
 
-	db.AutoMigrate(&model.Team{})
+	// Index unique cũ tính cả nhân viên đã xoá, thay bằng idx_employee_code_active
+	if db.Migrator().HasIndex(&model.Employee{}, "idx_tbl_employee_employee_code") {
+		db.Migrator().DropIndex(&model.Employee{}, "idx_tbl_employee_employee_code")
+	}
+
+	db.AutoMigrate(&model.Employee{}, &model.TeamMembership{})
+
+	seedMembership()
 
 	return true
 }
+
+// Nhân viên có team_id nhưng chưa có lịch sử membership: mở membership từ ngày vào công ty (hoặc ngày tạo)
+func seedMembership() {
+	database.DB.Exec(`INSERT INTO tbl_team_membership (employee_id, team_id, role, valid_from, created_at, created_by)
+		SELECT e.employee_id, e.team_id, ?, COALESCE(e.hire_date, e.created_at::date), NOW(), e.created_by
+		FROM tbl_employee e
+		WHERE e.team_id IS NOT NULL AND e.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM tbl_team_membership m WHERE m.employee_id = e.employee_id)`, model.RoleMember)
+}
@@ -1,63 +1,146 @@
 package model
 
 import (
+	"app/config"
+	"app/modules/translation"
+	translationModel "app/modules/translation/model"
 	"time"
+
+	teamModel "app/modules/team/model"
+
 	"gorm.io/gorm"
-	"app/modules/group/model"
 )
 
 type Model struct {
-	ID        uint `gorm:"primarykey;column:team_id;<-:create"`
+	ID        uint `gorm:"primarykey;column:employee_id;<-:create"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
+// Trạng thái nhân viên
+const (
+	StatusActive     = "active"
+	StatusLeave      = "leave"
+	StatusTerminated = "terminated"
+)
 
-type Team struct {
+// Employee nhân viên, EmployeeCode là mã nhân viên (vd: 1105), trùng với username đăng nhập.
+// Mã chỉ duy nhất trong các nhân viên chưa xoá, mã của nhân viên đã xoá có thể cấp lại
+type Employee struct {
 	Model
-	GroupID  int               `gorm:"column:group_id;not null"`
-	Group    model.Group  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	TeamNameVN   string            `gorm:"column:team_name_vn;size:100;not null"`
-	TeamNameEN   string            `gorm:"column:team_name_en;size:100;not null"`
-	TeamNameJP   string            `gorm:"column:team_name_jp;size:100;not null"`
-	TeamShortcut string            `gorm:"column:team_shortcut;size:5"`
-	LogVersion    int64             `gorm:"column:log_version;default:0"`
-	CreatedBy  string     `gorm:"column:created_by;size:15"`
-	UpdatedBy  string     `gorm:"column:updated_by;size:15"`	
-	DeletedBy  string     `gorm:"column:deleted_by;size:15"`
+	EmployeeCode    string                         `gorm:"column:employee_code;size:15;not null;uniqueIndex:idx_employee_code_active,where:deleted_at IS NULL"`
+	EmployeeNameVN  string                         `gorm:"column:employee_name_vn;size:100;not null"`
+	EmployeeNameEN  string                         `gorm:"column:employee_name_en;size:100;not null"`
+	EmployeeNameJP  string                         `gorm:"column:employee_name_jp;size:100;not null"`
+	Name            string                         `gorm:"-" json:"name"`            // tên theo ngôn ngữ của request, xem Localize
+	Names           map[string]string              `gorm:"-" json:"names,omitempty"` // mọi bản dịch của tên (ngôn ngữ → tên), chỉ trả về khi all
+	Translations    []translationModel.Translation `gorm:"polymorphic:Entity;polymorphicValue:employee;constraint:-" json:"-"`
+	Email           string                         `gorm:"column:email;size:100"`
+	Phone           string                         `gorm:"column:phone;size:20"`
+	HireDate        *time.Time                     `gorm:"column:hire_date;type:date"`
+	TerminationDate *time.Time                     `gorm:"column:termination_date;type:date"`
+	Status          string                         `gorm:"column:status;size:10;not null;default:active;index"`
+	TeamID          *uint                          `gorm:"column:team_id;index"`
+	Team            *teamModel.Team                `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	LogVersion      int64                          `gorm:"column:log_version;default:0"`
+	CreatedBy       string                         `gorm:"column:created_by;size:15"`
+	UpdatedBy       string                         `gorm:"column:updated_by;size:15"`
+	DeletedBy       string                         `gorm:"column:deleted_by;size:15"`
 }
 
+// Localize điền Name theo ngôn ngữ lang (có fallback), all thì trả thêm Names
+func (e *Employee) Localize(lang string, all bool) {
+	names := e.names()
+	e.Name = config.LocalValue(lang, names)
+	if all {
+		e.Names = names
+	}
+	if e.Team != nil {
+		e.Team.Localize(lang, all)
+	}
+}
 
-type CreateTeamModel struct {
-	GroupID  int    `json:"group_id" validate:"required"`
-	TeamNameVN   string `json:"team_name_vn" validate:"required"`
-	TeamNameEN   string `json:"team_name_en" validate:"required"`
-	TeamNameJP   string `json:"team_name_jp" validate:"required"`
-	TeamShortcut string `json:"team_shortcut"`
-	CreatedBy     string `json:"created_by"`
+// LocalName tên theo ngôn ngữ lang
+func (e *Employee) LocalName(lang string) string {
+	return config.LocalValue(lang, e.names())
 }
 
-type UpdateTeamModel struct {
-	TeamID       int    `json:"team_id" validate:"required"`
-	GroupID  int    `json:"group_id" validate:"required"`
-	TeamNameVN   string `json:"team_name_vn" validate:"required"`
-	TeamNameEN   string `json:"team_name_en" validate:"required"`
-	TeamNameJP   string `json:"team_name_jp" validate:"required"`
-	TeamShortcut string `json:"team_shortcut"`
-	IsDeleted     bool   `json:"is_deleted"`
-	UpdatedBy     string `json:"updated_by"`
+// Tên theo các cột vn / en / jp, ghi đè bằng Translations nếu đã preload
+func (e *Employee) names() map[string]string {
+	return translation.Values(e.Translations, "name", map[string]string{"vn": e.EmployeeNameVN, "en": e.EmployeeNameEN, "jp": e.EmployeeNameJP})
 }
 
-// type HeaderTeam struct {
-// 	CreatedBy  string     `gorm:"column:created_by;size:15"`
-// 	UpdatedBy  string     `gorm:"column:updated_by;size:15"`	
-// 	DeletedBy  string     `gorm:"column:deleted_by;size:15"`
-// }
+// AfterSave đồng bộ các cột tên sang tbl_translation
+func (e *Employee) AfterSave(tx *gorm.DB) error {
+	return translation.Save(tx, "employee", e.ID, "name", map[string]string{"vn": e.EmployeeNameVN, "en": e.EmployeeNameEN, "jp": e.EmployeeNameJP}, e.UpdatedBy)
+}
 
+// Vai trò trong team
+const (
+	RoleMember = "member"
+	RoleLeader = "leader"
+)
+
+// TeamMembership nhân viên thuộc team trong khoảng [ValidFrom, ValidTo], ValidTo nil là đến hiện tại.
+// Không sửa / xoá bản ghi cũ, chuyển team thì đóng bản ghi đang mở và mở bản ghi mới
+type TeamMembership struct {
+	ID         uint            `gorm:"primarykey;column:membership_id;<-:create" json:"membership_id"`
+	EmployeeID uint            `gorm:"column:employee_id;not null;index" json:"employee_id"`
+	Employee   *Employee       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"employee,omitempty"`
+	TeamID     uint            `gorm:"column:team_id;not null;index" json:"team_id"`
+	Team       *teamModel.Team `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"team,omitempty"`
+	Role       string          `gorm:"column:role;size:10;not null;default:member" json:"role"`
+	ValidFrom  time.Time       `gorm:"column:valid_from;type:date;not null;index" json:"valid_from"`
+	ValidTo    *time.Time      `gorm:"column:valid_to;type:date;index" json:"valid_to"`
+	CreatedAt  time.Time       `json:"created_at"`
+	CreatedBy  string          `gorm:"column:created_by;size:15" json:"created_by"`
+	UpdatedBy  string          `gorm:"column:updated_by;size:15" json:"updated_by"`
+}
 
+// Localize điền Name của nhân viên và team đã preload
+func (m *TeamMembership) Localize(lang string, all bool) {
+	if m.Employee != nil {
+		m.Employee.Localize(lang, all)
+	}
+	if m.Team != nil {
+		m.Team.Localize(lang, all)
+	}
+}
+
+type CreateEmployeeModel struct {
+	EmployeeCode    string `json:"employee_code" validate:"required"`
+	EmployeeNameVN  string `json:"employee_name_vn" validate:"required"`
+	EmployeeNameEN  string `json:"employee_name_en" validate:"required"`
+	EmployeeNameJP  string `json:"employee_name_jp" validate:"required"`
+	Email           string `json:"email"`
+	Phone           string `json:"phone"`
+	HireDate        string `json:"hire_date"`        // YYYY-MM-DD
+	TerminationDate string `json:"termination_date"` // YYYY-MM-DD
+	Status          string `json:"status"`           // active | leave | terminated
+	TeamID          *uint  `json:"team_id"`
+}
+
+type UpdateEmployeeModel struct {
+	EmployeeID uint `json:"employee_id" validate:"required"`
+	CreateEmployeeModel
+	IsDeleted bool `json:"is_deleted"`
+}
+
+// TransferModel chuyển nhân viên sang team khác (hoặc đổi vai trò) từ ngày ValidFrom.
+// TeamID nil: rời team
+type TransferModel struct {
+	EmployeeID uint   `json:"employee_id" validate:"required"`
+	TeamID     *uint  `json:"team_id"`
+	Role       string `json:"role"`       // member | leader
+	ValidFrom  string `json:"valid_from"` // YYYY-MM-DD, mặc định hôm nay
+}
 
 // Tên bảng trong CSDL
-func (Team) TableName() string {
-	return "tbl_team"
+func (Employee) TableName() string {
+	return "tbl_employee"
+}
+
+func (TeamMembership) TableName() string {
+	return "tbl_team_membership"
 }
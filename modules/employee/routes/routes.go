@@ -3,23 +3,24 @@ package routes
 import (
 	"app/middleware"
 
-	"app/modules/team/controller"
+	"app/modules/employee/controller"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func InitTeamRoutes(app *fiber.App) {
-	team := app.Group("/team", middleware.AppInfo, middleware.AppAuthen)
+func InitEmployeeRoutes(app *fiber.App) {
+	employee := app.Group("/employee", middleware.AppInfo, middleware.AppAuthen)
 
-	getList := team.Group("")
-	getList.Get("/", controller.GetTeam)
-	getList.Get("/all",controller.GetAllTeam) //error nếu swap get :id trước
-	getList.Get("/:id", controller.GetTeamByID)
+	getList := employee.Group("")
+	getList.Get("/", middleware.Require("employee:read"), controller.GetEmployee)
+	getList.Get("/all", middleware.Require("employee:read"), controller.GetAllEmployee) // khai báo trước /:id
+	getList.Get("/membership", middleware.Require("employee:read"), controller.GetTeamMember)
+	getList.Get("/:id", middleware.Require("employee:read"), controller.GetEmployeeByID)
+	getList.Get("/:id/membership", middleware.Require("employee:read"), controller.GetEmployeeMembership)
 
-
-	
-	team.Post("/", controller.CreateTeam)
-	team.Put("/", controller.UpdateTeam)
-	team.Delete("/:id", controller.DeleteTeam)
-	team.Put("/restore/:id",controller.RestoreTeam)
+	employee.Post("/", middleware.Require("employee:write"), controller.CreateEmployee)
+	employee.Put("/", middleware.Require("employee:write"), controller.UpdateEmployee)
+	employee.Post("/transfer", middleware.Require("employee:write"), controller.TransferEmployee)
+	employee.Delete("/:id", middleware.Require("employee:delete"), controller.DeleteEmployee)
+	employee.Put("/restore/:id", middleware.Require("employee:restore"), controller.RestoreEmployee)
 }
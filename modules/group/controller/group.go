@@ -0,0 +1,597 @@
+package controller
+
+import (
+	"app/config"
+	"app/database"
+	modell "app/modules/department/model"
+	"app/modules/group/model"
+	"app/modules/history"
+	"app/modules/org"
+	"app/utils"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"os"
+	"strconv"
+	"time"
+)
+
+type tempGroup model.Group
+
+// Tên loại bản ghi trong tbl_version_history
+const historyType = "group"
+
+// GetGroup Lấy danh sách tất cả group
+// @Summary Get all Groups
+// @Description Returns a list of all Groups
+// @Tags Group
+// @Accept json
+// @Produce json
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /group [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetGroup(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var groups []model.Group
+	results := database.DB.Select("*").Preload("Translations").Preload("Department.Translations").Order("group_id").Find(&groups)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	for i := range groups {
+		groups[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = groups
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetAllGroup Lấy danh sách các group đã bị xoá
+// @Summary Get all Groups (deleted)
+// @Description Returns a list of all Groups (soft-deleted)
+// @Tags Group
+// @Accept json
+// @Produce json
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /group/all [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetAllGroup(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var groups []model.Group
+	results := database.DB.Select("*").Unscoped().Preload("Translations").Preload("Department.Translations").Where("deleted_at IS NOT NULL").Order("group_id").Find(&groups)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	for i := range groups {
+		groups[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = groups
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetGroupByID returns information about a Group based on its ID
+// @Summary Get a Group by ID
+// @Description Returns information about a Group based on its ID.
+// @Description With as_of (YYYY-MM-DD or RFC 3339) returns the version that was current at that time instead
+// @Tags Group
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Group"
+// @Param as_of query string false "Point in time"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /group/{id} [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetGroupByID(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	if asOf := c.Query("as_of"); len(asOf) > 0 {
+		return getGroupAsOf(c, asOf)
+	}
+
+	var group model.Group
+	results := database.DB.Select("*").Preload("Translations").Preload("Department.Translations").Where("group_id = ?", c.Params("id")).First(&group)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	group.Localize(utils.Language(c), utils.AllTranslations(c))
+	response.Data = group
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetGroupHistory Lịch sử phiên bản của group
+// @Summary Get the version history of a Group
+// @Description Returns every version of the Group, newest first, with snapshot, diff, actor and timestamp
+// @Tags Group
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Group"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /group/{id}/history [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetGroupHistory(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	groupID, _ := strconv.Atoi(c.Params("id"))
+	rows, err := history.List(database.DB, historyType, uint(groupID))
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	response.Data = rows
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// CreateGroup Tạo mới 1 group
+// @Summary Create a new Group
+// @Description Creates a new Group
+// @Tags Group
+// @Accept json
+// @Produce json
+// @Param body body []model.CreateGroupModel true "New Group information"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /group [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func CreateGroup(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.CreateGroupModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		errors := checkGroup(tx, item.DepartmentID, item.GroupNameVN, item.GroupNameEN, item.GroupNameJP)
+
+		if len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("MISSING_FIELDS")
+			response.ValidateError = errors
+			return c.JSON(response)
+		}
+
+		newGroup := model.Group{
+			DepartmentID:  item.DepartmentID,
+			GroupNameVN:   item.GroupNameVN,
+			GroupNameEN:   item.GroupNameEN,
+			GroupNameJP:   item.GroupNameJP,
+			GroupShortcut: item.GroupShortcut,
+			CreatedBy:     getUsername(c),
+		}
+
+		if err := tx.Create(&newGroup).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("CREATE_FAIL")
+			return c.JSON(response)
+		}
+
+		if err := history.Record(tx, historyType, newGroup.ID, newGroup.LogVersion, history.ActionCreate, nil, newGroup, newGroup.CreatedBy); err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("CREATE_FAIL")
+			return c.JSON(response)
+		}
+
+		if err := org.SyncUnit(tx, historyType, newGroup.ID, newGroup.CreatedBy); err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("CREATE_FAIL")
+			return c.JSON(response)
+		}
+
+		tempNewGroup := tempGroup(newGroup)
+		if err := tempNewGroup.AfterCreate(tx); err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("AFTER_CREATE_FAIL")
+			return c.JSON(response)
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("CREATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// UpdateGroup cập nhật thông tin một Group dựa trên ID
+// @Summary Cập nhật thông tin Group
+// @Description Cập nhật thông tin một Group dựa trên ID. Mỗi lần cập nhật tăng log_version và ghi một dòng lịch sử
+// @Tags Group
+// @Accept json
+// @Produce json
+// @Param body body []model.UpdateGroupModel true "Thông tin Group cần cập nhật"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /group [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func UpdateGroup(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.UpdateGroupModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		// Không có group_id thì tạo mới
+		if item.GroupID == 0 {
+			newGroup := model.Group{
+				DepartmentID:  item.DepartmentID,
+				GroupNameVN:   item.GroupNameVN,
+				GroupNameEN:   item.GroupNameEN,
+				GroupNameJP:   item.GroupNameJP,
+				GroupShortcut: item.GroupShortcut,
+				CreatedBy:     getUsername(c),
+			}
+
+			if errors := checkGroup(tx, item.DepartmentID, item.GroupNameVN, item.GroupNameEN, item.GroupNameJP); len(errors) > 0 {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("MISSING_FIELDS")
+				response.ValidateError = errors
+				return c.JSON(response)
+			}
+
+			if err := tx.Create(&newGroup).Error; err != nil {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("CREATE_FAIL")
+				return c.JSON(response)
+			}
+
+			if err := history.Record(tx, historyType, newGroup.ID, newGroup.LogVersion, history.ActionCreate, nil, newGroup, newGroup.CreatedBy); err != nil {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("CREATE_FAIL")
+				return c.JSON(response)
+			}
+
+			if err := org.SyncUnit(tx, historyType, newGroup.ID, newGroup.CreatedBy); err != nil {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("CREATE_FAIL")
+				return c.JSON(response)
+			}
+			continue
+		}
+
+		action := history.ActionUpdate
+		if item.IsDeleted {
+			action = history.ActionDelete
+		}
+
+		if message, errors := updateGroup(tx, item, getUsername(c), action); len(message) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode(message)
+			response.ValidateError = errors
+			return c.JSON(response)
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// RevertGroup đưa group về phiên bản cũ
+// @Summary Revert a Group to a version
+// @Description Copies the fields of version N back through the normal update path, which creates a new version (action revert)
+// @Tags Group
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Group"
+// @Param version path int true "Version to revert to"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /group/{id}/revert/{version} [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func RevertGroup(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	groupID, _ := strconv.Atoi(c.Params("id"))
+	version, _ := strconv.ParseInt(c.Params("version"), 10, 64)
+
+	row, err := history.Version(database.DB, historyType, uint(groupID), version)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	var snapshot model.Group
+	if err := json.Unmarshal([]byte(row.Snapshot), &snapshot); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	item := &model.UpdateGroupModel{
+		GroupID:       groupID,
+		DepartmentID:  snapshot.DepartmentID,
+		GroupNameVN:   snapshot.GroupNameVN,
+		GroupNameEN:   snapshot.GroupNameEN,
+		GroupNameJP:   snapshot.GroupNameJP,
+		GroupShortcut: snapshot.GroupShortcut,
+	}
+
+	tx := database.DB.Begin()
+	if message, errors := updateGroup(tx, item, getUsername(c), history.ActionRevert); len(message) > 0 {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode(message)
+		response.ValidateError = errors
+		return c.JSON(response)
+	}
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// DeleteGroup xóa một Group dựa trên ID
+// @Summary Xóa Group
+// @Description Xóa một Group dựa trên ID
+// @Tags Group
+// @Accept json
+// @Produce json
+// @Param id path int true "ID của Group"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /group/{id} [delete]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func DeleteGroup(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	groupID, _ := strconv.Atoi(c.Params("id"))
+
+	tx := database.DB.Begin()
+	if message, _ := updateGroup(tx, &model.UpdateGroupModel{GroupID: groupID, IsDeleted: true}, getUsername(c), history.ActionDelete); len(message) > 0 {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode(message)
+		return c.JSON(response)
+	}
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("DELETE_SUCCESS")
+	return c.JSON(response)
+}
+
+// RestoreGroup khôi phục một Group dựa trên ID
+// @Summary Khôi phục Group
+// @Description Khôi phục một Group dựa trên ID
+// @Tags Group
+// @Accept json
+// @Produce json
+// @Param id path int true "ID của Group"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /group/{id}/restore [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func RestoreGroup(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	tx := database.DB.Begin()
+
+	var group model.Group
+	result := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&group, c.Params("id"))
+	if result.Error != nil {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	before := group
+	group.DeletedAt = gorm.DeletedAt{}
+	group.DeletedBy = ""
+	group.UpdatedBy = getUsername(c)
+	group.LogVersion++
+
+	if err := tx.Unscoped().Save(&group).Error; err != nil {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("RESTORE_FAIL")
+		return c.JSON(response)
+	}
+
+	if err := history.Record(tx, historyType, group.ID, group.LogVersion, history.ActionRestore, before, group, group.UpdatedBy); err != nil {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("RESTORE_FAIL")
+		return c.JSON(response)
+	}
+
+	if err := org.SyncUnit(tx, historyType, group.ID, group.UpdatedBy); err != nil {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("RESTORE_FAIL")
+		return c.JSON(response)
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("RESTORE_SUCCESS")
+	return c.JSON(response)
+}
+
+// Đường cập nhật chung của PUT /group, DELETE /group/:id và revert: tăng log_version và ghi lịch sử.
+// Trả về mã message (rỗng nếu thành công) và lỗi validate
+func updateGroup(tx *gorm.DB, item *model.UpdateGroupModel, username, action string) (string, map[string]string) {
+	var group model.Group
+	if err := tx.First(&group, item.GroupID).Error; err != nil {
+		return "NOT_ID_EXISTS", nil
+	}
+	before := group
+
+	if item.IsDeleted {
+		group.DeletedBy = username
+		group.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	} else {
+		errors := checkGroup(tx, item.DepartmentID, item.GroupNameVN, item.GroupNameEN, item.GroupNameJP)
+		if len(errors) > 0 {
+			return "MISSING_FIELDS", errors
+		}
+
+		group.DepartmentID = item.DepartmentID
+		group.GroupNameVN = item.GroupNameVN
+		group.GroupNameEN = item.GroupNameEN
+		group.GroupNameJP = item.GroupNameJP
+		group.GroupShortcut = item.GroupShortcut
+		group.UpdatedBy = username
+	}
+	group.LogVersion++
+
+	if err := tx.Save(&group).Error; err != nil {
+		return "SYSTEM_ERROR", nil
+	}
+
+	if err := history.Record(tx, historyType, group.ID, group.LogVersion, action, before, group, username); err != nil {
+		return "SYSTEM_ERROR", nil
+	}
+
+	if err := org.SyncUnit(tx, historyType, group.ID, username); err != nil {
+		return "SYSTEM_ERROR", nil
+	}
+
+	return "", nil
+}
+
+// Group tại thời điểm asOf, lấy từ snapshot trong lịch sử
+func getGroupAsOf(c *fiber.Ctx, asOf string) error {
+	response := new(config.DataResponse)
+
+	// Chỉ có ngày thì tính đến hết ngày đó
+	at, err := time.Parse(time.RFC3339, asOf)
+	if err != nil {
+		date, dateErr := time.Parse("2006-01-02", asOf)
+		if dateErr != nil {
+			response.Status = false
+			response.Message = config.GetMessageCode("FORMAT_DATE")
+			return c.JSON(response)
+		}
+		at = date.AddDate(0, 0, 1)
+	}
+
+	groupID, _ := strconv.Atoi(c.Params("id"))
+	row, err := history.AsOf(database.DB, historyType, uint(groupID), at)
+	if err != nil || row == nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+
+	response.Data = row
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+func checkGroup(tx *gorm.DB, departmentID int, nameVN, nameEN, nameJP string) map[string]string {
+	listCheck := []string{"GroupNameVN", "GroupNameEN", "GroupNameJP"}
+	vItem := map[string]string{
+		"GroupNameVN": nameVN,
+		"GroupNameEN": nameEN,
+		"GroupNameJP": nameJP,
+	}
+	errors := utils.RequireCheck(listCheck, vItem, map[string]string{})
+
+	var department modell.Department
+	if err := tx.Where("department_id = ?", departmentID).First(&department).Error; err != nil {
+		errors["DepartmentID"] = config.GetMessageCode("NOT_ID_EXISTS")
+	}
+
+	return errors
+}
+
+func (g *tempGroup) AfterCreate(tx *gorm.DB) (err error) {
+	if g.CreatedBy == "1105" {
+		fmt.Println(">>>>  it's created by Admin....")
+		WriteLog(">>>>  it's created by Admin....")
+	}
+	return nil
+}
+
+func WriteLog(logEntry string) error {
+	// Tạo một timestamp để thêm vào log
+	timestamp := time.Now().Format("2006-01-02 15:04:05")
+	logEntryWithTimestamp := fmt.Sprintf("[%s] %s\n", timestamp, logEntry)
+
+	// Mở file log.txt trong chế độ append
+	file, err := os.OpenFile("assets/log/log.txt", os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Ghi log vào file
+	_, err = file.WriteString(logEntryWithTimestamp)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func getUsername(c *fiber.Ctx) string {
+	response := new(config.DataResponse)
+
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("ERROR_GET_USERNAME")
+	}
+
+	return tokenData.Username
+}
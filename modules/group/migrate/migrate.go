@@ -0,0 +1,22 @@
+package groupMigrate
+
+import (
+	"app/database"
+	model "app/modules/group/model"
+	"app/modules/history"
+)
+
+func MigrateTbl() bool {
+	db := database.DB
+
+	db.AutoMigrate(&model.Group{})
+
+	// Group có từ trước khi bật lịch sử: ghi phiên bản hiện tại làm mốc
+	var groups []model.Group
+	db.Unscoped().Find(&groups)
+	for _, item := range groups {
+		history.Baseline(db, "group", item.ID, item.LogVersion, item, item.UpdatedAt)
+	}
+
+	return true
+}
@@ -0,0 +1,90 @@
+package model
+
+import (
+	"app/config"
+	"time"
+	"gorm.io/gorm"
+	"app/modules/department/model"
+	"app/modules/translation"
+	translationModel "app/modules/translation/model"
+)
+
+type Model struct {
+	ID        uint `gorm:"primarykey;column:group_id;<-:create"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+
+type Group struct {
+	Model
+	DepartmentID  int               `gorm:"column:department_id;not null"`
+	Department    model.Department  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	GroupNameVN   string            `gorm:"column:group_name_vn;size:100;not null"`
+	GroupNameEN   string            `gorm:"column:group_name_en;size:100;not null"`
+	GroupNameJP   string            `gorm:"column:group_name_jp;size:100;not null"`
+	Name          string            `gorm:"-" json:"name"` // tên theo ngôn ngữ của request, xem Localize
+	Names         map[string]string `gorm:"-" json:"names,omitempty"` // mọi bản dịch của tên (ngôn ngữ → tên), chỉ trả về khi all
+	Translations  []translationModel.Translation `gorm:"polymorphic:Entity;polymorphicValue:group;constraint:-" json:"-"`
+	GroupShortcut string            `gorm:"column:group_shortcut;size:5"`
+	LogVersion    int64             `gorm:"column:log_version;default:0"`
+	CreatedBy  string     `gorm:"column:created_by;size:15"`
+	UpdatedBy  string     `gorm:"column:updated_by;size:15"`
+	DeletedBy  string     `gorm:"column:deleted_by;size:15"`
+}
+
+// Localize điền Name theo ngôn ngữ lang (có fallback), all thì trả thêm Names
+func (g *Group) Localize(lang string, all bool) {
+	names := g.names()
+	g.Name = config.LocalValue(lang, names)
+	if all {
+		g.Names = names
+	}
+	if g.Department.ID != 0 {
+		g.Department.Localize(lang, all)
+	}
+}
+
+// LocalName tên theo ngôn ngữ lang
+func (g *Group) LocalName(lang string) string {
+	return config.LocalValue(lang, g.names())
+}
+
+// Tên theo các cột vn / en / jp, ghi đè bằng Translations nếu đã preload
+func (g *Group) names() map[string]string {
+	return translation.Values(g.Translations, "name", map[string]string{"vn": g.GroupNameVN, "en": g.GroupNameEN, "jp": g.GroupNameJP})
+}
+
+// AfterSave đồng bộ các cột tên sang tbl_translation
+func (g *Group) AfterSave(tx *gorm.DB) error {
+	return translation.Save(tx, "group", g.ID, "name", map[string]string{"vn": g.GroupNameVN, "en": g.GroupNameEN, "jp": g.GroupNameJP}, g.UpdatedBy)
+}
+
+
+type CreateGroupModel struct {
+	DepartmentID  int    `json:"department_id" validate:"required"`
+	GroupNameVN   string `json:"group_name_vn" validate:"required"`
+	GroupNameEN   string `json:"group_name_en" validate:"required"`
+	GroupNameJP   string `json:"group_name_jp" validate:"required"`
+	GroupShortcut string `json:"group_shortcut"`
+	CreatedBy     string `json:"created_by"`
+}
+
+type UpdateGroupModel struct {
+	GroupID       int    `json:"group_id" validate:"required"`
+	DepartmentID  int    `json:"department_id" validate:"required"`
+	GroupNameVN   string `json:"group_name_vn" validate:"required"`
+	GroupNameEN   string `json:"group_name_en" validate:"required"`
+	GroupNameJP   string `json:"group_name_jp" validate:"required"`
+	GroupShortcut string `json:"group_shortcut"`
+	IsDeleted     bool   `json:"is_deleted"`
+	UpdatedBy     string `json:"updated_by"`
+}
+
+
+
+// Tên bảng trong CSDL
+func (Group) TableName() string {
+	return "tbl_group"
+}
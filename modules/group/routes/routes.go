@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"app/middleware"
+
+	"app/modules/group/controller"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func InitGroupRoutes(app *fiber.App) {
+	group := app.Group("/group", middleware.AppInfo, middleware.AppAuthen)
+
+	getList := group.Group("")
+	getList.Get("/", middleware.Require("group:read"), controller.GetGroup)
+	getList.Get("/all", middleware.Require("group:read"), controller.GetAllGroup) //error nếu swap get :id trước
+	getList.Get("/:id", middleware.Require("group:read"), controller.GetGroupByID)
+	getList.Get("/:id/history", middleware.Require("group:read"), controller.GetGroupHistory)
+
+
+	
+	group.Post("/", middleware.Require("group:write"), controller.CreateGroup)
+	group.Put("/", middleware.Require("group:write"), controller.UpdateGroup)
+	group.Put("/:id/revert/:version", middleware.Require("group:write"), controller.RevertGroup)
+	group.Delete("/:id", middleware.Require("group:delete"), controller.DeleteGroup)
+	group.Put("/restore/:id", middleware.Require("group:restore"), controller.RestoreGroup)
+}
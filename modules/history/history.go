@@ -0,0 +1,154 @@
+package history
+
+import (
+	"app/modules/history/model"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Thao tác ghi vào lịch sử
+const (
+	ActionBaseline = "baseline"
+	ActionCreate   = "create"
+	ActionUpdate   = "update"
+	ActionDelete   = "delete"
+	ActionRestore  = "restore"
+	ActionRevert   = "revert"
+)
+
+// Các trường tự thay đổi mỗi lần lưu, không đưa vào diff
+var ignoreDiff = map[string]bool{"UpdatedAt": true, "LogVersion": true, "UpdatedBy": true}
+
+// Record ghi một phiên bản mới của bản ghi. before nil khi tạo mới
+func Record(tx *gorm.DB, entityType string, entityID uint, version int64, action string, before, after interface{}, actor string) error {
+	snapshot, err := toMap(after)
+	if err != nil {
+		return err
+	}
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	row := model.VersionHistory{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Version:    version,
+		Action:     action,
+		Snapshot:   model.JSON(snapshotJSON),
+		Actor:      actor,
+	}
+
+	if before != nil {
+		previous, err := toMap(before)
+		if err != nil {
+			return err
+		}
+		diffJSON, err := json.Marshal(diff(previous, snapshot))
+		if err != nil {
+			return err
+		}
+		row.Diff = model.JSON(diffJSON)
+	}
+
+	return tx.Create(&row).Error
+}
+
+// AsOf phiên bản mới nhất tạo trước thời điểm at, nil nếu bản ghi chưa tồn tại lúc đó
+func AsOf(db *gorm.DB, entityType string, entityID uint, at time.Time) (*model.VersionHistory, error) {
+	var row model.VersionHistory
+	results := db.Where("entity_type = ? AND entity_id = ? AND created_at < ?", entityType, entityID, at).
+		Order("version DESC").
+		First(&row)
+	if results.Error == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &row, nil
+}
+
+// Version lấy đúng phiên bản version của bản ghi
+func Version(db *gorm.DB, entityType string, entityID uint, version int64) (*model.VersionHistory, error) {
+	var row model.VersionHistory
+	results := db.Where("entity_type = ? AND entity_id = ? AND version = ?", entityType, entityID, version).First(&row)
+	if results.Error != nil {
+		return nil, results.Error
+	}
+
+	return &row, nil
+}
+
+// List toàn bộ lịch sử của bản ghi, phiên bản mới nhất trước
+func List(db *gorm.DB, entityType string, entityID uint) ([]model.VersionHistory, error) {
+	var rows []model.VersionHistory
+	results := db.Where("entity_type = ? AND entity_id = ?", entityType, entityID).Order("version DESC").Find(&rows)
+	return rows, results.Error
+}
+
+// Struct → map theo JSON, bỏ các quan hệ (giá trị dạng object) để snapshot chỉ chứa cột của bảng
+func toMap(value interface{}) (map[string]interface{}, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{}
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, err
+	}
+
+	for key, field := range result {
+		if _, ok := field.(map[string]interface{}); ok {
+			delete(result, key)
+		}
+	}
+
+	return result, nil
+}
+
+func diff(before, after map[string]interface{}) map[string]interface{} {
+	changes := map[string]interface{}{}
+	for key, value := range after {
+		if ignoreDiff[key] || reflect.DeepEqual(before[key], value) {
+			continue
+		}
+		changes[key] = map[string]interface{}{"from": before[key], "to": value}
+	}
+
+	return changes
+}
+
+// Baseline ghi phiên bản hiện tại của bản ghi đã có từ trước khi bật lịch sử, thời điểm là lần cập nhật cuối
+func Baseline(tx *gorm.DB, entityType string, entityID uint, version int64, value interface{}, at time.Time) error {
+	var count int64
+	if err := tx.Model(&model.VersionHistory{}).Where("entity_type = ? AND entity_id = ?", entityType, entityID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	snapshot, err := toMap(value)
+	if err != nil {
+		return err
+	}
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&model.VersionHistory{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Version:    version,
+		Action:     ActionBaseline,
+		Snapshot:   model.JSON(snapshotJSON),
+		CreatedAt:  at,
+	}).Error
+}
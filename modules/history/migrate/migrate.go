@@ -0,0 +1,14 @@
+package historyMigrate
+
+import (
+	"app/database"
+	model "app/modules/history/model"
+)
+
+func MigrateTbl() bool {
+	db := database.DB
+
+	db.AutoMigrate(&model.VersionHistory{})
+
+	return true
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// JSON chuỗi JSON lưu trong cột jsonb, trả ra API nguyên dạng (không bị escape thành string)
+type JSON string
+
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+
+	return []byte(j), nil
+}
+
+func (j JSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+
+	return string(j), nil
+}
+
+func (j *JSON) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*j = ""
+	case string:
+		*j = JSON(v)
+	case []byte:
+		*j = JSON(v)
+	default:
+		return fmt.Errorf("unsupported type %T for JSON", value)
+	}
+
+	return nil
+}
+
+// VersionHistory một phiên bản của bản ghi, chỉ thêm mới, không sửa / xoá.
+// Version bằng LogVersion của bản ghi sau khi thay đổi
+type VersionHistory struct {
+	ID         uint      `gorm:"primarykey;column:history_id;<-:create" json:"history_id"`
+	EntityType string    `gorm:"column:entity_type;size:20;not null;uniqueIndex:idx_version_history_entity" json:"entity_type"`
+	EntityID   uint      `gorm:"column:entity_id;not null;uniqueIndex:idx_version_history_entity" json:"entity_id"`
+	Version    int64     `gorm:"column:version;not null;uniqueIndex:idx_version_history_entity" json:"version"`
+	Action     string    `gorm:"column:action;size:10;not null" json:"action"`
+	Snapshot   JSON      `gorm:"column:snapshot;type:jsonb;not null" json:"snapshot"`
+	Diff       JSON      `gorm:"column:diff;type:jsonb" json:"diff"`
+	Actor      string    `gorm:"column:actor;size:15" json:"actor"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+}
+
+// Tên bảng trong CSDL
+func (VersionHistory) TableName() string {
+	return "tbl_version_history"
+}
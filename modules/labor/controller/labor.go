@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"app/config"
+	"app/database"
+	departmentModel "app/modules/department/model"
+	"app/modules/labor"
+	"app/modules/labor/model"
+	"app/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// GetLaborRule Lấy danh sách ngưỡng luật lao động
+// @Summary Get Labor rules
+// @Description Returns the labor rules per Department (department_id null is the default for every Department)
+// @Description and the default from .env used when no rule is saved
+// @Tags Labor
+// @Accept json
+// @Produce json
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /labor [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetLaborRule(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var rules []model.LaborRule
+	results := database.DB.Preload("Department.Translations").Order("department_id NULLS FIRST").Find(&rules)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	for i := range rules {
+		rules[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = map[string]interface{}{
+		"default": labor.Default(),
+		"rules":   rules,
+	}
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// SaveLaborRule lưu ngưỡng luật lao động
+// @Summary Save Labor rules
+// @Description Creates or replaces the rule of each Department (no department_id: the default for every Department).
+// @Description min_rest_minutes, max_week_minutes and max_consecutive_days, 0 is not checked. Only checked on new assignments
+// @Tags Labor
+// @Accept json
+// @Produce json
+// @Param body body []model.SaveLaborRuleModel true "Labor rules"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /labor [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func SaveLaborRule(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.SaveLaborRuleModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		errors := checkLaborRule(tx, item)
+
+		if len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("MISSING_FIELDS")
+			response.ValidateError = errors
+			return c.JSON(response)
+		}
+
+		var rule model.LaborRule
+		query := tx.Where("department_id IS NULL")
+		if item.DepartmentID != nil {
+			query = tx.Where("department_id = ?", *item.DepartmentID)
+		}
+		if err := query.First(&rule).Error; err != nil {
+			if err != gorm.ErrRecordNotFound {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("SYSTEM_ERROR")
+				return c.JSON(response)
+			}
+			rule = model.LaborRule{DepartmentID: item.DepartmentID, CreatedBy: getUsername(c)}
+		}
+
+		rule.MinRestMinutes = item.MinRestMinutes
+		rule.MaxWeekMinutes = item.MaxWeekMinutes
+		rule.MaxConsecutiveDays = item.MaxConsecutiveDays
+		rule.UpdatedBy = getUsername(c)
+
+		if err := tx.Save(&rule).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			return c.JSON(response)
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// DeleteLaborRule xóa ngưỡng luật lao động dựa trên ID
+// @Summary Delete Labor rule
+// @Description Deletes a Labor rule, its Department goes back to the default rule
+// @Tags Labor
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Labor rule"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /labor/{id} [delete]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func DeleteLaborRule(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var rule model.LaborRule
+	if err := database.DB.First(&rule, c.Params("id")).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	if err := database.DB.Delete(&rule).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("DELETE_SUCCESS")
+	return c.JSON(response)
+}
+
+func checkLaborRule(tx *gorm.DB, item *model.SaveLaborRuleModel) map[string]string {
+	errors := map[string]string{}
+	if item.DepartmentID != nil {
+		if err := tx.First(&departmentModel.Department{}, *item.DepartmentID).Error; err != nil {
+			errors["DepartmentID"] = config.GetMessageCode("NOT_ID_EXISTS")
+		}
+	}
+
+	values := map[string]int{
+		"MinRestMinutes":     item.MinRestMinutes,
+		"MaxWeekMinutes":     item.MaxWeekMinutes,
+		"MaxConsecutiveDays": item.MaxConsecutiveDays,
+	}
+	for key, value := range values {
+		if value < 0 {
+			errors[key] = config.GetMessageCode("VALUE_INVALID")
+		}
+	}
+
+	return errors
+}
+
+func getUsername(c *fiber.Ctx) string {
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		return ""
+	}
+
+	return tokenData.Username
+}
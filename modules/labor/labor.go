@@ -0,0 +1,257 @@
+package labor
+
+import (
+	"app/config"
+	"app/modules/labor/model"
+	"app/modules/roster"
+	rosterModel "app/modules/roster/model"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Default ngưỡng khi chưa cấu hình tbl_labor_rule, lấy từ .env
+func Default() model.LaborRule {
+	return model.LaborRule{
+		MinRestMinutes:     envInt("LABOR_MIN_REST", 11*60),
+		MaxWeekMinutes:     envInt("LABOR_MAX_WEEK", 48*60),
+		MaxConsecutiveDays: envInt("LABOR_MAX_CONSECUTIVE_DAYS", 6),
+	}
+}
+
+// Rules ngưỡng áp dụng cho từng nhân viên theo department của team hiện tại: riêng của department, không có thì mặc định
+func Rules(db *gorm.DB, employeeIDs []uint) (map[uint]model.LaborRule, error) {
+	var rows []struct {
+		EmployeeID   uint
+		DepartmentID *uint
+	}
+	err := db.Table("tbl_employee e").
+		Select("e.employee_id, g.department_id").
+		Joins("LEFT JOIN tbl_team t ON t.team_id = e.team_id").
+		Joins("LEFT JOIN tbl_group g ON g.group_id = t.group_id").
+		Where("e.employee_id IN ?", employeeIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var saved []model.LaborRule
+	if err := db.Find(&saved).Error; err != nil {
+		return nil, err
+	}
+	fallback := Default()
+	byDepartment := map[uint]model.LaborRule{}
+	for _, rule := range saved {
+		if rule.DepartmentID == nil {
+			fallback = rule
+		} else {
+			byDepartment[*rule.DepartmentID] = rule
+		}
+	}
+
+	rules := map[uint]model.LaborRule{}
+	for _, id := range employeeIDs {
+		rules[id] = fallback
+	}
+	for _, row := range rows {
+		if row.DepartmentID == nil {
+			continue
+		}
+		if rule, ok := byDepartment[*row.DepartmentID]; ok {
+			rules[row.EmployeeID] = rule
+		}
+	}
+
+	return rules, nil
+}
+
+// Window khoảng ngày cần kiểm tra khi xếp roster [start, end] (end nil là không hết hạn):
+// từ hôm nay (hoặc start nếu sau hôm nay), tối đa LABOR_CHECK_DAYS ngày. ok = false nếu roster đã hết hạn
+func Window(start time.Time, end *time.Time) (time.Time, time.Time, bool) {
+	from, _ := time.Parse("2006-01-02", time.Now().In(roster.Location()).Format("2006-01-02"))
+	if start.After(from) {
+		from = start
+	}
+	to := from.AddDate(0, 0, envInt("LABOR_CHECK_DAYS", 28)-1)
+	if end != nil && end.Before(to) {
+		to = *end
+	}
+
+	return from, to, !to.Before(from)
+}
+
+// ByRoster các ca sinh từ roster rosterID
+func ByRoster(rosterID uint) func(rosterModel.ShiftInstance) bool {
+	return func(instance rosterModel.ShiftInstance) bool { return instance.RosterID == rosterID }
+}
+
+// ByTeam các ca sinh từ roster của team teamID
+func ByTeam(teamID uint) func(rosterModel.ShiftInstance) bool {
+	return func(instance rosterModel.ShiftInstance) bool {
+		return instance.TeamID != nil && *instance.TeamID == teamID
+	}
+}
+
+// ByShift các ca làm theo mẫu ca shiftID (kể cả ca được swap sang mẫu ca này)
+func ByShift(shiftID uint) func(rosterModel.ShiftInstance) bool {
+	return func(instance rosterModel.ShiftInstance) bool { return instance.ShiftID == shiftID }
+}
+
+// Check kiểm tra trùng ca, nghỉ tối thiểu, số giờ trong tuần và số ngày làm liên tiếp của các nhân viên employeeIDs
+// sau khi thay đổi (đã lưu trong db). Chỉ báo vi phạm có ca thoả match (ByRoster, ByTeam, ByShift) trong [from, to].
+// Mỗi vi phạm là một key của errors giống utils.RequireCheck: Employee[id].Date[YYYY-MM-DD] hoặc Employee[id].Week[thứ hai của tuần]
+func Check(db *gorm.DB, match func(rosterModel.ShiftInstance) bool, employeeIDs []uint, from, to time.Time, errors map[string]string) (map[string]string, error) {
+	if len(employeeIDs) == 0 {
+		return errors, nil
+	}
+	rules, err := Rules(db, employeeIDs)
+	if err != nil {
+		return errors, err
+	}
+
+	for _, employeeID := range employeeIDs {
+		rule := rules[employeeID]
+		// Lấy thêm các ngày trước / sau để tính đủ tuần và chuỗi ngày liên tiếp
+		padding := 7
+		if rule.MaxConsecutiveDays+1 > padding {
+			padding = rule.MaxConsecutiveDays + 1
+		}
+		instances, err := roster.Expand(db, from.AddDate(0, 0, -padding), to.AddDate(0, 0, padding), employeeID, 0, "")
+		if err != nil {
+			return errors, err
+		}
+		sort.SliceStable(instances, func(i, j int) bool { return instances[i].Start.Before(instances[j].Start) })
+
+		focus := func(instance rosterModel.ShiftInstance) bool {
+			date, _ := time.Parse("2006-01-02", instance.Date)
+			return match(instance) && !date.Before(from) && !date.After(to)
+		}
+		errors = RestCheck(employeeID, instances, rule, focus, errors)
+		errors = WeekCheck(employeeID, instances, rule, focus, errors)
+		errors = ConsecutiveCheck(employeeID, instances, rule, focus, errors)
+	}
+
+	return errors, nil
+}
+
+// RestCheck trùng ca và thời gian nghỉ giữa hai ca liên tiếp (instances đã sắp theo Start)
+func RestCheck(employeeID uint, instances []rosterModel.ShiftInstance, rule model.LaborRule, focus func(rosterModel.ShiftInstance) bool, errors map[string]string) map[string]string {
+	if len(instances) == 0 {
+		return errors
+	}
+
+	// So với ca kết thúc muộn nhất trước đó
+	previous := instances[0]
+	for i := 1; i < len(instances); i++ {
+		if instances[i-1].End.After(previous.End) {
+			previous = instances[i-1]
+		}
+		current := instances[i]
+		if !focus(current) && !focus(previous) {
+			continue
+		}
+
+		key := dateKey(employeeID, current)
+		if !focus(current) {
+			key = dateKey(employeeID, previous)
+		}
+		rest := current.Start.Sub(previous.End)
+		if rest < 0 {
+			// Trùng ca quan trọng hơn các vi phạm khác cùng ngày
+			errors[key] = config.GetMessageCode("SHIFT_CONFLICT")
+		} else if rule.MinRestMinutes > 0 && rest < time.Duration(rule.MinRestMinutes)*time.Minute {
+			setError(errors, key, "REST_TOO_SHORT")
+		}
+	}
+
+	return errors
+}
+
+// WeekCheck tổng số phút làm trong tuần (thứ hai → chủ nhật theo ngày của ca)
+func WeekCheck(employeeID uint, instances []rosterModel.ShiftInstance, rule model.LaborRule, focus func(rosterModel.ShiftInstance) bool, errors map[string]string) map[string]string {
+	if rule.MaxWeekMinutes <= 0 {
+		return errors
+	}
+
+	minutes, focused := map[string]int{}, map[string]bool{}
+	for _, instance := range instances {
+		date, err := time.Parse("2006-01-02", instance.Date)
+		if err != nil {
+			continue
+		}
+		week := date.AddDate(0, 0, -(int(date.Weekday())+6)%7).Format("2006-01-02")
+		minutes[week] += instance.WorkMinutes
+		focused[week] = focused[week] || focus(instance)
+	}
+	for week, total := range minutes {
+		if focused[week] && total > rule.MaxWeekMinutes {
+			setError(errors, fmt.Sprintf("Employee[%d].Week[%s]", employeeID, week), "WEEK_HOURS_EXCEEDED")
+		}
+	}
+
+	return errors
+}
+
+// ConsecutiveCheck số ngày làm liên tiếp, báo ở ngày đầu tiên vượt ngưỡng của chuỗi
+func ConsecutiveCheck(employeeID uint, instances []rosterModel.ShiftInstance, rule model.LaborRule, focus func(rosterModel.ShiftInstance) bool, errors map[string]string) map[string]string {
+	if rule.MaxConsecutiveDays <= 0 {
+		return errors
+	}
+
+	focused := map[string]bool{}
+	dates := []string{}
+	for _, instance := range instances {
+		if _, ok := focused[instance.Date]; !ok {
+			dates = append(dates, instance.Date)
+		}
+		focused[instance.Date] = focused[instance.Date] || focus(instance)
+	}
+	sort.Strings(dates)
+
+	for start := 0; start < len(dates); {
+		end, hasFocus := start, focused[dates[start]]
+		for end+1 < len(dates) && nextDay(dates[end]) == dates[end+1] {
+			end++
+			hasFocus = hasFocus || focused[dates[end]]
+		}
+		if hasFocus && end-start+1 > rule.MaxConsecutiveDays {
+			setError(errors, fmt.Sprintf("Employee[%d].Date[%s]", employeeID, dates[start+rule.MaxConsecutiveDays]), "CONSECUTIVE_DAYS_EXCEEDED")
+		}
+		start = end + 1
+	}
+
+	return errors
+}
+
+func dateKey(employeeID uint, instance rosterModel.ShiftInstance) string {
+	return fmt.Sprintf("Employee[%d].Date[%s]", employeeID, instance.Date)
+}
+
+// Giữ vi phạm đầu tiên của mỗi key
+func setError(errors map[string]string, key, message string) {
+	if _, ok := errors[key]; !ok {
+		errors[key] = config.GetMessageCode(message)
+	}
+}
+
+func nextDay(value string) string {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return ""
+	}
+
+	return date.AddDate(0, 0, 1).Format("2006-01-02")
+}
+
+// Giá trị < 0 hoặc không hợp lệ thì dùng mặc định, 0 là không kiểm tra
+func envInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(config.Config(key))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+
+	return value
+}
@@ -0,0 +1,159 @@
+package labor
+
+import (
+	"app/config"
+	"app/modules/labor/model"
+	rosterModel "app/modules/roster/model"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Ca của roster rosterID bắt đầu lúc start (YYYY-MM-DD HH:MM), dài hours giờ, tính hết là giờ làm
+func instance(rosterID uint, start string, hours int) rosterModel.ShiftInstance {
+	begin, err := time.Parse("2006-01-02 15:04", start)
+	if err != nil {
+		panic(err)
+	}
+
+	return rosterModel.ShiftInstance{
+		RosterID:    rosterID,
+		Date:        begin.Format("2006-01-02"),
+		EmployeeID:  1,
+		Start:       begin,
+		End:         begin.Add(time.Duration(hours) * time.Hour),
+		WorkMinutes: hours * 60,
+	}
+}
+
+// Mỗi ngày một ca 8 tiếng của roster rosterID từ ngày from, days ngày
+func daily(rosterID uint, from string, days int) []rosterModel.ShiftInstance {
+	date, _ := time.Parse("2006-01-02", from)
+	instances := []rosterModel.ShiftInstance{}
+	for i := 0; i < days; i++ {
+		instances = append(instances, instance(rosterID, date.AddDate(0, 0, i).Format("2006-01-02")+" 08:00", 8))
+	}
+
+	return instances
+}
+
+func concat(lists ...[]rosterModel.ShiftInstance) []rosterModel.ShiftInstance {
+	instances := []rosterModel.ShiftInstance{}
+	for _, list := range lists {
+		instances = append(instances, list...)
+	}
+
+	return instances
+}
+
+func codes(errors map[string]string) map[string]string {
+	for key, message := range errors {
+		errors[key] = config.GetMessageCode(message)
+	}
+
+	return errors
+}
+
+func TestRestCheck(t *testing.T) {
+	rule := model.LaborRule{MinRestMinutes: 11 * 60}
+
+	cases := []struct {
+		name      string
+		rule      model.LaborRule
+		instances []rosterModel.ShiftInstance
+		rosterID  uint
+		want      map[string]string
+	}{
+		{"overlap", rule, []rosterModel.ShiftInstance{instance(1, "2024-01-01 08:00", 9), instance(2, "2024-01-01 16:00", 4)}, 2,
+			map[string]string{"Employee[1].Date[2024-01-01]": "SHIFT_CONFLICT"}},
+		{"night then morning", rule, []rosterModel.ShiftInstance{instance(1, "2024-01-01 22:00", 8), instance(2, "2024-01-02 13:00", 8)}, 2,
+			map[string]string{"Employee[1].Date[2024-01-02]": "REST_TOO_SHORT"}},
+		{"night then afternoon", rule, []rosterModel.ShiftInstance{instance(1, "2024-01-01 22:00", 8), instance(2, "2024-01-02 17:00", 8)}, 2,
+			map[string]string{}},
+		// Vi phạm báo ở ngày của ca thuộc roster đang kiểm tra
+		{"new shift before an existing one", rule, []rosterModel.ShiftInstance{instance(2, "2024-01-01 22:00", 8), instance(1, "2024-01-02 13:00", 8)}, 2,
+			map[string]string{"Employee[1].Date[2024-01-01]": "REST_TOO_SHORT"}},
+		// So với ca kết thúc muộn nhất, không chỉ ca ngay trước
+		{"inside a long shift", rule, []rosterModel.ShiftInstance{instance(1, "2024-01-01 08:00", 12), instance(1, "2024-01-01 09:00", 2), instance(2, "2024-01-01 19:00", 4)}, 2,
+			map[string]string{"Employee[1].Date[2024-01-01]": "SHIFT_CONFLICT"}},
+		{"not the checked roster", rule, []rosterModel.ShiftInstance{instance(1, "2024-01-01 22:00", 8), instance(1, "2024-01-02 13:00", 8)}, 2,
+			map[string]string{}},
+		{"rest not checked", model.LaborRule{}, []rosterModel.ShiftInstance{instance(1, "2024-01-01 22:00", 8), instance(2, "2024-01-02 07:00", 8)}, 2,
+			map[string]string{}},
+	}
+
+	for _, item := range cases {
+		got := RestCheck(1, item.instances, item.rule, ByRoster(item.rosterID), map[string]string{})
+		if want := codes(item.want); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", item.name, got, want)
+		}
+	}
+}
+
+func TestWeekCheck(t *testing.T) {
+	rule := model.LaborRule{MaxWeekMinutes: 48 * 60}
+
+	cases := []struct {
+		name      string
+		instances []rosterModel.ShiftInstance
+		rosterID  uint
+		want      map[string]string
+	}{
+		{"at the limit", daily(1, "2024-01-01", 6), 1, map[string]string{}},
+		{"over the limit", concat(daily(1, "2024-01-01", 6), daily(2, "2024-01-07", 1)), 2,
+			map[string]string{"Employee[1].Week[2024-01-01]": "WEEK_HOURS_EXCEEDED"}},
+		// Thứ năm → thứ tư: 4 ngày ở tuần đầu, 3 ngày ở tuần sau
+		{"split across weeks", daily(1, "2024-01-04", 7), 1, map[string]string{}},
+		{"overtime in another week", concat(daily(1, "2024-01-01", 7), daily(2, "2024-01-08", 1)), 2, map[string]string{}},
+	}
+
+	for _, item := range cases {
+		got := WeekCheck(1, item.instances, rule, ByRoster(item.rosterID), map[string]string{})
+		if want := codes(item.want); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", item.name, got, want)
+		}
+	}
+}
+
+func TestConsecutiveCheck(t *testing.T) {
+	rule := model.LaborRule{MaxConsecutiveDays: 6}
+
+	cases := []struct {
+		name      string
+		instances []rosterModel.ShiftInstance
+		rosterID  uint
+		want      map[string]string
+	}{
+		{"at the limit", daily(1, "2024-01-01", 6), 1, map[string]string{}},
+		{"one day over", daily(1, "2024-01-01", 7), 1, map[string]string{"Employee[1].Date[2024-01-07]": "CONSECUTIVE_DAYS_EXCEEDED"}},
+		{"day off between", concat(daily(1, "2024-01-01", 6), daily(1, "2024-01-08", 6)), 1, map[string]string{}},
+		{"two shifts on a day", concat(daily(1, "2024-01-01", 6), []rosterModel.ShiftInstance{instance(1, "2024-01-03 20:00", 2)}), 1, map[string]string{}},
+		// Ca của roster đang kiểm tra ở đầu chuỗi, báo ở ngày đầu tiên vượt ngưỡng
+		{"checked roster starts the run", concat(daily(2, "2024-01-01", 1), daily(1, "2024-01-02", 7)), 2,
+			map[string]string{"Employee[1].Date[2024-01-07]": "CONSECUTIVE_DAYS_EXCEEDED"}},
+		{"not the checked roster", daily(1, "2024-01-01", 8), 2, map[string]string{}},
+	}
+
+	for _, item := range cases {
+		got := ConsecutiveCheck(1, item.instances, rule, ByRoster(item.rosterID), map[string]string{})
+		if want := codes(item.want); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", item.name, got, want)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	team := uint(5)
+	shift := rosterModel.ShiftInstance{RosterID: 1, TeamID: &team, ShiftID: 3}
+	personal := rosterModel.ShiftInstance{RosterID: 2, ShiftID: 4}
+
+	if !ByRoster(1)(shift) || ByRoster(1)(personal) {
+		t.Error("ByRoster")
+	}
+	if !ByTeam(5)(shift) || ByTeam(5)(personal) || ByTeam(6)(shift) {
+		t.Error("ByTeam")
+	}
+	if !ByShift(4)(personal) || ByShift(4)(shift) {
+		t.Error("ByShift")
+	}
+}
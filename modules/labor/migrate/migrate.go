@@ -0,0 +1,14 @@
+package laborMigrate
+
+import (
+	"app/database"
+	model "app/modules/labor/model"
+)
+
+func MigrateTbl() bool {
+	db := database.DB
+
+	db.AutoMigrate(&model.LaborRule{})
+
+	return true
+}
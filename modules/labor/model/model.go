@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	departmentModel "app/modules/department/model"
+)
+
+// LaborRule ngưỡng luật lao động khi xếp ca cho nhân viên của một department, DepartmentID = nil là mặc định cho mọi department.
+// Giá trị 0 là không kiểm tra (riêng trùng ca luôn được kiểm tra)
+type LaborRule struct {
+	ID                 uint                        `gorm:"primarykey;column:labor_rule_id;<-:create" json:"labor_rule_id"`
+	DepartmentID       *uint                       `gorm:"column:department_id;uniqueIndex" json:"department_id"`
+	Department         *departmentModel.Department `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"department,omitempty"`
+	MinRestMinutes     int                         `gorm:"column:min_rest_minutes;not null;default:0" json:"min_rest_minutes"`         // nghỉ tối thiểu giữa hai ca
+	MaxWeekMinutes     int                         `gorm:"column:max_week_minutes;not null;default:0" json:"max_week_minutes"`         // số phút làm tối đa trong một tuần (thứ hai → chủ nhật)
+	MaxConsecutiveDays int                         `gorm:"column:max_consecutive_days;not null;default:0" json:"max_consecutive_days"` // số ngày làm liên tiếp tối đa
+	CreatedAt          time.Time                   `json:"created_at"`
+	UpdatedAt          time.Time                   `json:"updated_at"`
+	CreatedBy          string                      `gorm:"column:created_by;size:15" json:"created_by"`
+	UpdatedBy          string                      `gorm:"column:updated_by;size:15" json:"updated_by"`
+}
+
+// Localize điền Name của department đã preload
+func (r *LaborRule) Localize(lang string, all bool) {
+	if r.Department != nil {
+		r.Department.Localize(lang, all)
+	}
+}
+
+type SaveLaborRuleModel struct {
+	DepartmentID       *uint `json:"department_id"`
+	MinRestMinutes     int   `json:"min_rest_minutes"`
+	MaxWeekMinutes     int   `json:"max_week_minutes"`
+	MaxConsecutiveDays int   `json:"max_consecutive_days"`
+}
+
+// Tên bảng trong CSDL
+func (LaborRule) TableName() string {
+	return "tbl_labor_rule"
+}
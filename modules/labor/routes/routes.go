@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"app/middleware"
+
+	"app/modules/labor/controller"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func InitLaborRoutes(app *fiber.App) {
+	labor := app.Group("/labor", middleware.AppInfo, middleware.AppAuthen)
+
+	labor.Get("/", middleware.Require("labor:read"), controller.GetLaborRule)
+	labor.Put("/", middleware.Require("labor:write"), controller.SaveLaborRule)
+	labor.Delete("/:id", middleware.Require("labor:write"), controller.DeleteLaborRule)
+}
@@ -0,0 +1,14 @@
+package mailMigrate
+
+import (
+	"app/database"
+	model "app/modules/mail/model"
+)
+
+func MigrateTbl() bool {
+	db := database.DB
+
+	db.AutoMigrate(&model.MailOutbox{})
+
+	return true
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+)
+
+// MailOutbox thư chờ gửi, worker gửi lại theo NextAttemptAt khi SMTP lỗi
+type MailOutbox struct {
+	ID            uint `gorm:"primarykey;column:mail_id;<-:create"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	ToAddress     string     `gorm:"column:to_address;size:255;not null"`
+	Subject       string     `gorm:"column:subject;size:255;not null"`
+	Body          string     `gorm:"column:body;type:text;not null"`
+	Status        string     `gorm:"column:status;size:10;not null;default:pending;index"`
+	Attempts      int        `gorm:"column:attempts;default:0"`
+	LastError     string     `gorm:"column:last_error;type:text"`
+	NextAttemptAt time.Time  `gorm:"column:next_attempt_at;index"`
+	SentAt        *time.Time `gorm:"column:sent_at"`
+}
+
+// Tên bảng trong CSDL
+func (MailOutbox) TableName() string {
+	return "tbl_mail_outbox"
+}
@@ -0,0 +1,71 @@
+package mail
+
+import (
+	"app/config"
+	"app/core"
+	"app/database"
+	"app/modules/mail/model"
+	"strconv"
+	"time"
+)
+
+// Queue dựng thư từ template và đưa vào outbox, worker sẽ gửi sau
+func Queue(to, name, lang string, data interface{}) error {
+	subject, body, err := Render(name, lang, data)
+	if err != nil {
+		return err
+	}
+
+	return database.DB.Create(&model.MailOutbox{
+		ToAddress:     to,
+		Subject:       subject,
+		Body:          body,
+		Status:        "pending",
+		NextAttemptAt: time.Now(),
+	}).Error
+}
+
+// ProcessOutbox gửi các thư đến hạn. Lỗi thì thử lại sau 2^attempts phút,
+// quá MAIL_MAX_ATTEMPTS lần thì chuyển trạng thái failed.
+func ProcessOutbox() {
+	db := database.DB
+
+	maxAttempts, err := strconv.Atoi(config.Config("MAIL_MAX_ATTEMPTS"))
+	if err != nil || maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+
+	var mails []model.MailOutbox
+	db.Where("status = ? AND next_attempt_at <= ?", "pending", time.Now()).Order("mail_id").Limit(50).Find(&mails)
+
+	for _, item := range mails {
+		err := GetSender().Send(Message{To: item.ToAddress, Subject: item.Subject, Body: item.Body})
+
+		item.Attempts++
+		if err == nil {
+			now := time.Now()
+			item.Status = "sent"
+			item.SentAt = &now
+			item.LastError = ""
+		} else {
+			core.WriteLog("ERROR | SEND MAIL " + strconv.Itoa(int(item.ID)) + " | " + err.Error())
+			item.LastError = err.Error()
+			item.NextAttemptAt = time.Now().Add(time.Minute * time.Duration(1<<uint(item.Attempts)))
+			if item.Attempts >= maxAttempts {
+				item.Status = "failed"
+			}
+		}
+
+		db.Save(&item)
+	}
+}
+
+// StartWorker chạy ProcessOutbox định kỳ, gọi bằng goroutine từ main
+func StartWorker(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ProcessOutbox()
+	}
+}
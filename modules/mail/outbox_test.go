@@ -0,0 +1,170 @@
+package mail
+
+import (
+	"app/database/testdb"
+	"app/modules/mail/model"
+	"bufio"
+	"errors"
+	"mime"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Sender giả lập: lưu thư đã gửi, trả lỗi err nếu có
+type fakeSender struct {
+	sent []Message
+	err  error
+}
+
+func (s *fakeSender) Send(msg Message) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, msg)
+	return nil
+}
+
+func useSender(t *testing.T, sender Sender) {
+	previous := current
+	SetSender(sender)
+	t.Cleanup(func() { SetSender(previous) })
+}
+
+func TestProcessOutbox(t *testing.T) {
+	t.Setenv("MAIL_MAX_ATTEMPTS", "2")
+	db := testdb.Open(t, &model.MailOutbox{})
+
+	if err := Queue("a@example.com", "password_reset", "jp", map[string]interface{}{"Username": "nv0001", "Minutes": 30, "Link": "https://app/reset"}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("smtp error is retried then failed", func(t *testing.T) {
+		useSender(t, &fakeSender{err: errors.New("421 try again later")})
+
+		ProcessOutbox()
+		var item model.MailOutbox
+		db.First(&item)
+		if item.Status != "pending" || item.Attempts != 1 || item.LastError == "" || !item.NextAttemptAt.After(time.Now()) {
+			t.Fatalf("after first failure: %+v", item)
+		}
+
+		// Chưa tới hạn thì không gửi lại
+		ProcessOutbox()
+		db.First(&item)
+		if item.Attempts != 1 {
+			t.Fatalf("sent before next_attempt_at: %d attempts", item.Attempts)
+		}
+
+		db.Model(&item).Update("next_attempt_at", time.Now().Add(-time.Second))
+		ProcessOutbox()
+		db.First(&item)
+		if item.Status != "failed" || item.Attempts != 2 {
+			t.Fatalf("after MAIL_MAX_ATTEMPTS: %+v", item)
+		}
+	})
+
+	t.Run("pending mail is sent", func(t *testing.T) {
+		db.Model(&model.MailOutbox{}).Where("1 = 1").Updates(map[string]interface{}{"status": "pending", "attempts": 0, "next_attempt_at": time.Now()})
+		sender := &fakeSender{}
+		useSender(t, sender)
+
+		ProcessOutbox()
+		ProcessOutbox()
+		if len(sender.sent) != 1 {
+			t.Fatalf("%d mails sent, want 1", len(sender.sent))
+		}
+		if msg := sender.sent[0]; msg.To != "a@example.com" || msg.Subject != "パスワード再設定のご案内" || !strings.Contains(msg.Body, "https://app/reset") {
+			t.Fatalf("unexpected mail %+v", msg)
+		}
+
+		var item model.MailOutbox
+		db.First(&item)
+		if item.Status != "sent" || item.SentAt == nil || item.LastError != "" {
+			t.Fatalf("after sending: %+v", item)
+		}
+	})
+}
+
+// SMTP server giả lập tối thiểu (không STARTTLS, không AUTH), trả về header và nội dung thư nhận được
+func listenSmtp(t *testing.T) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		text := textproto.NewConn(conn)
+		text.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			switch command := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); command {
+			case "EHLO", "HELO":
+				text.PrintfLine("250 localhost")
+			case "DATA":
+				text.PrintfLine("354 end with .")
+				data, err := text.ReadDotLines()
+				if err != nil {
+					return
+				}
+				received <- strings.Join(data, "\n")
+				text.PrintfLine("250 OK")
+			case "QUIT":
+				text.PrintfLine("221 bye")
+				return
+			default:
+				text.PrintfLine("250 OK")
+			}
+		}
+	}()
+
+	return listener.Addr().String(), received
+}
+
+func TestSmtpSender(t *testing.T) {
+	address, received := listenSmtp(t)
+	host, port, _ := net.SplitHostPort(address)
+
+	sender := NewSmtpSender(host, port, "", "", "no-reply@example.com")
+	if err := sender.Send(Message{To: "a@example.com", Subject: "Đặt lại mật khẩu", Body: "Xin chào"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var data string
+	select {
+	case data = <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no mail received")
+	}
+
+	header, err := textproto.NewReader(bufio.NewReader(strings.NewReader(data + "\n"))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Header chỉ có ASCII, giải mã lại được tiêu đề gốc
+	subject := header.Get("Subject")
+	for _, r := range subject {
+		if r > 127 {
+			t.Fatalf("subject header is not ASCII: %q", subject)
+		}
+	}
+	if decoded, err := new(mime.WordDecoder).DecodeHeader(subject); err != nil || decoded != "Đặt lại mật khẩu" {
+		t.Fatalf("subject %q decodes to %q, %v", subject, decoded, err)
+	}
+	if !strings.HasSuffix(data, "Xin chào") {
+		t.Fatalf("unexpected body in %q", data)
+	}
+}
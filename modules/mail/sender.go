@@ -0,0 +1,72 @@
+package mail
+
+import (
+	"app/config"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender gửi một thư. Tách thành interface để test có thể dùng SMTP server giả lập.
+type Sender interface {
+	Send(msg Message) error
+}
+
+type smtpSender struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+var current Sender
+
+func NewSmtpSender(host, port, username, password, from string) Sender {
+	return &smtpSender{host: host, port: port, username: username, password: password, from: from}
+}
+
+// GetSender trả về sender đang dùng, mặc định đọc MAIL_HOST / MAIL_PORT / MAIL_USERNAME / MAIL_PASSWORD
+func GetSender() Sender {
+	if current == nil {
+		current = NewSmtpSender(
+			config.Config("MAIL_HOST"),
+			config.Config("MAIL_PORT"),
+			config.Config("MAIL_USERNAME"),
+			config.Config("MAIL_PASSWORD"),
+			config.Config("MAIL_FROM"),
+		)
+	}
+
+	return current
+}
+
+// SetSender thay sender mặc định (dùng cho test)
+func SetSender(sender Sender) {
+	current = sender
+}
+
+func (s *smtpSender) Send(msg Message) error {
+	var auth smtp.Auth
+	if len(s.username) > 0 {
+		auth = smtp.PlainAuth("", s.username, s.password, s.host)
+	}
+
+	header := []string{
+		"From: " + s.from,
+		"To: " + msg.To,
+		"Subject: " + mime.QEncoding.Encode("UTF-8", msg.Subject), // tiêu đề vn / jp không phải ASCII
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	}
+	body := strings.Join(header, "\r\n") + "\r\n\r\n" + msg.Body
+
+	return smtp.SendMail(fmt.Sprintf("%s:%s", s.host, s.port), auth, s.from, []string{msg.To}, []byte(body))
+}
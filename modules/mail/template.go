@@ -0,0 +1,83 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+type mailTemplate struct {
+	Subject string
+	Body    string
+}
+
+// Mẫu thư theo ngôn ngữ vn / en / jp, trùng hậu tố các cột *_vn, *_en, *_jp
+var templateList = map[string]map[string]mailTemplate{
+	"password_reset": {
+		"vn": {
+			Subject: "Đặt lại mật khẩu",
+			Body:    "Xin chào {{.Username}},\n\nVui lòng mở liên kết sau để đặt lại mật khẩu (hiệu lực {{.Minutes}} phút):\n{{.Link}}\n\nNếu bạn không yêu cầu, hãy bỏ qua thư này.",
+		},
+		"en": {
+			Subject: "Reset your password",
+			Body:    "Hello {{.Username}},\n\nOpen the link below to set a new password (valid for {{.Minutes}} minutes):\n{{.Link}}\n\nIf you did not request this, please ignore this email.",
+		},
+		"jp": {
+			Subject: "パスワード再設定のご案内",
+			Body:    "{{.Username}} 様\n\n以下のリンクからパスワードを再設定してください（有効期限 {{.Minutes}} 分）。\n{{.Link}}\n\nお心当たりのない場合は、このメールを破棄してください。",
+		},
+	},
+	"email_verify": {
+		"vn": {
+			Subject: "Xác thực địa chỉ email",
+			Body:    "Xin chào {{.Username}},\n\nVui lòng mở liên kết sau để xác thực email (hiệu lực {{.Minutes}} phút):\n{{.Link}}",
+		},
+		"en": {
+			Subject: "Verify your email address",
+			Body:    "Hello {{.Username}},\n\nOpen the link below to verify your email address (valid for {{.Minutes}} minutes):\n{{.Link}}",
+		},
+		"jp": {
+			Subject: "メールアドレス確認のお願い",
+			Body:    "{{.Username}} 様\n\n以下のリンクからメールアドレスを確認してください（有効期限 {{.Minutes}} 分）。\n{{.Link}}",
+		},
+	},
+}
+
+// Render dựng tiêu đề và nội dung thư, ngôn ngữ không có thì dùng "en"
+func Render(name, lang string, data interface{}) (string, string, error) {
+	langs, ok := templateList[name]
+	if !ok {
+		return "", "", fmt.Errorf("mail template %s not found", name)
+	}
+
+	tpl, ok := langs[lang]
+	if !ok {
+		tpl = langs["en"]
+	}
+
+	subject, err := execute(tpl.Subject, data)
+	if err != nil {
+		return "", "", err
+	}
+
+	body, err := execute(tpl.Body, data)
+	if err != nil {
+		return "", "", err
+	}
+
+	return subject, body, nil
+}
+
+func execute(text string, data interface{}) (string, error) {
+	tpl, err := template.New("mail").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
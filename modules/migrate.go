@@ -2,15 +2,38 @@ package modules
 
 import (
 	"app/modules/authen/migrate"
+	"app/modules/client/migrate"
+	"app/modules/mail/migrate"
 	"app/modules/department/migrate"
+	"app/modules/employee/migrate"
+	"app/modules/org/migrate"
+	"app/modules/history/migrate"
 	"app/modules/group/migrate"
 	"app/modules/team/migrate"
+	"app/modules/shift/migrate"
+	"app/modules/roster/migrate"
+	"app/modules/labor/migrate"
+	"app/modules/site/migrate"
+	"app/modules/attendance/migrate"
+	"app/modules/translation/migrate"
 )
 
 func MigrateModule() bool {
 	migrate.MigrateAuthen()
+	// Trước các bảng có tên dịch được (hook AfterSave ghi vào tbl_translation)
+	translationMigrate.MigrateTbl()
+	clientMigrate.MigrateTbl()
+	mailMigrate.MigrateTbl()
 	departmentMigrate.MigrateTbl()
+	historyMigrate.MigrateTbl()
 	groupMigrate.MigrateTbl()
 	teamMigrate.MigrateTbl()
+	employeeMigrate.MigrateTbl()
+	orgMigrate.MigrateTbl()
+	shiftMigrate.MigrateTbl()
+	rosterMigrate.MigrateTbl()
+	laborMigrate.MigrateTbl()
+	siteMigrate.MigrateTbl()
+	attendanceMigrate.MigrateTbl()
 	return true
 }
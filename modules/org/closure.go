@@ -0,0 +1,44 @@
+package org
+
+import (
+	"app/modules/org/model"
+
+	"gorm.io/gorm"
+)
+
+// InsertPath thêm các dòng closure cho đơn vị mới: chính nó (depth 0) và mọi tổ tiên của parent
+func InsertPath(tx *gorm.DB, unitID uint, parentID *uint) error {
+	if err := tx.Create(&model.OrgUnitPath{AncestorID: unitID, DescendantID: unitID}).Error; err != nil {
+		return err
+	}
+
+	if parentID == nil {
+		return nil
+	}
+
+	return tx.Exec(`INSERT INTO tbl_org_unit_path (ancestor_id, descendant_id, depth)
+		SELECT ancestor_id, ?, depth + 1 FROM tbl_org_unit_path WHERE descendant_id = ?`, unitID, *parentID).Error
+}
+
+// MovePath chuyển cả cây con của unitID sang parent mới (nil: lên gốc)
+func MovePath(tx *gorm.DB, unitID uint, parentID *uint) error {
+	// Cắt liên kết giữa cây con và các tổ tiên cũ
+	err := tx.Exec(`DELETE FROM tbl_org_unit_path
+		WHERE descendant_id IN (SELECT descendant_id FROM tbl_org_unit_path WHERE ancestor_id = ?)
+		AND ancestor_id NOT IN (SELECT descendant_id FROM tbl_org_unit_path WHERE ancestor_id = ?)`, unitID, unitID).Error
+	if err != nil || parentID == nil {
+		return err
+	}
+
+	return tx.Exec(`INSERT INTO tbl_org_unit_path (ancestor_id, descendant_id, depth)
+		SELECT p.ancestor_id, s.descendant_id, p.depth + s.depth + 1
+		FROM tbl_org_unit_path p CROSS JOIN tbl_org_unit_path s
+		WHERE p.descendant_id = ? AND s.ancestor_id = ?`, *parentID, unitID).Error
+}
+
+// IsDescendant true nếu id nằm trong cây con của ancestorID (kể cả chính nó)
+func IsDescendant(tx *gorm.DB, ancestorID, id uint) bool {
+	var count int64
+	tx.Model(&model.OrgUnitPath{}).Where("ancestor_id = ? AND descendant_id = ?", ancestorID, id).Count(&count)
+	return count > 0
+}
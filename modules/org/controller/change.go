@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"app/config"
+	"app/core"
+	"app/database"
+	"app/modules/org"
+	"app/modules/org/model"
+	"app/utils"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// GetChangeSet Lấy danh sách change set
+// @Summary Get scheduled org changes
+// @Description Returns change sets with their changes, optionally filtered by status (pending, applied, cancelled, failed)
+// @Tags Org
+// @Accept json
+// @Produce json
+// @Param status query string false "Status"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /org/change [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetChangeSet(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	query := database.DB.Preload("Changes", func(db *gorm.DB) *gorm.DB { return db.Order("seq") }).Order("effective_date, change_set_id")
+	if status := c.Query("status"); len(status) > 0 {
+		query = query.Where("status = ?", status)
+	}
+
+	var sets []model.OrgChangeSet
+	if err := query.Find(&sets).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	response.Data = sets
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// CreateChangeSet Lên lịch thay đổi tổ chức
+// @Summary Schedule org changes
+// @Description Stores a pending change set (create, rename, move, dissolve of departments, groups and teams).
+// @Description The scheduler applies all changes in order in one transaction on effective_date
+// @Tags Org
+// @Accept json
+// @Produce json
+// @Param body body model.CreateOrgChangeSetModel true "Change set"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /org/change [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func CreateChangeSet(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	payload := new(model.CreateOrgChangeSetModel)
+	if err := c.BodyParser(payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	vItem := map[string]string{"Title": payload.Title, "EffectiveDate": payload.EffectiveDate}
+	errors := utils.RequireCheck([]string{"Title", "EffectiveDate"}, vItem, map[string]string{})
+	errors = utils.MaxLengthCheck([]string{"Title:200"}, vItem, errors)
+	errors = utils.DateFormatCheck([]string{"EffectiveDate"}, vItem, errors)
+
+	effectiveDate, _ := time.Parse("2006-01-02", payload.EffectiveDate)
+	if _, ok := errors["EffectiveDate"]; !ok && effectiveDate.Format("2006-01-02") < time.Now().Format("2006-01-02") {
+		errors["EffectiveDate"] = config.GetMessageCode("VALUE_INVALID")
+	}
+	if len(payload.Changes) == 0 {
+		errors["Changes"] = config.GetMessageCode("REQUIRE")
+	}
+	for i, item := range payload.Changes {
+		checkChange(i, item, errors)
+	}
+
+	if len(errors) > 0 {
+		response.Status = false
+		response.Message = config.GetMessageCode("MISSING_FIELDS")
+		response.ValidateError = errors
+		return c.JSON(response)
+	}
+
+	set := model.OrgChangeSet{
+		Title:         payload.Title,
+		EffectiveDate: effectiveDate,
+		Status:        model.ChangeStatusPending,
+		CreatedBy:     getUsername(c),
+	}
+	for i, item := range payload.Changes {
+		set.Changes = append(set.Changes, model.OrgChange{
+			Seq:      i + 1,
+			Action:   item.Action,
+			UnitType: item.UnitType,
+			UnitID:   item.UnitID,
+			ParentID: item.ParentID,
+			NameVN:   item.NameVN,
+			NameEN:   item.NameEN,
+			NameJP:   item.NameJP,
+			Shortcut: item.Shortcut,
+		})
+	}
+
+	// Thử áp dụng trong bộ nhớ (sau các change set pending trước đó) để báo lỗi ngay
+	// (đơn vị không tồn tại, còn đơn vị con...) thay vì đợi đến ngày hiệu lực
+	tree, err := buildTree(database.DB, true, config.DefaultLanguage(), nil)
+	if err == nil {
+		var sets []model.OrgChangeSet
+		if sets, err = org.PendingChangeSets(database.DB, effectiveDate); err == nil {
+			check := &preview{tree: tree}
+			check.applySets(sets)
+			err = check.applySet(&set)
+		}
+	}
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("VALUE_INVALID")
+		response.ValidateError = map[string]string{"Changes": err.Error()}
+		return c.JSON(response)
+	}
+
+	if err := database.DB.Create(&set).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("CREATE_FAIL")
+		return c.JSON(response)
+	}
+
+	core.WriteLog(fmt.Sprintf("ORG CHANGE | SCHEDULED | %d | %s | %s by %s", set.ID, set.Title, payload.EffectiveDate, set.CreatedBy))
+
+	response.Data = set
+	response.Status = true
+	response.Message = config.GetMessageCode("CREATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// CancelChangeSet Huỷ change set chưa áp dụng
+// @Summary Cancel scheduled org changes
+// @Description Cancels a pending change set. Applied change sets cannot be cancelled
+// @Tags Org
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the change set"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /org/change/{id} [delete]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func CancelChangeSet(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	results := database.DB.Model(&model.OrgChangeSet{}).
+		Where("change_set_id = ? AND status = ?", c.Params("id"), model.ChangeStatusPending).
+		Updates(map[string]interface{}{"status": model.ChangeStatusCancelled, "cancelled_by": getUsername(c)})
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+	if results.RowsAffected == 0 {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("DELETE_SUCCESS")
+	return c.JSON(response)
+}
+
+func checkChange(i int, item *model.OrgChangeModel, errors map[string]string) {
+	key := fmt.Sprintf("Changes[%d].", i)
+
+	switch item.Action {
+	case model.ActionCreate, model.ActionRename, model.ActionMove, model.ActionDissolve:
+	default:
+		errors[key+"Action"] = config.GetMessageCode("VALUE_INVALID")
+	}
+	if !org.IsUnitType(item.UnitType) {
+		errors[key+"UnitType"] = config.GetMessageCode("VALUE_INVALID")
+	}
+	if item.Action != model.ActionCreate && item.UnitID == nil {
+		errors[key+"UnitID"] = config.GetMessageCode("REQUIRE")
+	}
+	if item.Action == model.ActionCreate || item.Action == model.ActionRename {
+		vItem := map[string]string{"NameVN": item.NameVN, "NameEN": item.NameEN, "NameJP": item.NameJP, "Shortcut": item.Shortcut}
+		itemErrors := utils.RequireCheck([]string{"NameVN", "NameEN", "NameJP"}, vItem, map[string]string{})
+		itemErrors = utils.MaxLengthCheck([]string{"NameVN:100", "NameEN:100", "NameJP:100", "Shortcut:5"}, vItem, itemErrors)
+		for field, message := range itemErrors {
+			errors[key+field] = message
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"app/config"
+	"app/modules/org/model"
+	"fmt"
+)
+
+// Cấp cha / cấp con trực tiếp của từng loại node (team chỉ giải thể được khi không còn nhân viên)
+var (
+	nodeParent = map[string]string{model.TypeGroup: model.TypeDepartment, model.TypeTeam: model.TypeGroup}
+	nodeChild  = map[string]string{model.TypeDepartment: model.TypeGroup, model.TypeGroup: model.TypeTeam, model.TypeTeam: model.TypeEmployee}
+)
+
+// Xem trước as_of: áp dụng change set pending lên cây đã đọc (kể cả node đã xoá), chỉ trong bộ nhớ (không ghi CSDL).
+// undo lưu thao tác ngược để bỏ cả change set khi một thay đổi lỗi, giống scheduler đánh dấu failed
+type preview struct {
+	tree *orgTree
+	lang string
+	undo []func()
+}
+
+// applySets áp dụng lần lượt các change set, bỏ qua change set lỗi
+func (p *preview) applySets(sets []model.OrgChangeSet) {
+	for i := range sets {
+		p.applySet(&sets[i])
+	}
+}
+
+// applySet áp dụng một change set, lỗi thì hoàn tác cả change set.
+// Node bị thay đổi / tạo mới của change set thành công được đánh dấu Pending
+func (p *preview) applySet(set *model.OrgChangeSet) error {
+	p.undo = nil
+	var changed []*model.OrgNode
+	for i := range set.Changes {
+		change := &set.Changes[i]
+		node, err := p.apply(change)
+		if err != nil {
+			for j := len(p.undo) - 1; j >= 0; j-- {
+				p.undo[j]()
+			}
+			return fmt.Errorf("change %d (%s %s): %w", change.Seq, change.Action, change.UnitType, err)
+		}
+		changed = append(changed, node)
+	}
+
+	for _, node := range changed {
+		node.Pending = true
+	}
+
+	return nil
+}
+
+// Đơn vị mới chưa có id nên không tra được bằng find (change sau không trỏ tới được, giống khi tạo change set)
+func (p *preview) apply(change *model.OrgChange) (*model.OrgNode, error) {
+	if change.Action == model.ActionCreate {
+		var parent *model.OrgNode
+		if parentType, ok := nodeParent[change.UnitType]; ok {
+			if parent = p.active(parentType, change.ParentID); parent == nil {
+				return nil, fmt.Errorf("parent not found")
+			}
+		}
+		node := &model.OrgNode{
+			Type:     change.UnitType,
+			Name:     config.LocalName(p.lang, change.NameVN, change.NameEN, change.NameJP),
+			Shortcut: change.Shortcut,
+		}
+		p.attach(parent, node)
+		return node, nil
+	}
+
+	node := p.active(change.UnitType, change.UnitID)
+	if node == nil {
+		return nil, fmt.Errorf("unit not found")
+	}
+
+	switch change.Action {
+	case model.ActionRename:
+		name, shortcut := node.Name, node.Shortcut
+		node.Name = config.LocalName(p.lang, change.NameVN, change.NameEN, change.NameJP)
+		node.Shortcut = change.Shortcut
+		p.undo = append(p.undo, func() { node.Name, node.Shortcut = name, shortcut })
+
+	case model.ActionMove:
+		parentType, ok := nodeParent[change.UnitType]
+		if !ok {
+			return nil, fmt.Errorf("%s has no parent", change.UnitType)
+		}
+		parent := p.active(parentType, change.ParentID)
+		if parent == nil {
+			return nil, fmt.Errorf("parent not found")
+		}
+		p.detach(node)
+		p.attach(parent, node)
+
+	case model.ActionDissolve:
+		for _, child := range node.Children {
+			if child.Type == nodeChild[node.Type] && !child.IsDeleted {
+				return nil, fmt.Errorf("unit still has active %s", child.Type)
+			}
+		}
+		node.IsDeleted = true
+		p.undo = append(p.undo, func() { node.IsDeleted = false })
+
+	default:
+		return nil, fmt.Errorf("unknown action %s", change.Action)
+	}
+
+	return node, nil
+}
+
+// Node chưa xoá theo loại và id
+func (p *preview) active(nodeType string, id *uint) *model.OrgNode {
+	if id == nil {
+		return nil
+	}
+	if node := p.tree.find(nodeType, *id); node != nil && !node.IsDeleted {
+		return node
+	}
+
+	return nil
+}
+
+func (p *preview) attach(parent, node *model.OrgNode) {
+	children := p.tree.children(parent)
+	old, oldParent := *children, p.tree.parents[node]
+	*children = append(append([]*model.OrgNode{}, old...), node)
+	p.tree.parents[node] = parent
+	p.undo = append(p.undo, func() {
+		*children = old
+		p.tree.parents[node] = oldParent
+	})
+}
+
+func (p *preview) detach(node *model.OrgNode) {
+	children := p.tree.children(p.tree.parents[node])
+	old := *children
+	rest := []*model.OrgNode{}
+	for _, child := range old {
+		if child != node {
+			rest = append(rest, child)
+		}
+	}
+	*children = rest
+	p.undo = append(p.undo, func() { *children = old })
+}
+
+// Bỏ các node đã xoá (cùng cây con của chúng) khi không lấy include_deleted, rồi tính lại member_count
+func prune(nodes []*model.OrgNode) []*model.OrgNode {
+	kept := []*model.OrgNode{}
+	for _, node := range nodes {
+		if node.IsDeleted {
+			continue
+		}
+		node.Children = prune(node.Children)
+		kept = append(kept, node)
+	}
+
+	return kept
+}
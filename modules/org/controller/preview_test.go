@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"app/modules/org/model"
+	"testing"
+)
+
+// Cây: department 1 → group 10 (team 100, team 101), group 11; department 2
+func sampleTree() *orgTree {
+	tree := &orgTree{
+		nodes:   map[string]map[uint]*model.OrgNode{model.TypeDepartment: {}, model.TypeGroup: {}, model.TypeTeam: {}},
+		parents: map[*model.OrgNode]*model.OrgNode{},
+	}
+	add := func(parent *model.OrgNode, nodeType string, id uint) *model.OrgNode {
+		node := &model.OrgNode{Type: nodeType, ID: id, Name: nodeType}
+		tree.nodes[nodeType][id] = node
+		tree.parents[node] = parent
+		children := tree.children(parent)
+		*children = append(*children, node)
+		return node
+	}
+
+	department := add(nil, model.TypeDepartment, 1)
+	add(nil, model.TypeDepartment, 2)
+	group := add(department, model.TypeGroup, 10)
+	add(department, model.TypeGroup, 11)
+	add(group, model.TypeTeam, 100).Children = []*model.OrgNode{{Type: model.TypeEmployee, ID: 1, MemberCount: 1}}
+	add(group, model.TypeTeam, 101)
+
+	return tree
+}
+
+func id(value uint) *uint {
+	return &value
+}
+
+func TestPreviewApplySet(t *testing.T) {
+	tree := sampleTree()
+	p := &preview{tree: tree, lang: "en"}
+
+	err := p.applySet(&model.OrgChangeSet{Changes: []model.OrgChange{
+		{Seq: 1, Action: model.ActionRename, UnitType: model.TypeTeam, UnitID: id(100), NameVN: "Đội A", NameEN: "Team A", NameJP: "A"},
+		{Seq: 2, Action: model.ActionMove, UnitType: model.TypeTeam, UnitID: id(100), ParentID: id(11)},
+		{Seq: 3, Action: model.ActionCreate, UnitType: model.TypeTeam, ParentID: id(11), NameVN: "Đội mới", NameEN: "New team", NameJP: "新"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	team := tree.find(model.TypeTeam, 100)
+	group := tree.find(model.TypeGroup, 11)
+	if team.Name != "Team A" || !team.Pending || tree.parents[team] != group || len(group.Children) != 2 || len(tree.find(model.TypeGroup, 10).Children) != 1 {
+		t.Fatalf("changes not applied: team %+v, group 11 has %d children", team, len(group.Children))
+	}
+	if created := group.Children[1]; created.ID != 0 || created.Name != "New team" || !created.Pending {
+		t.Fatalf("created node %+v", created)
+	}
+
+	countMember(tree.departments[0])
+	if group.MemberCount != 1 || tree.find(model.TypeGroup, 10).MemberCount != 0 {
+		t.Fatalf("member_count not moved with the team: %d", group.MemberCount)
+	}
+}
+
+func TestPreviewFailedSetIsUndone(t *testing.T) {
+	cases := []struct {
+		name    string
+		changes []model.OrgChange
+	}{
+		{"parent not found", []model.OrgChange{
+			{Seq: 1, Action: model.ActionRename, UnitType: model.TypeTeam, UnitID: id(101), NameEN: "Renamed"},
+			{Seq: 2, Action: model.ActionMove, UnitType: model.TypeTeam, UnitID: id(100), ParentID: id(11)},
+			{Seq: 3, Action: model.ActionMove, UnitType: model.TypeTeam, UnitID: id(101), ParentID: id(99)},
+		}},
+		{"dissolve with active children", []model.OrgChange{
+			{Seq: 1, Action: model.ActionDissolve, UnitType: model.TypeTeam, UnitID: id(101)},
+			{Seq: 2, Action: model.ActionDissolve, UnitType: model.TypeGroup, UnitID: id(10)},
+		}},
+		{"dissolve team with employees", []model.OrgChange{
+			{Seq: 1, Action: model.ActionRename, UnitType: model.TypeTeam, UnitID: id(101), NameEN: "Renamed"},
+			{Seq: 2, Action: model.ActionDissolve, UnitType: model.TypeTeam, UnitID: id(100)},
+		}},
+		{"unit already dissolved", []model.OrgChange{
+			{Seq: 1, Action: model.ActionDissolve, UnitType: model.TypeGroup, UnitID: id(11)},
+			{Seq: 2, Action: model.ActionRename, UnitType: model.TypeGroup, UnitID: id(11), NameEN: "Again"},
+		}},
+		{"department has no parent", []model.OrgChange{
+			{Seq: 1, Action: model.ActionMove, UnitType: model.TypeDepartment, UnitID: id(2), ParentID: id(1)},
+		}},
+	}
+
+	for _, item := range cases {
+		t.Run(item.name, func(t *testing.T) {
+			tree := sampleTree()
+			p := &preview{tree: tree, lang: "en"}
+
+			if err := p.applySet(&model.OrgChangeSet{Changes: item.changes}); err == nil {
+				t.Fatal("set applied, want error")
+			}
+
+			for _, nodeType := range []string{model.TypeGroup, model.TypeTeam} {
+				for _, node := range tree.nodes[nodeType] {
+					if node.Pending || node.IsDeleted || node.Name != nodeType {
+						t.Errorf("%s %d not restored: %+v", nodeType, node.ID, node)
+					}
+				}
+			}
+			if group := tree.find(model.TypeGroup, 10); len(group.Children) != 2 || tree.parents[tree.find(model.TypeTeam, 100)] != group {
+				t.Errorf("group 10 children not restored: %d", len(group.Children))
+			}
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	tree := sampleTree()
+	p := &preview{tree: tree}
+	p.applySets([]model.OrgChangeSet{
+		{Changes: []model.OrgChange{{Seq: 1, Action: model.ActionDissolve, UnitType: model.TypeTeam, UnitID: id(101)}}},
+		// Lỗi: team 100 vẫn còn nhân viên, group 10 vẫn còn team 100, bỏ qua cả change set
+		{Changes: []model.OrgChange{{Seq: 1, Action: model.ActionDissolve, UnitType: model.TypeTeam, UnitID: id(100)}}},
+		{Changes: []model.OrgChange{{Seq: 1, Action: model.ActionDissolve, UnitType: model.TypeGroup, UnitID: id(10)}}},
+	})
+
+	tree.departments = prune(tree.departments)
+	group := tree.find(model.TypeGroup, 10)
+	if len(group.Children) != 1 || group.Children[0].ID != 100 || group.IsDeleted {
+		t.Fatalf("group 10 after prune: %+v", group.Children)
+	}
+	if tree.visible(tree.find(model.TypeTeam, 101)) {
+		t.Fatal("dissolved team still visible")
+	}
+}
@@ -0,0 +1,308 @@
+package controller
+
+import (
+	"app/config"
+	"app/database"
+	departmentModel "app/modules/department/model"
+	employeeModel "app/modules/employee/model"
+	groupModel "app/modules/group/model"
+	"app/modules/org"
+	"app/modules/org/model"
+	teamModel "app/modules/team/model"
+	"app/utils"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// Số cấp bên dưới department
+const maxDepth = 3
+
+// GetTree Lấy cây tổ chức department → group → team → employee
+// @Summary Get the organization tree
+// @Description Returns the whole hierarchy, or the subtree below the node given by type and id.
+// @Description depth limits how many levels below the root are returned (default: all), member_count always covers the whole subtree.
+// @Description as_of previews the tree on a date: pending scheduled changes up to that date are applied in memory (nodes marked pending, nothing is written)
+// @Description and employees come from team memberships valid on that date. New units in the preview have id 0
+// @Tags Org
+// @Accept json
+// @Produce json
+// @Param type query string false "department | group | team"
+// @Param id query int false "ID of the root node, required with type"
+// @Param depth query int false "Levels below the root"
+// @Param include_deleted query bool false "Include soft-deleted nodes"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param as_of query string false "YYYY-MM-DD"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /org/tree [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetTree(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	rootType := c.Query("type")
+	rootID, _ := strconv.Atoi(c.Query("id"))
+	depth := maxDepth
+	if len(c.Query("depth")) > 0 {
+		depth, _ = strconv.Atoi(c.Query("depth"))
+	}
+
+	errors := map[string]string{}
+	switch rootType {
+	case "", model.TypeDepartment, model.TypeGroup, model.TypeTeam:
+	default:
+		errors["Type"] = config.GetMessageCode("VALUE_INVALID")
+	}
+	if len(rootType) > 0 && rootID <= 0 {
+		errors["ID"] = config.GetMessageCode("FORMAT_NUMBER")
+	}
+	if depth < 0 {
+		errors["Depth"] = config.GetMessageCode("FORMAT_NUMBER")
+	}
+	var asOf *time.Time
+	if len(c.Query("as_of")) > 0 {
+		date, err := time.Parse("2006-01-02", c.Query("as_of"))
+		if err != nil {
+			errors["AsOf"] = config.GetMessageCode("FORMAT_DATE")
+		}
+		asOf = &date
+	}
+
+	if len(errors) > 0 {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		response.ValidateError = errors
+		return c.JSON(response)
+	}
+
+	// as_of: đọc cả node đã xoá để áp dụng change set giống scheduler (đơn vị thuộc cấp trên đã xoá...)
+	includeDeleted := c.QueryBool("include_deleted")
+	tree, err := buildTree(database.DB, includeDeleted || asOf != nil, utils.Language(c), asOf)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+
+	if asOf != nil {
+		// Áp dụng change set pending lên cây trong bộ nhớ, không ghi CSDL
+		sets, err := org.PendingChangeSets(database.DB, *asOf)
+		if err != nil {
+			response.Status = false
+			response.Message = config.GetMessageCode("GET_DATA_FAIL")
+			return c.JSON(response)
+		}
+		(&preview{tree: tree, lang: utils.Language(c)}).applySets(sets)
+
+		if !includeDeleted {
+			tree.departments = prune(tree.departments)
+		}
+		for _, department := range tree.departments {
+			countMember(department)
+		}
+	}
+
+	roots := tree.departments
+	if len(rootType) > 0 {
+		root := tree.find(rootType, uint(rootID))
+		if root == nil || (!includeDeleted && !tree.visible(root)) {
+			response.Status = false
+			response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+			return c.JSON(response)
+		}
+		roots = []*model.OrgNode{root}
+	}
+
+	for _, root := range roots {
+		trim(root, depth)
+	}
+
+	response.Data = roots
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+type orgTree struct {
+	departments []*model.OrgNode
+	nodes       map[string]map[uint]*model.OrgNode
+	parents     map[*model.OrgNode]*model.OrgNode // department: nil
+}
+
+func (t *orgTree) find(nodeType string, id uint) *model.OrgNode {
+	return t.nodes[nodeType][id]
+}
+
+// visible false nếu node hoặc một cấp trên đã xoá (bị bỏ khỏi cây khi không lấy include_deleted)
+func (t *orgTree) visible(node *model.OrgNode) bool {
+	for ; node != nil; node = t.parents[node] {
+		if node.IsDeleted {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Danh sách con của parent, parent nil là danh sách department
+func (t *orgTree) children(parent *model.OrgNode) *[]*model.OrgNode {
+	if parent == nil {
+		return &t.departments
+	}
+
+	return &parent.Children
+}
+
+// Đọc toàn bộ department / group / team / employee rồi ghép cây theo Group.DepartmentID và Team.GroupID.
+// asOf khác nil: nhân viên lấy theo membership có hiệu lực ngày đó thay vì tbl_employee.team_id
+func buildTree(db *gorm.DB, includeDeleted bool, lang string, asOf *time.Time) (*orgTree, error) {
+	db = db.Session(&gorm.Session{})
+	if includeDeleted {
+		db = db.Unscoped()
+	}
+
+	var departments []departmentModel.Department
+	if err := db.Preload("Translations").Order("department_id").Find(&departments).Error; err != nil {
+		return nil, err
+	}
+	var groups []groupModel.Group
+	if err := db.Preload("Translations").Order("group_id").Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	var teams []teamModel.Team
+	if err := db.Preload("Translations").Order("team_id").Find(&teams).Error; err != nil {
+		return nil, err
+	}
+	var employees []employeeModel.Employee
+	if asOf == nil {
+		if err := db.Preload("Translations").Where("team_id IS NOT NULL").Order("employee_code").Find(&employees).Error; err != nil {
+			return nil, err
+		}
+	} else {
+		var memberships []employeeModel.TeamMembership
+		results := db.Preload("Employee.Translations").
+			Where("valid_from <= ? AND (valid_to IS NULL OR valid_to >= ?)", *asOf, *asOf).
+			Order("employee_id").
+			Find(&memberships)
+		if results.Error != nil {
+			return nil, results.Error
+		}
+		for _, membership := range memberships {
+			if membership.Employee == nil {
+				continue
+			}
+			employee := *membership.Employee
+			teamID := membership.TeamID
+			employee.TeamID = &teamID
+			employees = append(employees, employee)
+		}
+	}
+
+	tree := &orgTree{
+		nodes: map[string]map[uint]*model.OrgNode{
+			model.TypeDepartment: {},
+			model.TypeGroup:      {},
+			model.TypeTeam:       {},
+		},
+		parents: map[*model.OrgNode]*model.OrgNode{},
+	}
+
+	for _, department := range departments {
+		node := &model.OrgNode{
+			Type:      model.TypeDepartment,
+			ID:        department.ID,
+			Name:      department.LocalName(lang),
+			Shortcut:  department.DepartmentShortcut,
+			IsDeleted: department.DeletedAt.Valid,
+		}
+		tree.nodes[model.TypeDepartment][department.ID] = node
+		tree.departments = append(tree.departments, node)
+	}
+
+	for _, group := range groups {
+		parent := tree.nodes[model.TypeDepartment][uint(group.DepartmentID)]
+		if parent == nil {
+			continue
+		}
+		node := &model.OrgNode{
+			Type:      model.TypeGroup,
+			ID:        group.ID,
+			Name:      group.LocalName(lang),
+			Shortcut:  group.GroupShortcut,
+			IsDeleted: group.DeletedAt.Valid,
+		}
+		tree.nodes[model.TypeGroup][group.ID] = node
+		tree.parents[node] = parent
+		parent.Children = append(parent.Children, node)
+	}
+
+	for _, team := range teams {
+		parent := tree.nodes[model.TypeGroup][uint(team.GroupID)]
+		if parent == nil {
+			continue
+		}
+		node := &model.OrgNode{
+			Type:      model.TypeTeam,
+			ID:        team.ID,
+			Name:      team.LocalName(lang),
+			Shortcut:  team.TeamShortcut,
+			IsDeleted: team.DeletedAt.Valid,
+		}
+		tree.nodes[model.TypeTeam][team.ID] = node
+		tree.parents[node] = parent
+		parent.Children = append(parent.Children, node)
+	}
+
+	for _, employee := range employees {
+		parent := tree.nodes[model.TypeTeam][*employee.TeamID]
+		if parent == nil {
+			continue
+		}
+		// Nhân viên đã xoá vẫn hiện khi include_deleted nhưng không tính vào member_count
+		node := &model.OrgNode{
+			Type:      model.TypeEmployee,
+			ID:        employee.ID,
+			Code:      employee.EmployeeCode,
+			Name:      employee.LocalName(lang),
+			IsDeleted: employee.DeletedAt.Valid,
+		}
+		if !node.IsDeleted {
+			node.MemberCount = 1
+		}
+		parent.Children = append(parent.Children, node)
+	}
+
+	for _, department := range tree.departments {
+		countMember(department)
+	}
+
+	return tree, nil
+}
+
+func countMember(node *model.OrgNode) int {
+	if node.Type == model.TypeEmployee {
+		return node.MemberCount
+	}
+
+	node.MemberCount = 0
+	for _, child := range node.Children {
+		node.MemberCount += countMember(child)
+	}
+
+	return node.MemberCount
+}
+
+// Bỏ các cấp sâu hơn depth, member_count giữ nguyên
+func trim(node *model.OrgNode, depth int) {
+	if depth == 0 {
+		node.Children = nil
+		return
+	}
+
+	for _, child := range node.Children {
+		trim(child, depth-1)
+	}
+}
@@ -0,0 +1,684 @@
+package controller
+
+import (
+	"app/config"
+	"app/database"
+	"app/modules/org"
+	"app/modules/org/model"
+	"app/modules/translation"
+	"app/utils"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetUnitType Lấy danh sách loại đơn vị
+// @Summary Get org unit types
+// @Description Returns the configured org unit types ordered by rank (top level first)
+// @Tags Org
+// @Accept json
+// @Produce json
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /org/unit-type [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetUnitType(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var types []model.OrgUnitType
+	if err := database.DB.Order("rank").Find(&types).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	for i := range types {
+		types[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = types
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// SaveUnitType Thêm / sửa loại đơn vị
+// @Summary Create or update org unit types
+// @Description Upserts org unit types by type_code. A child unit must have a greater rank than its parent
+// @Tags Org
+// @Accept json
+// @Produce json
+// @Param body body []model.OrgUnitType true "Org unit types"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /org/unit-type [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func SaveUnitType(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.OrgUnitType
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		listCheck := []string{"TypeCode", "TypeNameVN", "TypeNameEN", "TypeNameJP"}
+		vItem := map[string]string{
+			"TypeCode":   item.TypeCode,
+			"TypeNameVN": item.TypeNameVN,
+			"TypeNameEN": item.TypeNameEN,
+			"TypeNameJP": item.TypeNameJP,
+		}
+		errors := utils.RequireCheck(listCheck, vItem, map[string]string{})
+		errors = utils.MaxLengthCheck([]string{"TypeCode:30", "TypeNameVN:100", "TypeNameEN:100", "TypeNameJP:100"}, vItem, errors)
+
+		if len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("MISSING_FIELDS")
+			response.ValidateError = errors
+			return c.JSON(response)
+		}
+
+		if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(item).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			return c.JSON(response)
+		}
+	}
+
+	// Đổi rank không được làm đơn vị con đứng trên đơn vị cha
+	var invalid int64
+	tx.Table("tbl_org_unit AS u").
+		Joins("JOIN tbl_org_unit AS p ON p.org_unit_id = u.parent_id").
+		Joins("JOIN tbl_org_unit_type AS ut ON ut.type_code = u.type_code").
+		Joins("JOIN tbl_org_unit_type AS pt ON pt.type_code = p.type_code").
+		Where("u.deleted_at IS NULL AND p.deleted_at IS NULL AND ut.rank <= pt.rank").
+		Count(&invalid)
+	if invalid > 0 {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("VALUE_INVALID")
+		response.ValidateError = map[string]string{"Rank": config.GetMessageCode("VALUE_INVALID")}
+		return c.JSON(response)
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetUnit Lấy danh sách đơn vị
+// @Summary Get org units
+// @Description Returns all org units, optionally of one type
+// @Tags Org
+// @Accept json
+// @Produce json
+// @Param type_code query string false "Type of the unit"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /org/unit [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetUnit(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	query := database.DB.Preload("Translations").Order("org_unit_id")
+	if typeCode := c.Query("type_code"); len(typeCode) > 0 {
+		query = query.Where("type_code = ?", typeCode)
+	}
+
+	var units []model.OrgUnit
+	if err := query.Find(&units).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	for i := range units {
+		units[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = units
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetAllUnit Lấy danh sách đơn vị đã bị xoá
+// @Summary Get all org units (deleted)
+// @Description Returns a list of all org units (soft-deleted)
+// @Tags Org
+// @Accept json
+// @Produce json
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /org/unit/all [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetAllUnit(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var units []model.OrgUnit
+	results := database.DB.Unscoped().Preload("Translations").Where("deleted_at IS NOT NULL").Order("org_unit_id").Find(&units)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	for i := range units {
+		units[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = units
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetUnitByID returns information about an org unit based on its ID
+// @Summary Get an org unit by ID
+// @Description Returns information about an org unit based on its ID
+// @Tags Org
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the org unit"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /org/unit/{id} [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetUnitByID(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var unit model.OrgUnit
+	if err := database.DB.Preload("Translations").Where("org_unit_id = ?", c.Params("id")).First(&unit).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	unit.Localize(utils.Language(c), utils.AllTranslations(c))
+	response.Data = unit
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetUnitDescendant Lấy các đơn vị cấp dưới
+// @Summary Get descendants of an org unit
+// @Description Returns every unit below the given one with its distance, nearest first. depth limits the distance
+// @Tags Org
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the org unit"
+// @Param depth query int false "Maximum distance (default: all)"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /org/unit/{id}/descendant [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetUnitDescendant(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	query := database.DB.Table("tbl_org_unit").
+		Select("tbl_org_unit.*, tbl_org_unit_path.depth").
+		Joins("JOIN tbl_org_unit_path ON tbl_org_unit_path.descendant_id = tbl_org_unit.org_unit_id").
+		Where("tbl_org_unit_path.ancestor_id = ? AND tbl_org_unit_path.depth > 0 AND tbl_org_unit.deleted_at IS NULL", c.Params("id")).
+		Order("tbl_org_unit_path.depth, tbl_org_unit.org_unit_id")
+	if depth, err := strconv.Atoi(c.Query("depth")); err == nil {
+		query = query.Where("tbl_org_unit_path.depth <= ?", depth)
+	}
+
+	var units []model.OrgUnitNode
+	if err := query.Scan(&units).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	localizeNode(c, units)
+	response.Data = units
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetUnitAncestor Lấy các đơn vị cấp trên
+// @Summary Get ancestors of an org unit
+// @Description Returns every unit above the given one, from its parent up to the root
+// @Tags Org
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the org unit"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /org/unit/{id}/ancestor [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetUnitAncestor(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var units []model.OrgUnitNode
+	results := database.DB.Table("tbl_org_unit").
+		Select("tbl_org_unit.*, tbl_org_unit_path.depth").
+		Joins("JOIN tbl_org_unit_path ON tbl_org_unit_path.ancestor_id = tbl_org_unit.org_unit_id").
+		Where("tbl_org_unit_path.descendant_id = ? AND tbl_org_unit_path.depth > 0", c.Params("id")).
+		Order("tbl_org_unit_path.depth").
+		Scan(&units)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	localizeNode(c, units)
+	response.Data = units
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// CreateUnit Tạo mới đơn vị
+// @Summary Create new org units
+// @Description Creates org units in one transaction. Items are created in order, so a later item can use an earlier one as parent only by ID.
+// @Description department / group / team units are also created in tbl_department / tbl_group / tbl_team (a team holds employees),
+// @Description so a group / team needs a department / group above it. Other types (division, squad...) only group the units below them
+// @Tags Org
+// @Accept json
+// @Produce json
+// @Param body body []model.CreateOrgUnitModel true "New org unit information"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /org/unit [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func CreateUnit(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.CreateOrgUnitModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		errors := checkUnit(tx, item, 0)
+
+		if len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("MISSING_FIELDS")
+			response.ValidateError = errors
+			return c.JSON(response)
+		}
+
+		newUnit := model.OrgUnit{
+			ParentID:     item.ParentID,
+			TypeCode:     item.TypeCode,
+			UnitNameVN:   item.UnitNameVN,
+			UnitNameEN:   item.UnitNameEN,
+			UnitNameJP:   item.UnitNameJP,
+			UnitShortcut: item.UnitShortcut,
+			CreatedBy:    getUsername(c),
+		}
+
+		if err := tx.Create(&newUnit).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("CREATE_FAIL")
+			return c.JSON(response)
+		}
+
+		if err := org.InsertPath(tx, newUnit.ID, newUnit.ParentID); err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("CREATE_FAIL")
+			return c.JSON(response)
+		}
+
+		// department / group / team được tạo cả ở bảng cũ
+		if err := org.SaveUnit(tx, &newUnit, getUsername(c)); err != nil {
+			tx.Rollback()
+			return c.JSON(legacyFail(response, err, "CREATE_FAIL"))
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("CREATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// UpdateUnit cập nhật đơn vị, đổi parent_id sẽ chuyển cả cây con
+// @Summary Update org units
+// @Description Updates org units based on their ID. Changing parent_id moves the whole subtree. Soft deletes them when is_deleted is set.
+// @Description Changes of department / group / team units are written to the legacy tables too. type_code cannot change from or to these types
+// @Tags Org
+// @Accept json
+// @Produce json
+// @Param body body []model.UpdateOrgUnitModel true "Org unit information to update"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /org/unit [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func UpdateUnit(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.UpdateOrgUnitModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		var unit model.OrgUnit
+		if err := tx.First(&unit, item.OrgUnitID).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+			return c.JSON(response)
+		}
+		moved := false
+
+		if item.IsDeleted {
+			if hasChildren(tx, unit.ID) {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("HAS_CHILDREN")
+				return c.JSON(response)
+			}
+			unit.DeletedBy = getUsername(c)
+			unit.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		} else {
+			errors := checkUnit(tx, &item.CreateOrgUnitModel, unit.ID)
+
+			// Không đổi loại từ / sang department, group, team (đơn vị gắn với bảng cũ)
+			if item.TypeCode != unit.TypeCode && (len(unit.LegacyType) > 0 || org.IsUnitType(item.TypeCode)) {
+				errors["TypeCode"] = config.GetMessageCode("VALUE_INVALID")
+			}
+
+			if len(errors) > 0 {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("MISSING_FIELDS")
+				response.ValidateError = errors
+				return c.JSON(response)
+			}
+
+			moved = !sameParent(unit.ParentID, item.ParentID)
+			if moved {
+				if err := org.MovePath(tx, unit.ID, item.ParentID); err != nil {
+					tx.Rollback()
+					response.Status = false
+					response.Message = config.GetMessageCode("SYSTEM_ERROR")
+					return c.JSON(response)
+				}
+			}
+
+			unit.ParentID = item.ParentID
+			unit.TypeCode = item.TypeCode
+			unit.UnitNameVN = item.UnitNameVN
+			unit.UnitNameEN = item.UnitNameEN
+			unit.UnitNameJP = item.UnitNameJP
+			unit.UnitShortcut = item.UnitShortcut
+			unit.UpdatedBy = getUsername(c)
+			unit.LogVersion++
+		}
+
+		if err := tx.Save(&unit).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			return c.JSON(response)
+		}
+
+		// Ghi sang bảng cũ, chuyển cây con thì cả các đơn vị bên dưới
+		err := org.SaveUnit(tx, &unit, getUsername(c))
+		if err == nil && moved {
+			err = org.SaveSubtree(tx, unit.ID, getUsername(c))
+		}
+		if err != nil {
+			tx.Rollback()
+			return c.JSON(legacyFail(response, err, "SYSTEM_ERROR"))
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// DeleteUnit xóa một đơn vị dựa trên ID
+// @Summary Delete org unit
+// @Description Soft deletes an org unit based on its ID. Units that still have active children cannot be deleted
+// @Tags Org
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the org unit"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /org/unit/{id} [delete]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func DeleteUnit(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var unit model.OrgUnit
+	if err := database.DB.First(&unit, c.Params("id")).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	if hasChildren(database.DB, unit.ID) {
+		response.Status = false
+		response.Message = config.GetMessageCode("HAS_CHILDREN")
+		return c.JSON(response)
+	}
+
+	unit.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	unit.DeletedBy = getUsername(c)
+
+	tx := database.DB.Begin()
+
+	if err := tx.Model(&unit).Updates(&unit).Error; err != nil {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	if err := org.SaveUnit(tx, &unit, getUsername(c)); err != nil {
+		tx.Rollback()
+		return c.JSON(legacyFail(response, err, "SYSTEM_ERROR"))
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("DELETE_SUCCESS")
+	return c.JSON(response)
+}
+
+// RestoreUnit khôi phục một đơn vị dựa trên ID
+// @Summary Restore org unit
+// @Description Restores a soft-deleted org unit. Its parent must be active
+// @Tags Org
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the org unit"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /org/unit/restore/{id} [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func RestoreUnit(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var unit model.OrgUnit
+	if err := database.DB.Unscoped().Where("deleted_at IS NOT NULL").First(&unit, c.Params("id")).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	if unit.ParentID != nil {
+		var count int64
+		database.DB.Model(&model.OrgUnit{}).Where("org_unit_id = ?", *unit.ParentID).Count(&count)
+		if count == 0 {
+			response.Status = false
+			response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+			return c.JSON(response)
+		}
+	}
+
+	tx := database.DB.Begin()
+
+	results := tx.Unscoped().Model(&unit).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"deleted_by": "",
+		"updated_by": getUsername(c),
+	})
+	if results.Error != nil {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("RESTORE_FAIL")
+		return c.JSON(response)
+	}
+
+	if err := org.RestoreUnit(tx, &unit, getUsername(c)); err != nil {
+		tx.Rollback()
+		return c.JSON(legacyFail(response, err, "RESTORE_FAIL"))
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("RESTORE_SUCCESS")
+	return c.JSON(response)
+}
+
+// Kiểm tra dữ liệu đơn vị, unitID là đơn vị đang được cập nhật (0 khi tạo mới)
+func checkUnit(tx *gorm.DB, item *model.CreateOrgUnitModel, unitID uint) map[string]string {
+	vItem := map[string]string{
+		"TypeCode":     item.TypeCode,
+		"UnitNameVN":   item.UnitNameVN,
+		"UnitNameEN":   item.UnitNameEN,
+		"UnitNameJP":   item.UnitNameJP,
+		"UnitShortcut": item.UnitShortcut,
+	}
+	errors := utils.RequireCheck([]string{"TypeCode", "UnitNameVN", "UnitNameEN", "UnitNameJP"}, vItem, map[string]string{})
+	errors = utils.MaxLengthCheck([]string{"UnitNameVN:100", "UnitNameEN:100", "UnitNameJP:100", "UnitShortcut:5"}, vItem, errors)
+	if _, ok := errors["TypeCode"]; ok {
+		return errors
+	}
+
+	var unitType model.OrgUnitType
+	if err := tx.Where("type_code = ?", item.TypeCode).First(&unitType).Error; err != nil {
+		errors["TypeCode"] = config.GetMessageCode("NOT_ID_EXISTS")
+		return errors
+	}
+
+	if item.ParentID != nil {
+		var parent model.OrgUnit
+		var parentType model.OrgUnitType
+		switch {
+		case tx.First(&parent, *item.ParentID).Error != nil:
+			errors["ParentID"] = config.GetMessageCode("NOT_ID_EXISTS")
+		case unitID > 0 && org.IsDescendant(tx, unitID, parent.ID):
+			// Không chuyển đơn vị vào chính cây con của nó
+			errors["ParentID"] = config.GetMessageCode("VALUE_INVALID")
+		case tx.Where("type_code = ?", parent.TypeCode).First(&parentType).Error != nil || parentType.Rank >= unitType.Rank:
+			errors["TypeCode"] = config.GetMessageCode("VALUE_INVALID")
+		}
+	}
+
+	// Đơn vị con trực tiếp vẫn phải ở cấp thấp hơn
+	if unitID > 0 {
+		var invalid int64
+		tx.Model(&model.OrgUnit{}).
+			Joins("JOIN tbl_org_unit_type ON tbl_org_unit_type.type_code = tbl_org_unit.type_code").
+			Where("tbl_org_unit.parent_id = ? AND tbl_org_unit_type.rank <= ?", unitID, unitType.Rank).
+			Count(&invalid)
+		if invalid > 0 {
+			errors["TypeCode"] = config.GetMessageCode("VALUE_INVALID")
+		}
+	}
+
+	return errors
+}
+
+// Lỗi khi ghi sang bảng cũ: không có department / group phía trên thì báo ParentID, lỗi khác trả về message
+func legacyFail(response *config.DataResponse, err error, message string) *config.DataResponse {
+	response.Status = false
+	if errors.Is(err, org.ErrNoLegacyParent) {
+		response.Message = config.GetMessageCode("VALUE_INVALID")
+		response.ValidateError = map[string]string{"ParentID": config.GetMessageCode("VALUE_INVALID")}
+		return response
+	}
+
+	response.Message = config.GetMessageCode(message)
+	return response
+}
+
+func hasChildren(tx *gorm.DB, unitID uint) bool {
+	var count int64
+	tx.Model(&model.OrgUnit{}).Where("parent_id = ?", unitID).Count(&count)
+	return count > 0
+}
+
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+func getUsername(c *fiber.Ctx) string {
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		return ""
+	}
+
+	return tokenData.Username
+}
+
+// Đơn vị lấy bằng Scan không preload được Translations
+func localizeNode(c *fiber.Ctx, units []model.OrgUnitNode) {
+	ids := make([]uint, len(units))
+	for i := range units {
+		ids[i] = units[i].ID
+	}
+
+	translations := translation.Find(database.DB, "org_unit", ids)
+	for i := range units {
+		units[i].Translations = translations[units[i].ID]
+		units[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+}
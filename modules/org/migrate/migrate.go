@@ -0,0 +1,65 @@
+package orgMigrate
+
+import (
+	"app/core"
+	"app/database"
+	departmentModel "app/modules/department/model"
+	groupModel "app/modules/group/model"
+	"app/modules/org"
+	model "app/modules/org/model"
+	teamModel "app/modules/team/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Ba loại đơn vị tương ứng các bảng cũ, chừa khoảng trống để chèn cấp trên / dưới (division, squad...)
+var defaultTypes = []model.OrgUnitType{
+	{TypeCode: "department", TypeNameVN: "Phòng ban", TypeNameEN: "Department", TypeNameJP: "部署", Rank: 20},
+	{TypeCode: "group", TypeNameVN: "Nhóm", TypeNameEN: "Group", TypeNameJP: "グループ", Rank: 30},
+	{TypeCode: "team", TypeNameVN: "Đội", TypeNameEN: "Team", TypeNameJP: "チーム", Rank: 40},
+}
+
+func MigrateTbl() bool {
+	db := database.DB
+
+	db.AutoMigrate(&model.OrgUnitType{}, &model.OrgUnit{}, &model.OrgUnitPath{}, &model.OrgChangeSet{}, &model.OrgChange{})
+
+	db.Clauses(clause.OnConflict{DoNothing: true}).Create(&defaultTypes)
+
+	if err := ImportLegacy(db); err != nil {
+		core.WriteLog("ERROR | IMPORT ORG UNIT | " + err.Error())
+	}
+
+	return true
+}
+
+// ImportLegacy chuyển tbl_department, tbl_group, tbl_team sang tbl_org_unit.
+// Bản ghi đã chuyển (theo legacy_type / legacy_id) được cập nhật lại theo bảng cũ nên có thể chạy nhiều lần
+func ImportLegacy(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		imports := []struct {
+			unitType string
+			model    interface{}
+			column   string
+		}{
+			{"department", &departmentModel.Department{}, "department_id"},
+			{"group", &groupModel.Group{}, "group_id"},
+			{"team", &teamModel.Team{}, "team_id"},
+		}
+
+		for _, item := range imports {
+			var ids []uint
+			if err := tx.Unscoped().Model(item.model).Order(item.column).Pluck(item.column, &ids).Error; err != nil {
+				return err
+			}
+			for _, id := range ids {
+				if err := org.SyncUnit(tx, item.unitType, id, ""); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,315 @@
+package org
+
+import (
+	departmentModel "app/modules/department/model"
+	groupModel "app/modules/group/model"
+	"app/modules/history"
+	"app/modules/org/model"
+	teamModel "app/modules/team/model"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrNoLegacyParent đơn vị group / team không có cấp trên department / group tương ứng trong cây tbl_org_unit
+var ErrNoLegacyParent = errors.New("legacy parent not found")
+
+// SyncUnit cập nhật đơn vị tbl_org_unit của bản ghi cũ legacyType / legacyID (department, group, team), tạo mới nếu chưa có.
+// Gọi trong cùng transaction sau mỗi lần ghi tbl_department / tbl_group / tbl_team để hai cây không lệch nhau
+func SyncUnit(tx *gorm.DB, legacyType string, legacyID uint, username string) error {
+	source, legacyParentID, err := legacyUnit(tx, legacyType, legacyID)
+	if err != nil {
+		return err
+	}
+
+	// Cấp cha theo bảng cũ, đồng bộ trước nếu chưa có
+	var parentID *uint
+	if parentType := legacyTables[legacyType].parentType; len(parentType) > 0 {
+		parent, err := mirrorUnit(tx, parentType, legacyParentID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if err := SyncUnit(tx, parentType, legacyParentID, username); err != nil {
+				return err
+			}
+			parent, err = mirrorUnit(tx, parentType, legacyParentID)
+		}
+		if err != nil {
+			return err
+		}
+		parentID = &parent.ID
+	}
+
+	unit, err := mirrorUnit(tx, legacyType, legacyID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		source.ParentID = parentID
+		if err := tx.Create(&source).Error; err != nil {
+			return err
+		}
+		if err := InsertPath(tx, source.ID, source.ParentID); err != nil {
+			return err
+		}
+		return copyTranslations(tx, legacyType, legacyID, source.ID)
+	}
+	if err != nil {
+		return err
+	}
+
+	changed := unit.UnitNameVN != source.UnitNameVN || unit.UnitNameEN != source.UnitNameEN || unit.UnitNameJP != source.UnitNameJP ||
+		unit.UnitShortcut != source.UnitShortcut || unit.DeletedAt.Valid != source.DeletedAt.Valid
+
+	// Giữ nguyên cấp cha nếu vẫn nằm trong cây con của cấp cha cũ (vd: squad chèn giữa group và team),
+	// department không có cấp cha trong bảng cũ nên giữ nguyên (vd: division)
+	if parentID != nil && (unit.ParentID == nil || !IsDescendant(tx, *parentID, *unit.ParentID)) {
+		if err := MovePath(tx, unit.ID, parentID); err != nil {
+			return err
+		}
+		unit.ParentID = parentID
+		changed = true
+	}
+
+	if changed {
+		unit.UnitNameVN, unit.UnitNameEN, unit.UnitNameJP = source.UnitNameVN, source.UnitNameEN, source.UnitNameJP
+		unit.UnitShortcut = source.UnitShortcut
+		unit.DeletedAt, unit.DeletedBy = source.DeletedAt, source.DeletedBy
+		unit.UpdatedBy = username
+		unit.LogVersion++
+		if err := tx.Unscoped().Save(&unit).Error; err != nil {
+			return err
+		}
+	}
+
+	return copyTranslations(tx, legacyType, legacyID, unit.ID)
+}
+
+// SaveUnit ghi đơn vị department / group / team tạo, sửa, xoá qua /org/unit sang bảng cũ (kèm lịch sử phiên bản như change set),
+// để team tạo ở đây cũng nhận được nhân viên. Loại đơn vị khác (division, squad...) chỉ có trong tbl_org_unit
+func SaveUnit(tx *gorm.DB, unit *model.OrgUnit, username string) error {
+	legacy, ok := legacyTables[unit.TypeCode]
+	if !ok {
+		return nil
+	}
+
+	var parentID *uint
+	if len(legacy.parentType) > 0 {
+		if parentID = legacyParentID(tx, unit.ParentID, legacy.parentType); parentID == nil {
+			return ErrNoLegacyParent
+		}
+	}
+
+	change := model.OrgChange{
+		UnitType: unit.TypeCode,
+		UnitID:   unit.LegacyID,
+		ParentID: parentID,
+		NameVN:   unit.UnitNameVN,
+		NameEN:   unit.UnitNameEN,
+		NameJP:   unit.UnitNameJP,
+		Shortcut: unit.UnitShortcut,
+	}
+
+	if unit.LegacyID == nil {
+		change.Action = model.ActionCreate
+		_, err := applyLegacyChange(tx, &change, unit, username)
+		return err
+	}
+
+	if unit.DeletedAt.Valid {
+		change.Action = model.ActionDissolve
+		_, err := applyLegacyChange(tx, &change, unit, username)
+		return err
+	}
+
+	source, legacyParent, err := legacyUnit(tx, unit.TypeCode, *unit.LegacyID)
+	if err != nil {
+		return err
+	}
+	if source.UnitNameVN != unit.UnitNameVN || source.UnitNameEN != unit.UnitNameEN || source.UnitNameJP != unit.UnitNameJP || source.UnitShortcut != unit.UnitShortcut {
+		change.Action = model.ActionRename
+		if _, err := applyLegacyChange(tx, &change, unit, username); err != nil {
+			return err
+		}
+	}
+	if parentID != nil && *parentID != legacyParent {
+		change.Action = model.ActionMove
+		if _, err := applyLegacyChange(tx, &change, unit, username); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RenameUnit đổi tên ngôn ngữ locale (vn / en / jp) của department / group / team qua đường rename của change set:
+// tăng log_version, ghi lịch sử (team / group), đồng bộ tbl_translation và tbl_org_unit
+func RenameUnit(tx *gorm.DB, legacyType string, legacyID uint, locale, value, username string) error {
+	source, _, err := legacyUnit(tx, legacyType, legacyID)
+	if err != nil {
+		return err
+	}
+
+	change := model.OrgChange{
+		Action:   model.ActionRename,
+		UnitType: legacyType,
+		UnitID:   &legacyID,
+		NameVN:   source.UnitNameVN,
+		NameEN:   source.UnitNameEN,
+		NameJP:   source.UnitNameJP,
+		Shortcut: source.UnitShortcut,
+	}
+	switch locale {
+	case "vn":
+		change.NameVN = value
+	case "en":
+		change.NameEN = value
+	case "jp":
+		change.NameJP = value
+	default:
+		return fmt.Errorf("no name column for locale %s", locale)
+	}
+
+	_, err = applyChange(tx, &change, username)
+	return err
+}
+
+// RestoreUnit khôi phục bản ghi cũ của đơn vị đã xoá, cấp cha trong bảng cũ phải còn hoạt động
+func RestoreUnit(tx *gorm.DB, unit *model.OrgUnit, username string) error {
+	legacy, ok := legacyTables[unit.LegacyType]
+	if !ok || unit.LegacyID == nil {
+		return nil
+	}
+	id := *unit.LegacyID
+
+	_, legacyParent, err := legacyUnit(tx, unit.LegacyType, id)
+	if err != nil {
+		return err
+	}
+	if len(legacy.parentType) > 0 && !activeUnit(tx, legacy.parentType, legacyParent) {
+		return ErrNoLegacyParent
+	}
+
+	before, _ := historyUnit(tx, unit.LegacyType, id)
+	err = tx.Table(legacy.table).Where(legacy.idColumn+" = ?", id).Updates(map[string]interface{}{
+		"deleted_at":  nil,
+		"deleted_by":  "",
+		"updated_by":  username,
+		"updated_at":  time.Now(),
+		"log_version": gorm.Expr("log_version + 1"),
+	}).Error
+	if err != nil {
+		return err
+	}
+	if err := SyncUnit(tx, unit.LegacyType, id, username); err != nil {
+		return err
+	}
+
+	after, version := historyUnit(tx, unit.LegacyType, id)
+	if after == nil {
+		return nil
+	}
+
+	return history.Record(tx, unit.LegacyType, id, version, history.ActionRestore, before, after, username)
+}
+
+// Đơn vị tbl_org_unit (kể cả đã xoá) của bản ghi cũ
+func mirrorUnit(tx *gorm.DB, legacyType string, legacyID uint) (model.OrgUnit, error) {
+	var unit model.OrgUnit
+	err := tx.Unscoped().Where("legacy_type = ? AND legacy_id = ?", legacyType, legacyID).First(&unit).Error
+	return unit, err
+}
+
+// Bản ghi cũ dạng OrgUnit (chưa có ParentID) kèm id bản ghi cũ của cấp cha
+func legacyUnit(tx *gorm.DB, legacyType string, legacyID uint) (model.OrgUnit, uint, error) {
+	unit := model.OrgUnit{TypeCode: legacyType, LegacyType: legacyType, LegacyID: &legacyID}
+	var parentID uint
+
+	switch legacyType {
+	case "department":
+		var department departmentModel.Department
+		if err := tx.Unscoped().First(&department, legacyID).Error; err != nil {
+			return unit, 0, err
+		}
+		unit.UnitNameVN, unit.UnitNameEN, unit.UnitNameJP = department.DepartmentNameVN, department.DepartmentNameEN, department.DepartmentNameJP
+		unit.UnitShortcut = department.DepartmentShortcut
+		unit.CreatedBy, unit.DeletedBy = department.CreatedBy, department.DeletedBy
+		unit.DeletedAt = department.DeletedAt
+	case "group":
+		var group groupModel.Group
+		if err := tx.Unscoped().First(&group, legacyID).Error; err != nil {
+			return unit, 0, err
+		}
+		unit.UnitNameVN, unit.UnitNameEN, unit.UnitNameJP = group.GroupNameVN, group.GroupNameEN, group.GroupNameJP
+		unit.UnitShortcut = group.GroupShortcut
+		unit.CreatedBy, unit.DeletedBy = group.CreatedBy, group.DeletedBy
+		unit.DeletedAt = group.DeletedAt
+		parentID = uint(group.DepartmentID)
+	case "team":
+		var team teamModel.Team
+		if err := tx.Unscoped().First(&team, legacyID).Error; err != nil {
+			return unit, 0, err
+		}
+		unit.UnitNameVN, unit.UnitNameEN, unit.UnitNameJP = team.TeamNameVN, team.TeamNameEN, team.TeamNameJP
+		unit.UnitShortcut = team.TeamShortcut
+		unit.CreatedBy, unit.DeletedBy = team.CreatedBy, team.DeletedBy
+		unit.DeletedAt = team.DeletedAt
+		parentID = uint(team.GroupID)
+	default:
+		return unit, 0, fmt.Errorf("unknown unit type")
+	}
+
+	return unit, parentID, nil
+}
+
+// Id bản ghi cũ của đơn vị gần nhất loại parentType từ parentID trở lên, nil nếu không có
+func legacyParentID(tx *gorm.DB, parentID *uint, parentType string) *uint {
+	if parentID == nil {
+		return nil
+	}
+
+	var parent model.OrgUnit
+	err := tx.Joins("JOIN tbl_org_unit_path ON tbl_org_unit_path.ancestor_id = tbl_org_unit.org_unit_id").
+		Where("tbl_org_unit_path.descendant_id = ? AND tbl_org_unit.legacy_type = ?", *parentID, parentType).
+		Order("tbl_org_unit_path.depth").
+		First(&parent).Error
+	if err != nil {
+		return nil
+	}
+
+	return parent.LegacyID
+}
+
+// Chép bản dịch tên của bản ghi cũ (kể cả ngôn ngữ không có cột vn / en / jp) sang đơn vị, bỏ bản dịch đã bị xoá ở bản ghi cũ
+func copyTranslations(tx *gorm.DB, legacyType string, legacyID, unitID uint) error {
+	err := tx.Exec(`INSERT INTO tbl_translation (entity_type, entity_id, field, locale, value, created_at, updated_at, updated_by)
+		SELECT 'org_unit', ?, field, locale, value, created_at, updated_at, updated_by FROM tbl_translation
+		WHERE entity_type = ? AND entity_id = ? AND field = 'name'
+		ON CONFLICT (entity_type, entity_id, field, locale) DO UPDATE
+		SET value = excluded.value, updated_at = excluded.updated_at, updated_by = excluded.updated_by`, unitID, legacyType, legacyID).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Exec(`DELETE FROM tbl_translation WHERE entity_type = 'org_unit' AND entity_id = ? AND field = 'name'
+		AND locale NOT IN (SELECT locale FROM tbl_translation WHERE entity_type = ? AND entity_id = ? AND field = 'name')`,
+		unitID, legacyType, legacyID).Error
+}
+
+// SaveSubtree gọi SaveUnit cho đơn vị và các đơn vị con chưa xoá, dùng sau khi chuyển cả cây con
+// (cấp trên department / group gần nhất của các đơn vị bên dưới có thể đã đổi)
+func SaveSubtree(tx *gorm.DB, unitID uint, username string) error {
+	var units []model.OrgUnit
+	err := tx.Joins("JOIN tbl_org_unit_path ON tbl_org_unit_path.descendant_id = tbl_org_unit.org_unit_id").
+		Where("tbl_org_unit_path.ancestor_id = ?", unitID).
+		Order("tbl_org_unit_path.depth, tbl_org_unit.org_unit_id").
+		Find(&units).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range units {
+		if err := SaveUnit(tx, &units[i], username); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package org
+
+import (
+	"app/database/testdb"
+	groupModel "app/modules/group/model"
+	historyModel "app/modules/history/model"
+	"app/modules/org/model"
+	teamModel "app/modules/team/model"
+	translationModel "app/modules/translation/model"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+// openOrg CSDL có cây tổ chức cũ, tbl_org_unit và các loại đơn vị, models là bảng thêm cho test
+func openOrg(t *testing.T, models ...interface{}) *gorm.DB {
+	db := testdb.Open(t, testdb.OrgModels, &historyModel.VersionHistory{}, &model.OrgUnitType{}, &model.OrgUnit{}, &model.OrgUnitPath{}, models)
+	db.Create(&[]model.OrgUnitType{
+		{TypeCode: "department", TypeNameVN: "Phòng ban", TypeNameEN: "Department", TypeNameJP: "部署", Rank: 20},
+		{TypeCode: "group", TypeNameVN: "Nhóm", TypeNameEN: "Group", TypeNameJP: "グループ", Rank: 30},
+		{TypeCode: "squad", TypeNameVN: "Tổ", TypeNameEN: "Squad", TypeNameJP: "班", Rank: 35},
+		{TypeCode: "team", TypeNameVN: "Đội", TypeNameEN: "Team", TypeNameJP: "チーム", Rank: 40},
+	})
+
+	return db
+}
+
+func mirror(t *testing.T, db *gorm.DB, legacyType string, legacyID uint) model.OrgUnit {
+	t.Helper()
+
+	unit, err := mirrorUnit(db, legacyType, legacyID)
+	if err != nil {
+		t.Fatalf("%s %d has no org unit: %v", legacyType, legacyID, err)
+	}
+
+	return unit
+}
+
+func TestSyncUnit(t *testing.T) {
+	db := openOrg(t)
+
+	seed := testdb.SeedOrg(t, db)
+	department, group, team := seed.Department, seed.Group, seed.Team
+
+	// Team chưa có đơn vị: tạo cả các cấp cha
+	if err := SyncUnit(db, "team", team.ID, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	groupUnit := mirror(t, db, "group", group.ID)
+	teamUnit := mirror(t, db, "team", team.ID)
+	if teamUnit.ParentID == nil || *teamUnit.ParentID != groupUnit.ID || !IsDescendant(db, mirror(t, db, "department", department.ID).ID, teamUnit.ID) {
+		t.Fatalf("team unit not below its group: %+v", teamUnit)
+	}
+
+	// Squad chèn giữa group và team vẫn được giữ khi đổi tên team
+	squad := model.OrgUnit{ParentID: &groupUnit.ID, TypeCode: "squad", UnitNameVN: "Tổ 1", UnitNameEN: "Squad 1", UnitNameJP: "1班"}
+	db.Create(&squad)
+	InsertPath(db, squad.ID, squad.ParentID)
+	MovePath(db, teamUnit.ID, &squad.ID)
+	db.Model(&teamUnit).Update("parent_id", squad.ID)
+
+	db.Model(&team).Updates(map[string]interface{}{"team_name_en": "Morning shift"})
+	db.Create(&translationModel.Translation{EntityType: "team", EntityID: team.ID, Field: "name", Locale: "ko", Value: "오전조"})
+	if err := SyncUnit(db, "team", team.ID, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	teamUnit = mirror(t, db, "team", team.ID)
+	if teamUnit.UnitNameEN != "Morning shift" || *teamUnit.ParentID != squad.ID || teamUnit.LogVersion != 1 {
+		t.Fatalf("after rename: %+v", teamUnit)
+	}
+	var ko translationModel.Translation
+	if err := db.Where("entity_type = 'org_unit' AND entity_id = ? AND locale = 'ko'", teamUnit.ID).First(&ko).Error; err != nil || ko.Value != "오전조" {
+		t.Fatalf("ko translation not copied: %+v %v", ko, err)
+	}
+
+	// Team chuyển sang group khác thì ra khỏi squad
+	other := groupModel.Group{DepartmentID: int(department.ID), GroupNameVN: "Kiểm tra", GroupNameEN: "Inspection", GroupNameJP: "検査"}
+	db.Create(&other)
+	db.Model(&team).Update("group_id", other.ID)
+	if err := SyncUnit(db, "team", team.ID, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	teamUnit = mirror(t, db, "team", team.ID)
+	if otherUnit := mirror(t, db, "group", other.ID); *teamUnit.ParentID != otherUnit.ID || IsDescendant(db, squad.ID, teamUnit.ID) {
+		t.Fatalf("team unit not moved to the new group: %+v", teamUnit)
+	}
+
+	// Xoá ở bảng cũ thì xoá đơn vị
+	db.Delete(&team)
+	if err := SyncUnit(db, "team", team.ID, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	if teamUnit = mirror(t, db, "team", team.ID); !teamUnit.DeletedAt.Valid {
+		t.Fatalf("team unit not deleted: %+v", teamUnit)
+	}
+}
+
+func TestSaveUnit(t *testing.T) {
+	db := openOrg(t)
+
+	group := testdb.SeedOrg(t, db).Group
+	if err := SyncUnit(db, "group", group.ID, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	groupUnit := mirror(t, db, "group", group.ID)
+
+	squad := model.OrgUnit{ParentID: &groupUnit.ID, TypeCode: "squad", UnitNameVN: "Tổ 1", UnitNameEN: "Squad 1", UnitNameJP: "1班"}
+	db.Create(&squad)
+	InsertPath(db, squad.ID, squad.ParentID)
+
+	// Team tạo qua /org/unit dưới squad: tạo cả tbl_team thuộc group gần nhất
+	unit := model.OrgUnit{ParentID: &squad.ID, TypeCode: "team", UnitNameVN: "Ca B", UnitNameEN: "Shift B", UnitNameJP: "B班"}
+	db.Create(&unit)
+	InsertPath(db, unit.ID, unit.ParentID)
+	if err := SaveUnit(db, &unit, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	if unit.LegacyID == nil || unit.LegacyType != "team" {
+		t.Fatalf("unit not linked to a team: %+v", unit)
+	}
+	var team teamModel.Team
+	if err := db.First(&team, *unit.LegacyID).Error; err != nil || team.GroupID != int(group.ID) || team.TeamNameEN != "Shift B" {
+		t.Fatalf("legacy team %+v, %v", team, err)
+	}
+	if reloaded := mirror(t, db, "team", team.ID); reloaded.ID != unit.ID || *reloaded.ParentID != squad.ID {
+		t.Fatalf("mirror moved out of the squad: %+v", reloaded)
+	}
+
+	// Đổi tên ghi sang tbl_team kèm lịch sử
+	unit.UnitNameEN = "Evening shift"
+	db.Save(&unit)
+	if err := SaveUnit(db, &unit, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	db.First(&team, team.ID)
+	var versions int64
+	db.Model(&historyModel.VersionHistory{}).Where("entity_type = 'team' AND entity_id = ?", team.ID).Count(&versions)
+	if team.TeamNameEN != "Evening shift" || team.LogVersion != 1 || versions != 2 {
+		t.Fatalf("after rename: %+v, %d versions", team, versions)
+	}
+
+	// Team không có group phía trên
+	orphan := model.OrgUnit{TypeCode: "team", UnitNameVN: "X", UnitNameEN: "X", UnitNameJP: "X"}
+	db.Create(&orphan)
+	InsertPath(db, orphan.ID, nil)
+	if err := SaveUnit(db, &orphan, "admin"); err != ErrNoLegacyParent {
+		t.Fatalf("got %v, want ErrNoLegacyParent", err)
+	}
+
+	// Xoá rồi khôi phục
+	db.Delete(&unit)
+	db.Unscoped().First(&unit, unit.ID)
+	if err := SaveUnit(db, &unit, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	if db.First(&teamModel.Team{}, team.ID).Error == nil {
+		t.Fatal("legacy team not deleted")
+	}
+	db.Unscoped().Model(&unit).Update("deleted_at", nil)
+	if err := RestoreUnit(db, &unit, "admin"); err != nil {
+		t.Fatal(err)
+	}
+	if db.First(&teamModel.Team{}, team.ID).Error != nil {
+		t.Fatal("legacy team not restored")
+	}
+}
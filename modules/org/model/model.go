@@ -0,0 +1,213 @@
+package model
+
+import (
+	"app/config"
+	"app/modules/translation"
+	translationModel "app/modules/translation/model"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Loại node trong cây tổ chức, theo thứ tự từ trên xuống
+const (
+	TypeDepartment = "department"
+	TypeGroup      = "group"
+	TypeTeam       = "team"
+	TypeEmployee   = "employee"
+)
+
+// OrgNode một node của cây department → group → team → employee.
+// MemberCount là số nhân viên trong cả cây con, kể cả khi không trả về các cấp bên dưới (depth)
+type OrgNode struct {
+	Type        string     `json:"type"`
+	ID          uint       `json:"id"`
+	Code        string     `json:"code,omitempty"`
+	Name        string     `json:"name"`
+	Shortcut    string     `json:"shortcut,omitempty"`
+	MemberCount int        `json:"member_count"`
+	IsDeleted   bool       `json:"is_deleted"`
+	Pending     bool       `json:"pending,omitempty"` // bị thay đổi bởi change set chưa áp dụng (xem trước as_of)
+	Children    []*OrgNode `json:"children,omitempty"`
+}
+
+type Model struct {
+	ID        uint `gorm:"primarykey;column:org_unit_id;<-:create"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// OrgUnitType loại đơn vị (division, department, group, team, squad...).
+// Rank nhỏ ở trên: đơn vị con phải có Rank lớn hơn đơn vị cha
+type OrgUnitType struct {
+	TypeCode   string `gorm:"primaryKey;column:type_code;size:30" json:"type_code"`
+	TypeNameVN string `gorm:"column:type_name_vn;size:100;not null" json:"type_name_vn"`
+	TypeNameEN string `gorm:"column:type_name_en;size:100;not null" json:"type_name_en"`
+	TypeNameJP string `gorm:"column:type_name_jp;size:100;not null" json:"type_name_jp"`
+	Name       string `gorm:"-" json:"name"` // tên theo ngôn ngữ của request, xem Localize
+	Rank       int    `gorm:"column:rank;not null" json:"rank"`
+}
+
+// OrgUnit đơn vị tổ chức nhiều cấp.
+// LegacyType / LegacyID trỏ về bản ghi tbl_department, tbl_group, tbl_team đã được chuyển sang
+type OrgUnit struct {
+	Model
+	ParentID     *uint                          `gorm:"column:parent_id;index"`
+	TypeCode     string                         `gorm:"column:type_code;size:30;not null;index"`
+	UnitNameVN   string                         `gorm:"column:unit_name_vn;size:100;not null"`
+	UnitNameEN   string                         `gorm:"column:unit_name_en;size:100;not null"`
+	UnitNameJP   string                         `gorm:"column:unit_name_jp;size:100;not null"`
+	Name         string                         `gorm:"-" json:"name"`            // tên theo ngôn ngữ của request, xem Localize
+	Names        map[string]string              `gorm:"-" json:"names,omitempty"` // mọi bản dịch của tên (ngôn ngữ → tên), chỉ trả về khi all
+	Translations []translationModel.Translation `gorm:"polymorphic:Entity;polymorphicValue:org_unit;constraint:-" json:"-"`
+	UnitShortcut string                         `gorm:"column:unit_shortcut;size:5"`
+	LegacyType   string                         `gorm:"column:legacy_type;size:20;uniqueIndex:idx_org_unit_legacy"`
+	LegacyID     *uint                          `gorm:"column:legacy_id;uniqueIndex:idx_org_unit_legacy"`
+	LogVersion   int64                          `gorm:"column:log_version;default:0"`
+	CreatedBy    string                         `gorm:"column:created_by;size:15"`
+	UpdatedBy    string                         `gorm:"column:updated_by;size:15"`
+	DeletedBy    string                         `gorm:"column:deleted_by;size:15"`
+}
+
+// Localize điền Name theo ngôn ngữ lang (có fallback)
+func (t *OrgUnitType) Localize(lang string, all bool) {
+	t.Name = config.LocalName(lang, t.TypeNameVN, t.TypeNameEN, t.TypeNameJP)
+}
+
+// Localize điền Name theo ngôn ngữ lang (có fallback), all thì trả thêm Names
+func (u *OrgUnit) Localize(lang string, all bool) {
+	names := u.names()
+	u.Name = config.LocalValue(lang, names)
+	if all {
+		u.Names = names
+	}
+}
+
+// LocalName tên theo ngôn ngữ lang
+func (u *OrgUnit) LocalName(lang string) string {
+	return config.LocalValue(lang, u.names())
+}
+
+// Tên theo các cột vn / en / jp, ghi đè bằng Translations nếu đã preload
+func (u *OrgUnit) names() map[string]string {
+	return translation.Values(u.Translations, "name", map[string]string{"vn": u.UnitNameVN, "en": u.UnitNameEN, "jp": u.UnitNameJP})
+}
+
+// AfterSave đồng bộ các cột tên sang tbl_translation
+func (u *OrgUnit) AfterSave(tx *gorm.DB) error {
+	return translation.Save(tx, "org_unit", u.ID, "name", map[string]string{"vn": u.UnitNameVN, "en": u.UnitNameEN, "jp": u.UnitNameJP}, u.UpdatedBy)
+}
+
+// OrgUnitPath closure table: mỗi cặp tổ tiên / hậu duệ một dòng, Depth 0 là chính nó
+type OrgUnitPath struct {
+	AncestorID   uint `gorm:"primaryKey;column:ancestor_id"`
+	DescendantID uint `gorm:"primaryKey;column:descendant_id;index"`
+	Depth        int  `gorm:"column:depth;not null"`
+}
+
+type CreateOrgUnitModel struct {
+	ParentID     *uint  `json:"parent_id"`
+	TypeCode     string `json:"type_code" validate:"required"`
+	UnitNameVN   string `json:"unit_name_vn" validate:"required"`
+	UnitNameEN   string `json:"unit_name_en" validate:"required"`
+	UnitNameJP   string `json:"unit_name_jp" validate:"required"`
+	UnitShortcut string `json:"unit_shortcut"`
+}
+
+type UpdateOrgUnitModel struct {
+	OrgUnitID uint `json:"org_unit_id" validate:"required"`
+	CreateOrgUnitModel
+	IsDeleted bool `json:"is_deleted"`
+}
+
+// OrgUnitNode đơn vị kèm khoảng cách tới đơn vị đang truy vấn
+type OrgUnitNode struct {
+	OrgUnit
+	Depth int `json:"depth"`
+}
+
+// Tên bảng trong CSDL
+func (OrgUnitType) TableName() string {
+	return "tbl_org_unit_type"
+}
+
+func (OrgUnit) TableName() string {
+	return "tbl_org_unit"
+}
+
+func (OrgUnitPath) TableName() string {
+	return "tbl_org_unit_path"
+}
+
+// Trạng thái change set
+const (
+	ChangeStatusPending   = "pending"
+	ChangeStatusApplied   = "applied"
+	ChangeStatusCancelled = "cancelled"
+	ChangeStatusFailed    = "failed"
+)
+
+// Thao tác trong change set
+const (
+	ActionCreate   = "create"
+	ActionRename   = "rename"
+	ActionMove     = "move"
+	ActionDissolve = "dissolve"
+)
+
+// OrgChangeSet nhóm thay đổi tổ chức có hiệu lực từ EffectiveDate, scheduler áp dụng trong một transaction
+type OrgChangeSet struct {
+	ID            uint        `gorm:"primarykey;column:change_set_id;<-:create" json:"change_set_id"`
+	Title         string      `gorm:"column:title;size:200;not null" json:"title"`
+	EffectiveDate time.Time   `gorm:"column:effective_date;type:date;not null;index" json:"effective_date"`
+	Status        string      `gorm:"column:status;size:10;not null;default:pending;index" json:"status"`
+	AppliedAt     *time.Time  `gorm:"column:applied_at" json:"applied_at"`
+	Error         string      `gorm:"column:error;size:500" json:"error"`
+	Changes       []OrgChange `gorm:"foreignKey:ChangeSetID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"changes"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	CreatedBy     string      `gorm:"column:created_by;size:15" json:"created_by"`
+	CancelledBy   string      `gorm:"column:cancelled_by;size:15" json:"cancelled_by"`
+}
+
+// OrgChange một thay đổi trên department / group / team.
+// ParentID là department_id của group hoặc group_id của team (create, move)
+type OrgChange struct {
+	ID          uint   `gorm:"primarykey;column:change_id;<-:create" json:"change_id"`
+	ChangeSetID uint   `gorm:"column:change_set_id;not null;index" json:"change_set_id"`
+	Seq         int    `gorm:"column:seq;not null" json:"seq"`
+	Action      string `gorm:"column:action;size:10;not null" json:"action"`
+	UnitType    string `gorm:"column:unit_type;size:20;not null" json:"unit_type"`
+	UnitID      *uint  `gorm:"column:unit_id" json:"unit_id"`
+	ParentID    *uint  `gorm:"column:parent_id" json:"parent_id"`
+	NameVN      string `gorm:"column:name_vn;size:100" json:"name_vn"`
+	NameEN      string `gorm:"column:name_en;size:100" json:"name_en"`
+	NameJP      string `gorm:"column:name_jp;size:100" json:"name_jp"`
+	Shortcut    string `gorm:"column:shortcut;size:5" json:"shortcut"`
+}
+
+type OrgChangeModel struct {
+	Action   string `json:"action" validate:"required"`    // create | rename | move | dissolve
+	UnitType string `json:"unit_type" validate:"required"` // department | group | team
+	UnitID   *uint  `json:"unit_id"`
+	ParentID *uint  `json:"parent_id"`
+	NameVN   string `json:"name_vn"`
+	NameEN   string `json:"name_en"`
+	NameJP   string `json:"name_jp"`
+	Shortcut string `json:"shortcut"`
+}
+
+type CreateOrgChangeSetModel struct {
+	Title         string            `json:"title" validate:"required"`
+	EffectiveDate string            `json:"effective_date" validate:"required"` // YYYY-MM-DD
+	Changes       []*OrgChangeModel `json:"changes" validate:"required"`
+}
+
+func (OrgChangeSet) TableName() string {
+	return "tbl_org_change_set"
+}
+
+func (OrgChange) TableName() string {
+	return "tbl_org_change"
+}
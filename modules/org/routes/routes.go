@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"app/middleware"
+
+	"app/modules/org/controller"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func InitOrgRoutes(app *fiber.App) {
+	org := app.Group("/org", middleware.AppInfo, middleware.AppAuthen)
+
+	getList := org.Group("")
+	getList.Get("/tree", middleware.Require("org:read"), controller.GetTree)
+	getList.Get("/unit-type", middleware.Require("org:read"), controller.GetUnitType)
+	getList.Get("/unit", middleware.Require("org:read"), controller.GetUnit)
+	getList.Get("/unit/all", middleware.Require("org:read"), controller.GetAllUnit) // khai báo trước /unit/:id
+	getList.Get("/unit/:id", middleware.Require("org:read"), controller.GetUnitByID)
+	getList.Get("/unit/:id/descendant", middleware.Require("org:read"), controller.GetUnitDescendant)
+	getList.Get("/unit/:id/ancestor", middleware.Require("org:read"), controller.GetUnitAncestor)
+	getList.Get("/change", middleware.Require("org:read"), controller.GetChangeSet)
+
+	org.Put("/unit-type", middleware.Require("org:write"), controller.SaveUnitType)
+	org.Post("/unit", middleware.Require("org:write"), controller.CreateUnit)
+	org.Put("/unit", middleware.Require("org:write"), controller.UpdateUnit)
+	org.Delete("/unit/:id", middleware.Require("org:write"), controller.DeleteUnit)
+	org.Put("/unit/restore/:id", middleware.Require("org:write"), controller.RestoreUnit)
+	org.Post("/change", middleware.Require("org:write"), controller.CreateChangeSet)
+	org.Delete("/change/:id", middleware.Require("org:write"), controller.CancelChangeSet)
+}
@@ -0,0 +1,325 @@
+package org
+
+import (
+	"app/core"
+	"app/database"
+	employeeModel "app/modules/employee/model"
+	groupModel "app/modules/group/model"
+	"app/modules/history"
+	"app/modules/org/model"
+	teamModel "app/modules/team/model"
+	"app/modules/translation"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Bảng cũ của từng cấp: cột khoá, tiền tố cột tên, cột trỏ lên cấp cha và cấp con trực tiếp
+type legacyTable struct {
+	table      string
+	idColumn   string
+	prefix     string
+	parentType string
+	parentCol  string
+	childType  string
+}
+
+var legacyTables = map[string]legacyTable{
+	"department": {table: "tbl_department", idColumn: "department_id", prefix: "department", childType: "group"},
+	"group":      {table: "tbl_group", idColumn: "group_id", prefix: "group", parentType: "department", parentCol: "department_id", childType: "team"},
+	"team":       {table: "tbl_team", idColumn: "team_id", prefix: "team", parentType: "group", parentCol: "group_id"},
+}
+
+// IsUnitType true với department, group, team
+func IsUnitType(unitType string) bool {
+	_, ok := legacyTables[unitType]
+	return ok
+}
+
+// ApplyChangeSet áp dụng lần lượt các thay đổi trên tx, trả về các đơn vị bị ảnh hưởng theo loại.
+// Lỗi ở bất kỳ thay đổi nào thì caller rollback cả change set
+func ApplyChangeSet(tx *gorm.DB, set *model.OrgChangeSet) (map[string]map[uint]bool, error) {
+	affected := map[string]map[uint]bool{}
+	for _, change := range set.Changes {
+		id, err := applyChange(tx, &change, set.CreatedBy)
+		if err != nil {
+			return nil, fmt.Errorf("change %d (%s %s): %w", change.Seq, change.Action, change.UnitType, err)
+		}
+
+		if affected[change.UnitType] == nil {
+			affected[change.UnitType] = map[uint]bool{}
+		}
+		affected[change.UnitType][id] = true
+	}
+
+	return affected, nil
+}
+
+// Áp dụng một thay đổi và ghi lịch sử phiên bản cho team / group giống như sửa qua API
+func applyChange(tx *gorm.DB, change *model.OrgChange, username string) (uint, error) {
+	return applyLegacyChange(tx, change, nil, username)
+}
+
+// unit khác nil: đơn vị tbl_org_unit đang được ghi qua /org/unit, được gắn với bản ghi cũ vừa tạo (create)
+func applyLegacyChange(tx *gorm.DB, change *model.OrgChange, unit *model.OrgUnit, username string) (uint, error) {
+	var before interface{}
+	if change.UnitID != nil && change.Action != model.ActionCreate {
+		before, _ = historyUnit(tx, change.UnitType, *change.UnitID)
+	}
+
+	id, err := applyUnitChange(tx, change, username)
+	if err != nil {
+		return 0, err
+	}
+
+	if unit != nil && unit.LegacyID == nil {
+		unit.LegacyType, unit.LegacyID = change.UnitType, &id
+		if err := tx.Model(unit).Updates(map[string]interface{}{"legacy_type": unit.LegacyType, "legacy_id": id}).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	// Tên được ghi bằng SQL nên không qua hook AfterSave của model
+	if change.Action == model.ActionCreate || change.Action == model.ActionRename {
+		if err := translation.SyncLegacy(tx, change.UnitType, id, username); err != nil {
+			return 0, err
+		}
+	}
+	if err := SyncUnit(tx, change.UnitType, id, username); err != nil {
+		return 0, err
+	}
+
+	after, version := historyUnit(tx, change.UnitType, id)
+	if after == nil {
+		return id, nil
+	}
+
+	action := history.ActionUpdate
+	switch change.Action {
+	case model.ActionCreate:
+		action = history.ActionCreate
+	case model.ActionDissolve:
+		action = history.ActionDelete
+	}
+
+	return id, history.Record(tx, change.UnitType, id, version, action, before, after, username)
+}
+
+// Bản ghi dạng struct của đơn vị có lịch sử phiên bản (team, group), nil với loại khác
+func historyUnit(tx *gorm.DB, unitType string, id uint) (interface{}, int64) {
+	switch unitType {
+	case "team":
+		var team teamModel.Team
+		if err := tx.Unscoped().First(&team, id).Error; err == nil {
+			return team, team.LogVersion
+		}
+	case "group":
+		var group groupModel.Group
+		if err := tx.Unscoped().First(&group, id).Error; err == nil {
+			return group, group.LogVersion
+		}
+	}
+
+	return nil, 0
+}
+
+func applyUnitChange(tx *gorm.DB, change *model.OrgChange, username string) (uint, error) {
+	legacy, ok := legacyTables[change.UnitType]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit type")
+	}
+	now := time.Now()
+
+	if change.Action != model.ActionCreate {
+		if change.UnitID == nil || !activeUnit(tx, change.UnitType, *change.UnitID) {
+			return 0, fmt.Errorf("unit not found")
+		}
+	}
+	if change.Action == model.ActionCreate || change.Action == model.ActionMove {
+		if len(legacy.parentType) > 0 && (change.ParentID == nil || !activeUnit(tx, legacy.parentType, *change.ParentID)) {
+			return 0, fmt.Errorf("parent not found")
+		}
+	}
+
+	switch change.Action {
+	case model.ActionCreate:
+		values := map[string]interface{}{
+			legacy.prefix + "_name_vn":  change.NameVN,
+			legacy.prefix + "_name_en":  change.NameEN,
+			legacy.prefix + "_name_jp":  change.NameJP,
+			legacy.prefix + "_shortcut": change.Shortcut,
+			"created_by":                username,
+			"created_at":                now,
+			"updated_at":                now,
+		}
+		if len(legacy.parentCol) > 0 {
+			values[legacy.parentCol] = *change.ParentID
+		}
+
+		var id uint
+		err := tx.Raw(insertSQL(legacy, values), values).Scan(&id).Error
+		return id, err
+
+	case model.ActionRename:
+		return *change.UnitID, updateUnit(tx, legacy, *change.UnitID, map[string]interface{}{
+			legacy.prefix + "_name_vn":  change.NameVN,
+			legacy.prefix + "_name_en":  change.NameEN,
+			legacy.prefix + "_name_jp":  change.NameJP,
+			legacy.prefix + "_shortcut": change.Shortcut,
+			"updated_by":                username,
+			"updated_at":                now,
+			"log_version":               gorm.Expr("log_version + 1"),
+		})
+
+	case model.ActionMove:
+		if len(legacy.parentCol) == 0 {
+			return 0, fmt.Errorf("%s has no parent", change.UnitType)
+		}
+		return *change.UnitID, updateUnit(tx, legacy, *change.UnitID, map[string]interface{}{
+			legacy.parentCol: *change.ParentID,
+			"updated_by":     username,
+			"updated_at":     now,
+			"log_version":    gorm.Expr("log_version + 1"),
+		})
+
+	case model.ActionDissolve:
+		if child, ok := legacyTables[legacy.childType]; ok {
+			var count int64
+			tx.Table(child.table).Where(legacy.idColumn+" = ? AND deleted_at IS NULL", *change.UnitID).Count(&count)
+			if count > 0 {
+				return 0, fmt.Errorf("unit still has active %s", legacy.childType)
+			}
+		}
+		if change.UnitType == "team" && hasMembers(tx, *change.UnitID, now) {
+			return 0, fmt.Errorf("unit still has active employee")
+		}
+		return *change.UnitID, updateUnit(tx, legacy, *change.UnitID, map[string]interface{}{
+			"deleted_by":  username,
+			"deleted_at":  now,
+			"log_version": gorm.Expr("log_version + 1"),
+		})
+	}
+
+	return 0, fmt.Errorf("unknown action %s", change.Action)
+}
+
+func activeUnit(tx *gorm.DB, unitType string, id uint) bool {
+	legacy := legacyTables[unitType]
+	var count int64
+	tx.Table(legacy.table).Where(legacy.idColumn+" = ? AND deleted_at IS NULL", id).Count(&count)
+	return count > 0
+}
+
+// Team còn nhân viên: tbl_employee.team_id trỏ tới team hoặc membership chưa kết thúc trước ngày date
+func hasMembers(tx *gorm.DB, teamID uint, date time.Time) bool {
+	var count int64
+	tx.Model(&employeeModel.Employee{}).Where("team_id = ?", teamID).Count(&count)
+	if count > 0 {
+		return true
+	}
+
+	day, _ := time.Parse("2006-01-02", date.Format("2006-01-02"))
+	tx.Model(&employeeModel.TeamMembership{}).Where("team_id = ? AND (valid_to IS NULL OR valid_to >= ?)", teamID, day).Count(&count)
+	return count > 0
+}
+
+func updateUnit(tx *gorm.DB, legacy legacyTable, id uint, values map[string]interface{}) error {
+	return tx.Table(legacy.table).Where(legacy.idColumn+" = ? AND deleted_at IS NULL", id).Updates(values).Error
+}
+
+// INSERT ... RETURNING để lấy id của đơn vị mới
+func insertSQL(legacy legacyTable, values map[string]interface{}) string {
+	columns, params := "", ""
+	for column := range values {
+		if len(columns) > 0 {
+			columns += ", "
+			params += ", "
+		}
+		columns += column
+		params += "@" + column
+	}
+
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s", legacy.table, columns, params, legacy.idColumn)
+}
+
+// PendingChangeSets các change set pending có hiệu lực đến asOf theo thứ tự scheduler sẽ áp dụng (kèm Changes)
+func PendingChangeSets(db *gorm.DB, asOf time.Time) ([]model.OrgChangeSet, error) {
+	var sets []model.OrgChangeSet
+	err := db.Preload("Changes", func(db *gorm.DB) *gorm.DB { return db.Order("seq") }).
+		Where("status = ? AND effective_date <= ?", model.ChangeStatusPending, asOf).
+		Order("effective_date, change_set_id").
+		Find(&sets).Error
+
+	return sets, err
+}
+
+// ApplyDue áp dụng các change set đến hạn, mỗi change set một transaction
+func ApplyDue() {
+	var sets []model.OrgChangeSet
+	database.DB.Where("status = ? AND effective_date <= CURRENT_DATE", model.ChangeStatusPending).
+		Order("effective_date, change_set_id").
+		Find(&sets)
+
+	for _, set := range sets {
+		applyDueSet(set.ID)
+	}
+
+	syncEmployeeTeam()
+}
+
+func applyDueSet(changeSetID uint) {
+	tx := database.DB.Begin()
+
+	// SKIP LOCKED: nhiều instance chạy scheduler thì mỗi change set chỉ một instance áp dụng
+	var set model.OrgChangeSet
+	results := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Preload("Changes", func(db *gorm.DB) *gorm.DB { return db.Order("seq") }).
+		Where("change_set_id = ? AND status = ?", changeSetID, model.ChangeStatusPending).
+		First(&set)
+	if results.Error != nil {
+		tx.Rollback()
+		return
+	}
+
+	now := time.Now()
+	if _, err := ApplyChangeSet(tx, &set); err != nil {
+		tx.Rollback()
+		database.DB.Model(&set).Updates(map[string]interface{}{"status": model.ChangeStatusFailed, "error": err.Error()})
+		core.WriteLog(fmt.Sprintf("ORG CHANGE | FAILED | %d | %s", set.ID, err.Error()))
+		return
+	}
+
+	if err := tx.Model(&set).Updates(map[string]interface{}{"status": model.ChangeStatusApplied, "applied_at": now}).Error; err != nil {
+		tx.Rollback()
+		return
+	}
+
+	tx.Commit()
+	core.WriteLog(fmt.Sprintf("ORG CHANGE | APPLIED | %d | %s", set.ID, set.Title))
+}
+
+// Cập nhật tbl_employee.team_id theo membership có hiệu lực hôm nay (chuyển team đặt ngày trong tương lai)
+func syncEmployeeTeam() {
+	database.DB.Exec(`UPDATE tbl_employee e SET team_id = m.team_id
+		FROM tbl_team_membership m
+		WHERE m.employee_id = e.employee_id AND m.valid_from <= CURRENT_DATE AND (m.valid_to IS NULL OR m.valid_to >= CURRENT_DATE)
+		AND e.team_id IS DISTINCT FROM m.team_id`)
+
+	database.DB.Exec(`UPDATE tbl_employee e SET team_id = NULL
+		WHERE e.team_id IS NOT NULL
+		AND EXISTS (SELECT 1 FROM tbl_team_membership m WHERE m.employee_id = e.employee_id AND m.valid_from <= CURRENT_DATE)
+		AND NOT EXISTS (SELECT 1 FROM tbl_team_membership m WHERE m.employee_id = e.employee_id
+			AND m.valid_from <= CURRENT_DATE AND (m.valid_to IS NULL OR m.valid_to >= CURRENT_DATE))`)
+}
+
+// StartScheduler chạy ApplyDue theo chu kỳ, gọi bằng goroutine trong main
+func StartScheduler(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ApplyDue()
+	}
+}
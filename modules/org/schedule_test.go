@@ -0,0 +1,61 @@
+package org
+
+import (
+	"app/database/testdb"
+	"app/modules/org/model"
+	teamModel "app/modules/team/model"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplyDissolveTeam(t *testing.T) {
+	db := openOrg(t, testdb.EmployeeModels)
+
+	org := testdb.SeedOrg(t, db)
+	today, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	teams := map[string]teamModel.Team{}
+	for _, name := range []string{"current employee", "open membership", "membership ends today", "deleted employee", "past membership"} {
+		teams[name] = testdb.SeedTeam(t, db, org.Group.ID, name)
+	}
+
+	current, deletedTeam := teams["current employee"].ID, teams["deleted employee"].ID
+	testdb.SeedEmployee(t, db, "1001", &current)
+	employee := testdb.SeedEmployee(t, db, "1002", nil)
+	testdb.SeedMembership(t, db, employee.ID, teams["open membership"].ID, today.AddDate(0, 0, -30))
+	ending := testdb.SeedMembership(t, db, employee.ID, teams["membership ends today"].ID, today.AddDate(0, 0, -30))
+	db.Model(&ending).Update("valid_to", today)
+	ended := testdb.SeedMembership(t, db, employee.ID, teams["past membership"].ID, today.AddDate(0, 0, -30))
+	db.Model(&ended).Update("valid_to", today.AddDate(0, 0, -1))
+	deleted := testdb.SeedEmployee(t, db, "1003", &deletedTeam)
+	db.Delete(&deleted)
+
+	cases := []struct {
+		name string
+		ok   bool
+	}{
+		{"current employee", false},
+		{"open membership", false},
+		{"membership ends today", false},
+		{"deleted employee", true},
+		{"past membership", true},
+	}
+
+	for _, item := range cases {
+		team := teams[item.name]
+		set := model.OrgChangeSet{CreatedBy: "admin", Changes: []model.OrgChange{{Seq: 1, Action: model.ActionDissolve, UnitType: model.TypeTeam, UnitID: &team.ID}}}
+
+		tx := db.Begin()
+		_, err := ApplyChangeSet(tx, &set)
+		tx.Commit()
+		if (err == nil) != item.ok || (err != nil && !strings.Contains(err.Error(), "active employee")) {
+			t.Errorf("%s: got %v, want ok = %v", item.name, err, item.ok)
+		}
+
+		var count int64
+		db.Model(&teamModel.Team{}).Where("team_id = ?", team.ID).Count(&count)
+		if (count == 0) != item.ok {
+			t.Errorf("%s: team dissolved = %v, want %v", item.name, count == 0, item.ok)
+		}
+	}
+}
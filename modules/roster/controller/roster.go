@@ -0,0 +1,606 @@
+package controller
+
+import (
+	"app/config"
+	"app/database"
+	employeeModel "app/modules/employee/model"
+	"app/modules/labor"
+	"app/modules/roster"
+	"app/modules/roster/model"
+	shiftModel "app/modules/shift/model"
+	teamModel "app/modules/team/model"
+	"app/utils"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// GetRoster Lấy danh sách lịch ca lặp lại
+// @Summary Get all Rosters
+// @Description Returns a list of recurring Rosters with their exceptions
+// @Tags Roster
+// @Accept json
+// @Produce json
+// @Param employee_id query int false "Only Rosters of this Employee"
+// @Param team_id query int false "Only Rosters of this Team"
+// @Param shift_id query int false "Only Rosters of this Shift template"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /roster [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetRoster(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	query := database.DB.Preload("Shift.Translations").Preload("Employee.Translations").Preload("Team.Translations").Preload("Exceptions", orderException)
+	if employeeID := c.Query("employee_id"); len(employeeID) > 0 {
+		query = query.Where("employee_id = ?", employeeID)
+	}
+	if teamID := c.Query("team_id"); len(teamID) > 0 {
+		query = query.Where("team_id = ?", teamID)
+	}
+	if shiftID := c.Query("shift_id"); len(shiftID) > 0 {
+		query = query.Where("shift_id = ?", shiftID)
+	}
+
+	var rosters []model.Roster
+	if err := query.Order("roster_id").Find(&rosters).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	for i := range rosters {
+		rosters[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = rosters
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetRosterByID Lấy thông tin lịch ca lặp lại theo ID
+// @Summary Get a Roster by ID
+// @Description Returns a recurring Roster with its exceptions
+// @Tags Roster
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Roster"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /roster/{id} [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetRosterByID(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var item model.Roster
+	results := database.DB.Preload("Shift.Translations").Preload("Shift.Children", orderChild).Preload("Employee.Translations").Preload("Team.Translations").Preload("Exceptions", orderException).
+		Where("roster_id = ?", c.Params("id")).First(&item)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	item.Localize(utils.Language(c), utils.AllTranslations(c))
+	response.Data = item
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetRosterInstance Sinh các ca cụ thể từ lịch ca lặp lại
+// @Summary Expand Rosters into shift instances
+// @Description Returns the concrete shifts of every Employee between from and to (both included, at most 366 days).
+// @Description Team Rosters are expanded for the members of the Team on each date, skipped occurrences are left out and swapped ones use the other Shift template
+// @Tags Roster
+// @Accept json
+// @Produce json
+// @Param from query string true "First date (YYYY-MM-DD)"
+// @Param to query string true "Last date (YYYY-MM-DD)"
+// @Param employee_id query int false "Only shifts of this Employee"
+// @Param team_id query int false "Only shifts of the members of this Team"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /roster/instance [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetRosterInstance(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	vItem := map[string]string{"From": c.Query("from"), "To": c.Query("to")}
+	errors := utils.RequireCheck([]string{"From", "To"}, vItem, map[string]string{})
+	errors = utils.DateFormatCheck([]string{"From", "To"}, vItem, errors)
+	if len(errors) == 0 {
+		from, to := parseDate(vItem["From"]), parseDate(vItem["To"])
+		if to.Before(*from) || to.Sub(*from) >= roster.MaxDays*24*time.Hour {
+			errors["To"] = config.GetMessageCode("VALUE_INVALID")
+		}
+	}
+	if len(errors) > 0 {
+		response.Status = false
+		response.Message = config.GetMessageCode("MISSING_FIELDS")
+		response.ValidateError = errors
+		return c.JSON(response)
+	}
+
+	instances, err := roster.Expand(database.DB, *parseDate(vItem["From"]), *parseDate(vItem["To"]), uint(c.QueryInt("employee_id")), uint(c.QueryInt("team_id")), utils.Language(c))
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	response.Data = instances
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// CreateRoster Tạo mới lịch ca lặp lại
+// @Summary Create new Rosters
+// @Description Assigns a Shift template to an Employee or a Team with an RFC 5545 RRULE, start_date is the DTSTART.
+// @Description Ex: weekdays "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", every other Saturday "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA",
+// @Description 4-on-4-off is four Rosters "FREQ=DAILY;INTERVAL=8" on four consecutive start dates.
+// @Description The new shifts are checked against the labor rules of the Employee's Department (see /labor), violations are returned per Employee and date in validate_error
+// @Tags Roster
+// @Accept json
+// @Produce json
+// @Param body body []model.CreateRosterModel true "New Roster information"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /roster [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func CreateRoster(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.CreateRosterModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		errors := checkRoster(tx, item)
+
+		if len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("MISSING_FIELDS")
+			response.ValidateError = errors
+			return c.JSON(response)
+		}
+
+		newRoster := model.Roster{CreatedBy: getUsername(c)}
+		setRoster(&newRoster, item)
+
+		if err := tx.Create(&newRoster).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("CREATE_FAIL")
+			return c.JSON(response)
+		}
+
+		if errors, err := checkLabor(tx, newRoster, nil); err != nil || len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			if err == nil {
+				response.Message = config.GetMessageCode("MISSING_FIELDS")
+				response.ValidateError = errors
+			}
+			return c.JSON(response)
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("CREATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// UpdateRoster cập nhật lịch ca lặp lại
+// @Summary Update Rosters
+// @Description Updates Rosters based on their ID, or soft deletes them when is_deleted is set. Exceptions are kept.
+// @Description The shifts are checked against the labor rules like on create
+// @Tags Roster
+// @Accept json
+// @Produce json
+// @Param body body []model.UpdateRosterModel true "Roster information to update"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /roster [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func UpdateRoster(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.UpdateRosterModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		var current model.Roster
+		if err := tx.First(&current, item.RosterID).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+			return c.JSON(response)
+		}
+
+		if item.IsDeleted {
+			current.DeletedBy = getUsername(c)
+			current.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		} else {
+			errors := checkRoster(tx, &item.CreateRosterModel)
+
+			if len(errors) > 0 {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("MISSING_FIELDS")
+				response.ValidateError = errors
+				return c.JSON(response)
+			}
+
+			setRoster(&current, &item.CreateRosterModel)
+			current.UpdatedBy = getUsername(c)
+			current.LogVersion++
+		}
+
+		if err := tx.Save(&current).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			return c.JSON(response)
+		}
+
+		if item.IsDeleted {
+			continue
+		}
+		if errors, err := checkLabor(tx, current, nil); err != nil || len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			if err == nil {
+				response.Message = config.GetMessageCode("MISSING_FIELDS")
+				response.ValidateError = errors
+			}
+			return c.JSON(response)
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// DeleteRoster xóa một lịch ca lặp lại dựa trên ID
+// @Summary Delete Roster
+// @Description Soft deletes a Roster based on its ID, its shifts are no longer expanded
+// @Tags Roster
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Roster"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /roster/{id} [delete]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func DeleteRoster(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var current model.Roster
+	if err := database.DB.First(&current, c.Params("id")).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	current.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	current.DeletedBy = getUsername(c)
+
+	if err := database.DB.Model(&current).Updates(&current).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("DELETE_SUCCESS")
+	return c.JSON(response)
+}
+
+// CreateRosterException Tạo ngoại lệ cho một lần lặp
+// @Summary Create Roster exceptions
+// @Description Skips one occurrence of a Roster (action skip) or works another Shift template on that date (action swap with shift_id).
+// @Description For a Team Roster, employee_id limits the exception to one member, otherwise it applies to the whole Team.
+// @Description A swap is checked against the labor rules for that date
+// @Tags Roster
+// @Accept json
+// @Produce json
+// @Param body body []model.CreateRosterExceptionModel true "Exceptions"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /roster/exception [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func CreateRosterException(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.CreateRosterExceptionModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		var current model.Roster
+		if err := tx.First(&current, item.RosterID).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+			return c.JSON(response)
+		}
+
+		errors := checkException(tx, current, item)
+
+		if len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("MISSING_FIELDS")
+			response.ValidateError = errors
+			return c.JSON(response)
+		}
+
+		exception := model.RosterException{
+			RosterID:   current.ID,
+			Date:       *parseDate(item.Date),
+			EmployeeID: item.EmployeeID,
+			Action:     item.Action,
+			Note:       item.Note,
+			CreatedBy:  getUsername(c),
+		}
+		if item.Action == model.ExceptionSwap {
+			exception.ShiftID = item.ShiftID
+		}
+
+		if err := tx.Create(&exception).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("CREATE_FAIL")
+			return c.JSON(response)
+		}
+
+		if item.Action != model.ExceptionSwap {
+			continue
+		}
+		if errors, err := checkLabor(tx, current, &exception); err != nil || len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			if err == nil {
+				response.Message = config.GetMessageCode("MISSING_FIELDS")
+				response.ValidateError = errors
+			}
+			return c.JSON(response)
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("CREATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// DeleteRosterException xóa ngoại lệ, lần lặp trở lại theo roster
+// @Summary Delete Roster exception
+// @Description Deletes an exception, the occurrence is worked as the Roster says again
+// @Tags Roster
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the exception"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /roster/exception/{id} [delete]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func DeleteRosterException(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var exception model.RosterException
+	if err := database.DB.First(&exception, c.Params("id")).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	if err := database.DB.Delete(&exception).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("DELETE_SUCCESS")
+	return c.JSON(response)
+}
+
+func checkRoster(tx *gorm.DB, item *model.CreateRosterModel) map[string]string {
+	vItem := map[string]string{
+		"RRule":     item.RRule,
+		"StartDate": item.StartDate,
+		"EndDate":   item.EndDate,
+	}
+	errors := utils.RequireCheck([]string{"RRule", "StartDate"}, vItem, map[string]string{})
+	errors = utils.MaxLengthCheck([]string{"RRule:255"}, vItem, errors)
+	listDate := []string{"StartDate"}
+	if len(item.EndDate) > 0 {
+		listDate = append(listDate, "EndDate")
+	}
+	errors = utils.DateFormatCheck(listDate, vItem, errors)
+
+	if err := tx.First(&shiftModel.Shift{}, item.ShiftID).Error; err != nil {
+		errors["ShiftID"] = config.GetMessageCode("NOT_ID_EXISTS")
+	}
+
+	// Đúng một trong nhân viên / team
+	switch {
+	case item.EmployeeID != nil && item.TeamID != nil:
+		errors["TeamID"] = config.GetMessageCode("VALUE_INVALID")
+	case item.EmployeeID != nil:
+		var employee employeeModel.Employee
+		if err := tx.First(&employee, *item.EmployeeID).Error; err != nil || employee.Status == employeeModel.StatusTerminated {
+			errors["EmployeeID"] = config.GetMessageCode("NOT_ID_EXISTS")
+		}
+	case item.TeamID != nil:
+		if err := tx.First(&teamModel.Team{}, *item.TeamID).Error; err != nil {
+			errors["TeamID"] = config.GetMessageCode("NOT_ID_EXISTS")
+		}
+	default:
+		errors["EmployeeID"] = config.GetMessageCode("REQUIRE")
+	}
+	if len(errors) > 0 {
+		return errors
+	}
+
+	start, end := parseDate(item.StartDate), parseDate(item.EndDate)
+	if end != nil && end.Before(*start) {
+		errors["EndDate"] = config.GetMessageCode("VALUE_INVALID")
+	}
+	if _, err := roster.Rule(item.RRule, *start, end); err != nil {
+		errors["RRule"] = config.GetMessageCode("VALUE_INVALID")
+	}
+
+	return errors
+}
+
+func checkException(tx *gorm.DB, current model.Roster, item *model.CreateRosterExceptionModel) map[string]string {
+	vItem := map[string]string{
+		"Date":   item.Date,
+		"Action": item.Action,
+		"Note":   item.Note,
+	}
+	errors := utils.RequireCheck([]string{"Date", "Action"}, vItem, map[string]string{})
+	errors = utils.MaxLengthCheck([]string{"Note:255"}, vItem, errors)
+	errors = utils.DateFormatCheck([]string{"Date"}, vItem, errors)
+
+	switch item.Action {
+	case "", model.ExceptionSkip:
+	case model.ExceptionSwap:
+		if item.ShiftID == nil {
+			errors["ShiftID"] = config.GetMessageCode("REQUIRE")
+		} else if err := tx.First(&shiftModel.Shift{}, *item.ShiftID).Error; err != nil {
+			errors["ShiftID"] = config.GetMessageCode("NOT_ID_EXISTS")
+		}
+	default:
+		errors["Action"] = config.GetMessageCode("VALUE_INVALID")
+	}
+
+	// Chỉ roster theo team mới có ngoại lệ riêng cho một nhân viên
+	if item.EmployeeID != nil {
+		if current.TeamID == nil {
+			errors["EmployeeID"] = config.GetMessageCode("VALUE_INVALID")
+		} else if err := tx.First(&employeeModel.Employee{}, *item.EmployeeID).Error; err != nil {
+			errors["EmployeeID"] = config.GetMessageCode("NOT_ID_EXISTS")
+		}
+	}
+	if _, ok := errors["Date"]; ok {
+		return errors
+	}
+
+	date := parseDate(item.Date)
+	if !roster.Occurs(current, *date) {
+		errors["Date"] = config.GetMessageCode("VALUE_INVALID")
+		return errors
+	}
+
+	query := tx.Model(&model.RosterException{}).Where("roster_id = ? AND date = ?", current.ID, *date)
+	if item.EmployeeID != nil {
+		query = query.Where("employee_id = ?", *item.EmployeeID)
+	} else {
+		query = query.Where("employee_id IS NULL")
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil || count > 0 {
+		errors["Date"] = config.GetMessageCode("CODE_EXISTS")
+	}
+
+	return errors
+}
+
+// Kiểm tra luật lao động (labor.Check) cho các ca vừa xếp, đã lưu trong tx:
+// cả roster trong labor.Window, hoặc chỉ lần lặp của ngoại lệ swap
+func checkLabor(tx *gorm.DB, current model.Roster, exception *model.RosterException) (map[string]string, error) {
+	from, to, ok := labor.Window(current.StartDate, current.EndDate)
+	if exception != nil {
+		from, to, ok = exception.Date, exception.Date, true
+	}
+	if !ok {
+		return map[string]string{}, nil
+	}
+
+	employeeIDs, err := roster.Employees(tx, current, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if exception != nil && exception.EmployeeID != nil {
+		employeeIDs = []uint{*exception.EmployeeID}
+	}
+
+	return labor.Check(tx, labor.ByRoster(current.ID), employeeIDs, from, to, map[string]string{})
+}
+
+func setRoster(current *model.Roster, item *model.CreateRosterModel) {
+	current.ShiftID = item.ShiftID
+	current.EmployeeID = item.EmployeeID
+	current.TeamID = item.TeamID
+	current.RRule = item.RRule
+	current.StartDate = *parseDate(item.StartDate)
+	current.EndDate = parseDate(item.EndDate)
+}
+
+func parseDate(value string) *time.Time {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil
+	}
+
+	return &date
+}
+
+// Khung giờ theo thứ tự đã nhập
+func orderChild(db *gorm.DB) *gorm.DB {
+	return db.Order("shift_child_id")
+}
+
+func orderException(db *gorm.DB) *gorm.DB {
+	return db.Order("date, exception_id")
+}
+
+func getUsername(c *fiber.Ctx) string {
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		return ""
+	}
+
+	return tokenData.Username
+}
@@ -0,0 +1,14 @@
+package rosterMigrate
+
+import (
+	"app/database"
+	model "app/modules/roster/model"
+)
+
+func MigrateTbl() bool {
+	db := database.DB
+
+	db.AutoMigrate(&model.Roster{}, &model.RosterException{})
+
+	return true
+}
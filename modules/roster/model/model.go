@@ -0,0 +1,116 @@
+package model
+
+import (
+	"time"
+
+	employeeModel "app/modules/employee/model"
+	shiftModel "app/modules/shift/model"
+	teamModel "app/modules/team/model"
+
+	"gorm.io/gorm"
+)
+
+// Loại ngoại lệ của một lần lặp
+const (
+	ExceptionSkip = "skip" // không làm ca ở lần lặp này
+	ExceptionSwap = "swap" // làm mẫu ca khác ở lần lặp này
+)
+
+// Roster lịch ca lặp lại: mẫu ca Shift cho một nhân viên hoặc cả team theo RRULE (RFC 5545).
+// StartDate là DTSTART của RRULE, EndDate (nếu có) là ngày cuối cùng được lặp.
+// Vd: ngày thường "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", thứ bảy cách tuần "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA",
+// 4 ngày làm 4 ngày nghỉ là 4 roster "FREQ=DAILY;INTERVAL=8" có StartDate liên tiếp nhau
+type Roster struct {
+	ID         uint                    `gorm:"primarykey;column:roster_id;<-:create" json:"roster_id"`
+	ShiftID    uint                    `gorm:"column:shift_id;not null;index" json:"shift_id"`
+	Shift      *shiftModel.Shift       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"shift,omitempty"`
+	EmployeeID *uint                   `gorm:"column:employee_id;index" json:"employee_id"`
+	Employee   *employeeModel.Employee `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"employee,omitempty"`
+	TeamID     *uint                   `gorm:"column:team_id;index" json:"team_id"`
+	Team       *teamModel.Team         `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"team,omitempty"`
+	RRule      string                  `gorm:"column:rrule;size:255;not null" json:"rrule"`
+	StartDate  time.Time               `gorm:"column:start_date;type:date;not null;index" json:"start_date"`
+	EndDate    *time.Time              `gorm:"column:end_date;type:date;index" json:"end_date"`
+	Exceptions []RosterException       `gorm:"foreignKey:RosterID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"exceptions,omitempty"`
+	LogVersion int64                   `gorm:"column:log_version;default:0" json:"log_version"`
+	CreatedAt  time.Time               `json:"created_at"`
+	UpdatedAt  time.Time               `json:"updated_at"`
+	DeletedAt  gorm.DeletedAt          `gorm:"index" json:"-"`
+	CreatedBy  string                  `gorm:"column:created_by;size:15" json:"created_by"`
+	UpdatedBy  string                  `gorm:"column:updated_by;size:15" json:"updated_by"`
+	DeletedBy  string                  `gorm:"column:deleted_by;size:15" json:"deleted_by"`
+}
+
+// RosterException ngoại lệ của một lần lặp (Date) của Roster: bỏ qua hoặc đổi sang mẫu ca ShiftID.
+// Với roster theo team, EmployeeID = nil là áp dụng cho cả team, có EmployeeID là chỉ nhân viên đó
+type RosterException struct {
+	ID         uint      `gorm:"primarykey;column:exception_id;<-:create" json:"exception_id"`
+	RosterID   uint      `gorm:"column:roster_id;not null;index" json:"roster_id"`
+	Date       time.Time `gorm:"column:date;type:date;not null;index" json:"date"`
+	EmployeeID *uint     `gorm:"column:employee_id" json:"employee_id"`
+	Action     string    `gorm:"column:action;size:10;not null" json:"action"`
+	ShiftID    *uint     `gorm:"column:shift_id" json:"shift_id"`
+	Note       string    `gorm:"column:note;size:255" json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+	CreatedBy  string    `gorm:"column:created_by;size:15" json:"created_by"`
+}
+
+// Localize điền Name của mẫu ca, nhân viên và team đã preload
+func (r *Roster) Localize(lang string, all bool) {
+	if r.Shift != nil {
+		r.Shift.Localize(lang, all)
+	}
+	if r.Employee != nil {
+		r.Employee.Localize(lang, all)
+	}
+	if r.Team != nil {
+		r.Team.Localize(lang, all)
+	}
+}
+
+// ShiftInstance một ca cụ thể của nhân viên sinh ra từ Roster, Start / End theo APP_TIME_ZONE
+type ShiftInstance struct {
+	RosterID    uint      `json:"roster_id"`
+	Date        string    `json:"date"`
+	EmployeeID  uint      `json:"employee_id"`
+	TeamID      *uint     `json:"team_id,omitempty"`
+	ShiftID     uint      `json:"shift_id"`
+	ShiftName   string    `json:"shift_name"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	WorkMinutes int       `json:"work_minutes"`
+	ExceptionID *uint     `json:"exception_id,omitempty"` // có khi lần lặp này đã bị swap
+}
+
+type CreateRosterModel struct {
+	ShiftID    uint   `json:"shift_id" validate:"required"`
+	EmployeeID *uint  `json:"employee_id"`
+	TeamID     *uint  `json:"team_id"`
+	RRule      string `json:"rrule" validate:"required"`
+	StartDate  string `json:"start_date" validate:"required"`
+	EndDate    string `json:"end_date"`
+}
+
+type UpdateRosterModel struct {
+	RosterID uint `json:"roster_id" validate:"required"`
+	CreateRosterModel
+	IsDeleted bool `json:"is_deleted"`
+}
+
+type CreateRosterExceptionModel struct {
+	RosterID   uint   `json:"roster_id" validate:"required"`
+	Date       string `json:"date" validate:"required"`
+	EmployeeID *uint  `json:"employee_id"`
+	Action     string `json:"action" validate:"required"`
+	ShiftID    *uint  `json:"shift_id"`
+	Note       string `json:"note"`
+}
+
+// Tên bảng trong CSDL
+func (Roster) TableName() string {
+	return "tbl_roster"
+}
+
+func (RosterException) TableName() string {
+	return "tbl_roster_exception"
+}
@@ -0,0 +1,290 @@
+package roster
+
+import (
+	"app/config"
+	employeeModel "app/modules/employee/model"
+	"app/modules/roster/model"
+	shiftModel "app/modules/shift/model"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+	"gorm.io/gorm"
+)
+
+// Số ngày tối đa của một lần sinh ca
+const MaxDays = 366
+
+var errRRule = errors.New("rrule must be a single RRULE without DTSTART, FREQ from YEARLY to DAILY")
+
+// Location múi giờ tính giờ ca (APP_TIME_ZONE)
+func Location() *time.Location {
+	location, err := time.LoadLocation(config.Config("APP_TIME_ZONE"))
+	if err != nil {
+		return time.Local
+	}
+
+	return location
+}
+
+// Rule dựng RRULE của roster: DTSTART là start, không lặp quá end (nếu có).
+// rule chỉ gồm phần RRULE (có hoặc không có tiền tố "RRULE:"), vd "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR"
+func Rule(rule string, start time.Time, end *time.Time) (*rrule.RRule, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if strings.ContainsAny(rule, "\r\n") || strings.Contains(rule, "DTSTART") {
+		return nil, errRRule
+	}
+
+	option, err := rrule.StrToROption(rule)
+	if err != nil {
+		return nil, err
+	}
+	// Mỗi lần lặp là một ngày làm ca
+	if option.Freq > rrule.DAILY {
+		return nil, errRRule
+	}
+
+	option.Dtstart = day(start)
+	if end != nil && (option.Until.IsZero() || option.Until.After(day(*end))) {
+		option.Until = day(*end)
+	}
+
+	return rrule.NewRRule(*option)
+}
+
+// Dates các ngày lặp của roster trong [from, to]
+func Dates(roster model.Roster, from, to time.Time) ([]time.Time, error) {
+	rule, err := Rule(roster.RRule, roster.StartDate, roster.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	return rule.Between(day(from), day(to), true), nil
+}
+
+// Occurs date có phải là một lần lặp của roster không
+func Occurs(roster model.Roster, date time.Time) bool {
+	dates, err := Dates(roster, date, date)
+	return err == nil && len(dates) > 0
+}
+
+// ShiftTime giờ bắt đầu / kết thúc của mẫu ca (đã preload Children) khi làm ca vào ngày date, theo Location
+func ShiftTime(shift *shiftModel.Shift, date time.Time) (time.Time, time.Time) {
+	firstStart, lastEnd := -1, 0
+	for _, child := range shift.Children {
+		start, end, err := child.Span()
+		if err != nil {
+			continue
+		}
+		if firstStart < 0 {
+			firstStart = start
+		} else if start < firstStart {
+			end += shiftModel.DayMinutes
+		}
+		if end > lastEnd {
+			lastEnd = end
+		}
+	}
+	if firstStart < 0 {
+		firstStart = 0
+	}
+
+	base := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, Location())
+	return base.Add(time.Duration(firstStart) * time.Minute), base.Add(time.Duration(lastEnd) * time.Minute)
+}
+
+// Expand sinh các ca cụ thể trong [from, to] (tối đa MaxDays ngày), đã trừ lần lặp bị skip và đổi mẫu ca khi swap.
+// employeeID > 0: chỉ ca của nhân viên đó (roster cá nhân và roster của team nhân viên đang thuộc).
+// teamID > 0: chỉ ca của các thành viên team vào ngày đó
+func Expand(db *gorm.DB, from, to time.Time, employeeID, teamID uint, lang string) ([]model.ShiftInstance, error) {
+	from, to = day(from), day(to)
+
+	query := db.Preload("Shift.Translations").Preload("Shift.Children", orderChild).Preload("Exceptions").
+		Where("start_date <= ? AND (end_date IS NULL OR end_date >= ?)", to, from)
+	if employeeID > 0 {
+		query = query.Where("(employee_id = ? OR team_id IN (?))", employeeID,
+			db.Model(&employeeModel.TeamMembership{}).Select("team_id").Where("employee_id = ?", employeeID).Scopes(validIn(from, to)))
+	}
+	if teamID > 0 {
+		query = query.Where("(team_id = ? OR employee_id IN (?))", teamID,
+			db.Model(&employeeModel.TeamMembership{}).Select("employee_id").Where("team_id = ?", teamID).Scopes(validIn(from, to)))
+	}
+
+	var rosters []model.Roster
+	if err := query.Order("roster_id").Find(&rosters).Error; err != nil {
+		return nil, err
+	}
+
+	// Thành viên của các team liên quan trong khoảng ngày
+	teamIDs := []uint{}
+	if teamID > 0 {
+		teamIDs = append(teamIDs, teamID)
+	}
+	for _, roster := range rosters {
+		if roster.TeamID != nil {
+			teamIDs = append(teamIDs, *roster.TeamID)
+		}
+	}
+	var memberships []employeeModel.TeamMembership
+	if len(teamIDs) > 0 {
+		if err := db.Where("team_id IN ?", teamIDs).Scopes(validIn(from, to)).Order("valid_from").Find(&memberships).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	// Mẫu ca thay thế của các ngoại lệ swap
+	swapShifts := map[uint]*shiftModel.Shift{}
+	swapIDs := []uint{}
+	for _, roster := range rosters {
+		for _, exception := range roster.Exceptions {
+			if exception.Action == model.ExceptionSwap && exception.ShiftID != nil {
+				swapIDs = append(swapIDs, *exception.ShiftID)
+			}
+		}
+	}
+	if len(swapIDs) > 0 {
+		var shifts []shiftModel.Shift
+		if err := db.Preload("Translations").Preload("Children", orderChild).Where("shift_id IN ?", swapIDs).Find(&shifts).Error; err != nil {
+			return nil, err
+		}
+		for i := range shifts {
+			swapShifts[shifts[i].ID] = &shifts[i]
+		}
+	}
+
+	instances := []model.ShiftInstance{}
+	for _, roster := range rosters {
+		// Mẫu ca đã bị xoá thì không sinh ca
+		if roster.Shift == nil {
+			continue
+		}
+		dates, err := Dates(roster, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, date := range dates {
+			employeeIDs := []uint{}
+			if roster.EmployeeID != nil {
+				employeeIDs = append(employeeIDs, *roster.EmployeeID)
+			} else {
+				employeeIDs = members(memberships, *roster.TeamID, date)
+			}
+
+			for _, id := range employeeIDs {
+				if employeeID > 0 && id != employeeID {
+					continue
+				}
+				if teamID > 0 && (roster.TeamID == nil || *roster.TeamID != teamID) && !isMember(memberships, teamID, id, date) {
+					continue
+				}
+
+				shift := roster.Shift
+				var exceptionID *uint
+				if exception := findException(roster.Exceptions, date, id); exception != nil {
+					if exception.Action == model.ExceptionSkip || exception.ShiftID == nil {
+						continue
+					}
+					swap, ok := swapShifts[*exception.ShiftID]
+					if !ok {
+						continue
+					}
+					shift, exceptionID = swap, &exception.ID
+				}
+
+				start, end := ShiftTime(shift, date)
+				instances = append(instances, model.ShiftInstance{
+					RosterID:    roster.ID,
+					Date:        date.Format("2006-01-02"),
+					EmployeeID:  id,
+					TeamID:      roster.TeamID,
+					ShiftID:     shift.ID,
+					ShiftName:   shift.LocalName(lang),
+					Start:       start,
+					End:         end,
+					WorkMinutes: shift.WorkMinutes(),
+					ExceptionID: exceptionID,
+				})
+			}
+		}
+	}
+
+	return instances, nil
+}
+
+// Employees các nhân viên được xếp ca bởi roster trong [from, to]: nhân viên của roster, hoặc các thành viên của team
+func Employees(db *gorm.DB, current model.Roster, from, to time.Time) ([]uint, error) {
+	if current.EmployeeID != nil {
+		return []uint{*current.EmployeeID}, nil
+	}
+
+	employeeIDs := []uint{}
+	err := db.Model(&employeeModel.TeamMembership{}).Distinct("employee_id").
+		Where("team_id = ?", current.TeamID).Scopes(validIn(day(from), day(to))).Pluck("employee_id", &employeeIDs).Error
+
+	return employeeIDs, err
+}
+
+// Ngoại lệ của lần lặp date cho nhân viên employeeID, ngoại lệ riêng của nhân viên được ưu tiên hơn ngoại lệ cả team
+func findException(exceptions []model.RosterException, date time.Time, employeeID uint) *model.RosterException {
+	var found *model.RosterException
+	for i, exception := range exceptions {
+		if !day(exception.Date).Equal(day(date)) {
+			continue
+		}
+		if exception.EmployeeID == nil {
+			if found == nil {
+				found = &exceptions[i]
+			}
+		} else if *exception.EmployeeID == employeeID {
+			return &exceptions[i]
+		}
+	}
+
+	return found
+}
+
+// Các nhân viên thuộc team vào ngày date
+func members(memberships []employeeModel.TeamMembership, teamID uint, date time.Time) []uint {
+	employeeIDs := []uint{}
+	for _, membership := range memberships {
+		if membership.TeamID == teamID && validOn(membership, date) {
+			employeeIDs = append(employeeIDs, membership.EmployeeID)
+		}
+	}
+
+	return employeeIDs
+}
+
+func isMember(memberships []employeeModel.TeamMembership, teamID, employeeID uint, date time.Time) bool {
+	for _, membership := range memberships {
+		if membership.TeamID == teamID && membership.EmployeeID == employeeID && validOn(membership, date) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validOn(membership employeeModel.TeamMembership, date time.Time) bool {
+	date = day(date)
+	return !day(membership.ValidFrom).After(date) && (membership.ValidTo == nil || !day(*membership.ValidTo).Before(date))
+}
+
+// Membership có hiệu lực trong [from, to]
+func validIn(from, to time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("valid_from <= ? AND (valid_to IS NULL OR valid_to >= ?)", to, from)
+	}
+}
+
+// Khung giờ theo thứ tự đã nhập
+func orderChild(db *gorm.DB) *gorm.DB {
+	return db.Order("shift_child_id")
+}
+
+// Ngày (00:00 UTC) của t, cùng mốc với cột date đọc từ CSDL
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
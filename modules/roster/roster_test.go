@@ -0,0 +1,212 @@
+package roster
+
+import (
+	"app/database/testdb"
+	"app/modules/roster/model"
+	shiftModel "app/modules/shift/model"
+	"testing"
+	"time"
+)
+
+func date(value string) time.Time {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+func datePtr(value string) *time.Time {
+	t := date(value)
+	return &t
+}
+
+func uintPtr(value uint) *uint {
+	return &value
+}
+
+func TestRule(t *testing.T) {
+	cases := []struct {
+		rule string
+		ok   bool
+	}{
+		{"FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", true},
+		{"RRULE:FREQ=DAILY;INTERVAL=8", true},
+		{"FREQ=MONTHLY;BYMONTHDAY=1", true},
+		{"FREQ=HOURLY", false},
+		{"DTSTART:20240101T000000Z\nRRULE:FREQ=DAILY", false},
+		{"FREQ=DAILY;DTSTART=20240101T000000Z", false},
+		{"FREQ=SOMETIMES", false},
+	}
+
+	for _, item := range cases {
+		if _, err := Rule(item.rule, date("2024-01-01"), nil); (err == nil) != item.ok {
+			t.Errorf("%q: got %v, want ok = %v", item.rule, err, item.ok)
+		}
+	}
+}
+
+func TestDates(t *testing.T) {
+	cases := []struct {
+		name     string
+		roster   model.Roster
+		from, to string
+		want     []string
+	}{
+		{"weekdays", model.Roster{RRule: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", StartDate: date("2024-01-03")}, "2024-01-01", "2024-01-09",
+			[]string{"2024-01-03", "2024-01-04", "2024-01-05", "2024-01-08", "2024-01-09"}},
+		{"every other saturday", model.Roster{RRule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA", StartDate: date("2024-01-01")}, "2024-01-01", "2024-02-29",
+			[]string{"2024-01-06", "2024-01-20", "2024-02-03", "2024-02-17"}},
+		{"end date before until", model.Roster{RRule: "FREQ=DAILY;UNTIL=20240110T000000Z", StartDate: date("2024-01-01"), EndDate: datePtr("2024-01-03")}, "2024-01-01", "2024-01-31",
+			[]string{"2024-01-01", "2024-01-02", "2024-01-03"}},
+		{"until before end date", model.Roster{RRule: "FREQ=DAILY;INTERVAL=3;UNTIL=20240110T000000Z", StartDate: date("2024-01-01"), EndDate: datePtr("2024-01-31")}, "2024-01-01", "2024-01-31",
+			[]string{"2024-01-01", "2024-01-04", "2024-01-07", "2024-01-10"}},
+		{"end date only", model.Roster{RRule: "FREQ=DAILY;INTERVAL=8", StartDate: date("2024-01-01"), EndDate: datePtr("2024-01-17")}, "2024-01-05", "2024-01-31",
+			[]string{"2024-01-09", "2024-01-17"}},
+		{"before start", model.Roster{RRule: "FREQ=DAILY", StartDate: date("2024-02-01")}, "2024-01-01", "2024-01-31", []string{}},
+	}
+
+	for _, item := range cases {
+		t.Run(item.name, func(t *testing.T) {
+			dates, err := Dates(item.roster, date(item.from), date(item.to))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, d := range dates {
+				got = append(got, d.Format("2006-01-02"))
+			}
+			if len(got) != len(item.want) {
+				t.Fatalf("got %v, want %v", got, item.want)
+			}
+			for i := range got {
+				if got[i] != item.want[i] {
+					t.Fatalf("got %v, want %v", got, item.want)
+				}
+			}
+		})
+	}
+
+	if !Occurs(model.Roster{RRule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA", StartDate: date("2024-01-01")}, date("2024-01-20")) ||
+		Occurs(model.Roster{RRule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=SA", StartDate: date("2024-01-01")}, date("2024-01-13")) {
+		t.Error("Occurs: want 2024-01-20 and not 2024-01-13")
+	}
+}
+
+func TestFindException(t *testing.T) {
+	exceptions := []model.RosterException{
+		{ID: 1, Date: date("2024-01-02"), EmployeeID: uintPtr(7), Action: model.ExceptionSwap, ShiftID: uintPtr(2)},
+		{ID: 2, Date: date("2024-01-02"), Action: model.ExceptionSkip},
+		{ID: 3, Date: date("2024-01-03"), EmployeeID: uintPtr(7), Action: model.ExceptionSkip},
+	}
+
+	cases := []struct {
+		name       string
+		date       string
+		employeeID uint
+		want       uint
+	}{
+		{"employee exception beats team exception", "2024-01-02", 7, 1},
+		{"team exception for other members", "2024-01-02", 8, 2},
+		{"employee exception only", "2024-01-03", 7, 3},
+		{"other employee has none", "2024-01-03", 8, 0},
+		{"no exception on date", "2024-01-04", 7, 0},
+	}
+
+	for _, item := range cases {
+		got := uint(0)
+		if found := findException(exceptions, date(item.date), item.employeeID); found != nil {
+			got = found.ID
+		}
+		if got != item.want {
+			t.Errorf("%s: got exception %d, want %d", item.name, got, item.want)
+		}
+	}
+}
+
+func TestShiftTime(t *testing.T) {
+	testdb.Chdir(t)
+	t.Setenv("APP_TIME_ZONE", "Asia/Ho_Chi_Minh")
+	location := Location()
+
+	cases := []struct {
+		name       string
+		children   []shiftModel.ShiftChild
+		start, end string
+	}{
+		{"day shift", []shiftModel.ShiftChild{{TimeStart: "08:00", TimeEnd: "17:00"}}, "2024-01-02 08:00", "2024-01-02 17:00"},
+		{"overnight", []shiftModel.ShiftChild{{TimeStart: "22:00", TimeEnd: "06:00"}}, "2024-01-02 22:00", "2024-01-03 06:00"},
+		{"split", []shiftModel.ShiftChild{{TimeStart: "08:00", TimeEnd: "12:00"}, {TimeStart: "13:00", TimeEnd: "17:00"}}, "2024-01-02 08:00", "2024-01-02 17:00"},
+		// Khung giờ sau bắt đầu trước khung đầu tiên là sang ngày hôm sau
+		{"second block after midnight", []shiftModel.ShiftChild{{TimeStart: "18:00", TimeEnd: "22:00"}, {TimeStart: "01:00", TimeEnd: "05:00"}}, "2024-01-02 18:00", "2024-01-03 05:00"},
+	}
+
+	for _, item := range cases {
+		start, end := ShiftTime(&shiftModel.Shift{Children: item.children}, date("2024-01-02"))
+		wantStart, _ := time.ParseInLocation("2006-01-02 15:04", item.start, location)
+		wantEnd, _ := time.ParseInLocation("2006-01-02 15:04", item.end, location)
+		if !start.Equal(wantStart) || !end.Equal(wantEnd) {
+			t.Errorf("%s: got %s – %s, want %s – %s", item.name, start, end, wantStart, wantEnd)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	db := testdb.Open(t, testdb.OrgModels, testdb.EmployeeModels, testdb.ShiftModels, &model.Roster{}, &model.RosterException{})
+
+	team := testdb.SeedOrg(t, db).Team
+	first := testdb.SeedEmployee(t, db, "1001", nil)
+	second := testdb.SeedEmployee(t, db, "1002", nil)
+	testdb.SeedMembership(t, db, first.ID, team.ID, date("2024-01-01"))
+	// Nhân viên thứ hai vào team từ 2024-01-03
+	testdb.SeedMembership(t, db, second.ID, team.ID, date("2024-01-03"))
+
+	morning := testdb.SeedShift(t, db, "Morning", "08:00-17:00")
+	night := testdb.SeedShift(t, db, "Night", "22:00-06:00")
+
+	roster := model.Roster{ShiftID: morning.ID, TeamID: &team.ID, RRule: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", StartDate: date("2024-01-01")}
+	db.Create(&roster)
+	db.Create(&[]model.RosterException{
+		{RosterID: roster.ID, Date: date("2024-01-02"), Action: model.ExceptionSkip},
+		{RosterID: roster.ID, Date: date("2024-01-04"), EmployeeID: &second.ID, Action: model.ExceptionSwap, ShiftID: &night.ID},
+	})
+
+	cases := []struct {
+		name       string
+		employeeID uint
+		teamID     uint
+		want       []string
+	}{
+		{"all", 0, 0, []string{"1001 2024-01-01 Morning", "1001 2024-01-03 Morning", "1002 2024-01-03 Morning", "1001 2024-01-04 Morning",
+			"1002 2024-01-04 Night", "1001 2024-01-05 Morning", "1002 2024-01-05 Morning"}},
+		{"employee", second.ID, 0, []string{"1002 2024-01-03 Morning", "1002 2024-01-04 Night", "1002 2024-01-05 Morning"}},
+		{"team", 0, team.ID, []string{"1001 2024-01-01 Morning", "1001 2024-01-03 Morning", "1002 2024-01-03 Morning", "1001 2024-01-04 Morning",
+			"1002 2024-01-04 Night", "1001 2024-01-05 Morning", "1002 2024-01-05 Morning"}},
+	}
+	codes := map[uint]string{first.ID: "1001", second.ID: "1002"}
+
+	for _, item := range cases {
+		t.Run(item.name, func(t *testing.T) {
+			instances, err := Expand(db, date("2024-01-01"), date("2024-01-07"), item.employeeID, item.teamID, "en")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{}
+			for _, instance := range instances {
+				got = append(got, codes[instance.EmployeeID]+" "+instance.Date+" "+instance.ShiftName)
+				if (instance.ShiftID == night.ID) != (instance.ExceptionID != nil) {
+					t.Errorf("%+v: exception id only on swapped shifts", instance)
+				}
+			}
+			if len(got) != len(item.want) {
+				t.Fatalf("got %v, want %v", got, item.want)
+			}
+			for i := range got {
+				if got[i] != item.want[i] {
+					t.Fatalf("got %v, want %v", got, item.want)
+				}
+			}
+		})
+	}
+}
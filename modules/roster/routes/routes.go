@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"app/middleware"
+
+	"app/modules/roster/controller"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func InitRosterRoutes(app *fiber.App) {
+	roster := app.Group("/roster", middleware.AppInfo, middleware.AppAuthen)
+
+	getList := roster.Group("")
+	getList.Get("/", middleware.Require("roster:read"), controller.GetRoster)
+	getList.Get("/instance", middleware.Require("roster:read"), controller.GetRosterInstance) // khai báo trước /:id
+	getList.Get("/:id", middleware.Require("roster:read"), controller.GetRosterByID)
+
+	roster.Post("/", middleware.Require("roster:write"), controller.CreateRoster)
+	roster.Put("/", middleware.Require("roster:write"), controller.UpdateRoster)
+	roster.Post("/exception", middleware.Require("roster:write"), controller.CreateRosterException)
+	roster.Delete("/exception/:id", middleware.Require("roster:write"), controller.DeleteRosterException)
+	roster.Delete("/:id", middleware.Require("roster:write"), controller.DeleteRoster)
+}
@@ -1,16 +1,34 @@
 package modules
 
 import (
+	attendanceRoute "app/modules/attendance/routes"
 	authenRoute "app/modules/authen/routes"
+	clientRoute "app/modules/client/routes"
 	departmentRoute "app/modules/department/routes"
+	employeeRoute "app/modules/employee/routes"
 	groupRoute "app/modules/group/routes"
+	laborRoute "app/modules/labor/routes"
+	orgRoute "app/modules/org/routes"
+	rosterRoute "app/modules/roster/routes"
+	shiftRoute "app/modules/shift/routes"
+	siteRoute "app/modules/site/routes"
 	teamRoute "app/modules/team/routes"
+	translationRoute "app/modules/translation/routes"
 	"github.com/gofiber/fiber/v2"
 )
 
 func InitRoutes(app *fiber.App) {
+	attendanceRoute.InitAttendanceRoutes(app)
 	authenRoute.InitAuthenRoutes(app)
+	clientRoute.InitClientRoutes(app)
 	departmentRoute.InitDepartmentRoutes(app)
+	employeeRoute.InitEmployeeRoutes(app)
 	groupRoute.InitGroupRoutes(app)
+	laborRoute.InitLaborRoutes(app)
+	orgRoute.InitOrgRoutes(app)
+	rosterRoute.InitRosterRoutes(app)
+	shiftRoute.InitShiftRoutes(app)
+	siteRoute.InitSiteRoutes(app)
 	teamRoute.InitTeamRoutes(app)
+	translationRoute.InitTranslationRoutes(app)
 }
@@ -3,117 +3,127 @@ package controller
 import (
 	"app/config"
 	"app/database"
+	"app/modules/labor"
+	"app/modules/roster"
+	rosterModel "app/modules/roster/model"
 	"app/modules/shift/model"
-	modell "app/modules/department/model"
 	"app/utils"
-	"errors"
 	"fmt"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
-	"os"
-	"time"
 )
 
-type tempCreateGroupModel model.CreateGroupModel
-type tempGroup model.Group
-type updateGr model.UpdateGroupModel
-
-
-// GetGroup Lấy danh sách tất cả group
-// @Summary Get all Groups
-// @Description Returns a list of all Groups
-// @Tags Group
+// GetShift Lấy danh sách tất cả mẫu ca
+// @Summary Get all Shift templates
+// @Description Returns a list of all Shift templates with their time blocks
+// @Tags Shift
 // @Accept json
 // @Produce json
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
 // @Success 200 {object} config.DataResponse
 // @Failure 500 {object} config.DataResponse
-// @Router /group [get]
+// @Router /shift [get]
 // @Security ApiKeyAuth
 // @Security ApiTokenAuth
-func GetGroup(c *fiber.Ctx) error {
+func GetShift(c *fiber.Ctx) error {
 	response := new(config.DataResponse)
-	var groups []model.Group
-	results := database.DB.Select("*").Preload("Department").Order("group_id").Find(&groups)
+	var shifts []model.Shift
+	results := database.DB.Preload("Translations").Preload("Children", orderChild).Order("shift_id").Find(&shifts)
 	if results.Error != nil {
 		response.Status = false
 		response.Message = config.GetMessageCode("GET_DATA_FAIL")
 		return c.JSON(response)
 	}
-	response.Data = groups
+	for i := range shifts {
+		shifts[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = shifts
 	response.Status = true
 	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
 	return c.JSON(response)
 }
 
-// GetAllGroup Lấy danh sách các group đã bị xoá
-// @Summary Get all Groups (deleted)
-// @Description Returns a list of all Groups (soft-deleted)
-// @Tags Group
+// GetAllShift Lấy danh sách các mẫu ca đã bị xoá
+// @Summary Get all Shift templates (deleted)
+// @Description Returns a list of all soft-deleted Shift templates
+// @Tags Shift
 // @Accept json
 // @Produce json
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
 // @Success 200 {object} config.DataResponse
 // @Failure 500 {object} config.DataResponse
-// @Router /group/all [get]
+// @Router /shift/all [get]
 // @Security ApiKeyAuth
 // @Security ApiTokenAuth
-func GetAllGroup(c *fiber.Ctx) error {
+func GetAllShift(c *fiber.Ctx) error {
 	response := new(config.DataResponse)
-	var groups []model.Group
-	results := database.DB.Select("*").Unscoped().Preload("Department").Where("deleted_at IS NOT NULL").Order("group_id").Find(&groups)
+	var shifts []model.Shift
+	results := database.DB.Unscoped().Preload("Translations").Preload("Children", orderChild).Where("deleted_at IS NOT NULL").Order("shift_id").Find(&shifts)
 	if results.Error != nil {
 		response.Status = false
 		response.Message = config.GetMessageCode("GET_DATA_FAIL")
 		return c.JSON(response)
 	}
-	response.Data = groups
+	for i := range shifts {
+		shifts[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = shifts
 	response.Status = true
 	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
 	return c.JSON(response)
 }
 
-// GetGroupByID returns information about a Group based on its ID
-// @Summary Get a Group by ID
-// @Description Returns information about a Group based on its ID
-// @Tags Group
+// GetShiftByID Lấy thông tin mẫu ca theo ID
+// @Summary Get a Shift template by ID
+// @Description Returns information about a Shift template based on its ID
+// @Tags Shift
 // @Accept json
 // @Produce json
-// @Param id path int true "ID of the Group"
+// @Param id path int true "ID of the Shift template"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
 // @Success 200 {object} config.DataResponse
 // @Failure 500 {object} config.DataResponse
-// @Router /group/{id} [get]
+// @Router /shift/{id} [get]
 // @Security ApiKeyAuth
 // @Security ApiTokenAuth
-func GetGroupByID(c *fiber.Ctx) error {
+func GetShiftByID(c *fiber.Ctx) error {
 	response := new(config.DataResponse)
-	var group model.Group
-	results := database.DB.Select("*").Preload("Department").Where("group_id = ?", c.Params("id")).First(&group)
+	var shift model.Shift
+	results := database.DB.Preload("Translations").Preload("Children", orderChild).Where("shift_id = ?", c.Params("id")).First(&shift)
 	if results.Error != nil {
 		response.Status = false
 		response.Message = config.GetMessageCode("GET_DATA_FAIL")
 		return c.JSON(response)
 	}
-	response.Data = group
+	shift.Localize(utils.Language(c), utils.AllTranslations(c))
+	response.Data = shift
 	response.Status = true
 	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
 	return c.JSON(response)
 }
 
-// CreateGroup Tạo mới 1 group
-// @Summary Create a new Group
-// @Description Creates a new Group
-// @Tags Group
+// CreateShift Tạo mới mẫu ca
+// @Summary Create Shift templates
+// @Description Creates Shift templates. A block whose time_end is not after time_start crosses midnight.
+// @Description The break (optional) must be inside its block, blocks must not overlap and must fit in 24 hours
+// @Tags Shift
 // @Accept json
 // @Produce json
-// @Param body body []model.CreateGroupModel true "New Group information"
+// @Param body body []model.CreateShiftModel true "New Shift templates"
 // @Success 200 {object} config.DataResponse
 // @Failure 500 {object} config.DataResponse
-// @Router /group [post]
+// @Router /shift [post]
 // @Security ApiKeyAuth
 // @Security ApiTokenAuth
-func CreateGroup(c *fiber.Ctx) error {
+func CreateShift(c *fiber.Ctx) error {
 	response := new(config.DataResponse)
 
-	var payload []*model.CreateGroupModel
+	var payload []*model.CreateShiftModel
 	if err := c.BodyParser(&payload); err != nil {
 		response.Status = false
 		response.Message = config.GetMessageCode("PARAM_ERROR")
@@ -121,160 +131,126 @@ func CreateGroup(c *fiber.Ctx) error {
 	}
 
 	tx := database.DB.Begin()
-	defer tx.Commit()
 
 	for _, item := range payload {
-		listCheck := []string{"GroupNameVN", "GroupNameEN", "GroupNameJP"}
-		vItem := map[string]string{
-			"GroupNameVN": item.GroupNameVN,
-			"GroupNameEN": item.GroupNameEN,
-			"GroupNameJP": item.GroupNameJP,
-		}
-		errors := utils.RequireCheck(listCheck, vItem, map[string]string{})
+		errors := checkShift(item)
 
 		if len(errors) > 0 {
+			tx.Rollback()
 			response.Status = false
 			response.Message = config.GetMessageCode("MISSING_FIELDS")
 			response.ValidateError = errors
 			return c.JSON(response)
 		}
 
-		newGroup := model.Group{
-			DepartmentID:  item.DepartmentID,
-			GroupNameVN:   item.GroupNameVN,
-			GroupNameEN:   item.GroupNameEN,
-			GroupNameJP:   item.GroupNameJP,
-			GroupShortcut: item.GroupShortcut,
+		newShift := model.Shift{
+			ShiftNameVN:   item.ShiftNameVN,
+			ShiftNameEN:   item.ShiftNameEN,
+			ShiftNameJP:   item.ShiftNameJP,
+			ShiftShortcut: item.ShiftShortcut,
+			Children:      newChildren(item.Children),
 			CreatedBy:     getUsername(c),
 		}
-		
-		if err := database.DB.Create(&newGroup).Error; err != nil {
+
+		if err := tx.Create(&newShift).Error; err != nil {
 			tx.Rollback()
 			response.Status = false
 			response.Message = config.GetMessageCode("CREATE_FAIL")
 			return c.JSON(response)
 		}
-		
-		tempNewGroup := tempGroup(newGroup)
-		if err := tempNewGroup.AfterCreate(tx); err != nil {
-			response.Status = false
-			response.Message = config.GetMessageCode("AFTER_CREATE_FAIL")
-			return c.JSON(response)
-		}
-		
-
 	}
 
+	tx.Commit()
+
 	response.Status = true
 	response.Message = config.GetMessageCode("CREATE_SUCCESS")
 	return c.JSON(response)
 }
 
-// UpdateGroup cập nhật thông tin một Group dựa trên ID
-// @Summary Cập nhật thông tin Group
-// @Description Cập nhật thông tin một Group dựa trên ID
-// @Tags Group
+// UpdateShift cập nhật mẫu ca
+// @Summary Update Shift templates
+// @Description Updates Shift templates based on their ID (the time blocks are replaced), or soft deletes them when is_deleted is set
+// @Description The rostered shifts of the template are checked against the labor rules (see /labor), violations are returned per Employee and date in validate_error
+// @Tags Shift
 // @Accept json
 // @Produce json
-// @Param body body []model.UpdateGroupModel true "Thông tin Group cần cập nhật"
+// @Param body body []model.UpdateShiftModel true "Shift template information to update"
 // @Success 200 {object} config.DataResponse
 // @Failure 500 {object} config.DataResponse
-// @Router /group [put]
+// @Router /shift [put]
 // @Security ApiKeyAuth
 // @Security ApiTokenAuth
-func UpdateGroup(c *fiber.Ctx) error {
+func UpdateShift(c *fiber.Ctx) error {
 	response := new(config.DataResponse)
-	db := database.DB
 
-	var payload []*model.UpdateGroupModel
+	var payload []*model.UpdateShiftModel
 	if err := c.BodyParser(&payload); err != nil {
 		response.Status = false
 		response.Message = config.GetMessageCode("PARAM_ERROR")
 		return c.JSON(response)
 	}
 
-	tx := db.Begin()
-	defer tx.Commit()
+	tx := database.DB.Begin()
 
 	for _, item := range payload {
-		listCheck := []string{"GroupNameVN", "GroupNameEN", "GroupNameJP"}
-		vItem := map[string]string{"GroupNameVN": item.GroupNameVN, "GroupNameEN": item.GroupNameEN, "GroupNameJP": item.GroupNameJP}
-		errors := utils.RequireCheck(listCheck, vItem, map[string]string{})
-
-		if len(errors) > 0 {
-			response.Message = "MISSING_FIELDS" //NOT_ID_EXISTS
-			response.ValidateError = errors
-			return c.JSON(response)
-		}
-
-		tempNGroup := updateGr(*item)
-		
-		if err := tempNGroup.BeforeUpdate(tx); err != nil {
+		var shift model.Shift
+		if err := tx.First(&shift, item.ShiftID).Error; err != nil {
+			tx.Rollback()
 			response.Status = false
-			response.Message = config.GetMessageCode("ERROR_BEFORE_UPDATE")
+			response.Message = config.GetMessageCode("NOT_ID_EXISTS")
 			return c.JSON(response)
 		}
 
-		var group model.Group
-		if item.GroupID != 0 {
-			if item.IsDeleted {
-				// Soft delete group
-				results := db.First(&group, item.GroupID)
-				if results.Error != nil {
-					tx.Rollback()
-					response.Status = false
-					response.Message = config.GetMessageCode("NOT_ID_EXISTS")
-					return c.JSON(response)
-				}
-				group.DeletedBy = getUsername(c)
-				group.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
-			} else {
-				// Update group
-				results := db.First(&group, item.GroupID)
-				if results.Error != nil {
-					tx.Rollback()
-					response.Status = false
-					response.Message = config.GetMessageCode("NOT_ID_EXISTS")
-					return c.JSON(response)
-				}
-
-				group.DepartmentID = item.DepartmentID
-				group.GroupNameVN = item.GroupNameVN
-				group.GroupNameEN = item.GroupNameEN
-				group.GroupNameJP = item.GroupNameJP
-				group.GroupShortcut = item.GroupShortcut
-
-
-				if err := db.Model(&group).Updates(group).Error; err != nil {
-					tx.Rollback()
-					response.Status = false
-					response.Message = config.GetMessageCode("SYSTEM_ERROR")
-					return c.JSON(response)
-				}
-			}
+		if item.IsDeleted {
+			shift.DeletedBy = getUsername(c)
+			shift.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		} else {
+			errors := checkShift(&item.CreateShiftModel)
 
-			if err := db.Save(&group).Error; err != nil {
+			if len(errors) > 0 {
 				tx.Rollback()
 				response.Status = false
-				response.Message = config.GetMessageCode("SYSTEM_ERROR")
+				response.Message = config.GetMessageCode("MISSING_FIELDS")
+				response.ValidateError = errors
 				return c.JSON(response)
 			}
-		} else {
-			// Create group
-			newGroup := model.Group{
-				GroupNameVN:   item.GroupNameVN,
-				GroupNameEN:   item.GroupNameEN,
-				GroupNameJP:   item.GroupNameJP,
-				GroupShortcut: item.GroupShortcut,
-				CreatedBy:     getUsername(c),
-			}
 
-			if err := db.Create(&newGroup).Error; err != nil {
+			shift.ShiftNameVN = item.ShiftNameVN
+			shift.ShiftNameEN = item.ShiftNameEN
+			shift.ShiftNameJP = item.ShiftNameJP
+			shift.ShiftShortcut = item.ShiftShortcut
+			shift.UpdatedBy = getUsername(c)
+			shift.LogVersion++
+
+			// Thay toàn bộ khung giờ
+			if err := tx.Where("shift_id = ?", shift.ID).Delete(&model.ShiftChild{}).Error; err != nil {
 				tx.Rollback()
 				response.Status = false
-				response.Message = config.GetMessageCode("CREATE_FAIL")
+				response.Message = config.GetMessageCode("SYSTEM_ERROR")
 				return c.JSON(response)
 			}
+			shift.Children = newChildren(item.Children)
+		}
+
+		if err := tx.Save(&shift).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			return c.JSON(response)
+		}
+
+		if item.IsDeleted {
+			continue
+		}
+		if errors, err := checkLabor(tx, shift.ID); err != nil || len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			if err == nil {
+				response.Message = config.GetMessageCode("MISSING_FIELDS")
+				response.ValidateError = errors
+			}
+			return c.JSON(response)
 		}
 	}
 
@@ -285,88 +261,70 @@ func UpdateGroup(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
-// DeleteGroup xóa một Group dựa trên ID
-// @Summary Xóa Group
-// @Description Xóa một Group dựa trên ID
-// @Tags Group
+// DeleteShift xóa một mẫu ca dựa trên ID
+// @Summary Delete Shift template
+// @Description Soft deletes a Shift template based on its ID
+// @Tags Shift
 // @Accept json
 // @Produce json
-// @Param id path int true "ID của Group"
+// @Param id path int true "ID of the Shift template"
 // @Success 200 {object} config.DataResponse
 // @Failure 500 {object} config.DataResponse
-// @Router /group/{id} [delete]
+// @Router /shift/{id} [delete]
 // @Security ApiKeyAuth
 // @Security ApiTokenAuth
-func DeleteGroup(c *fiber.Ctx) error {
+func DeleteShift(c *fiber.Ctx) error {
 	response := new(config.DataResponse)
 
-	// Get the ID from the request parameters
-	groupID := c.Params("id")
-
-	// Check if the group exists
-	var group model.Group
-	result := database.DB.First(&group, groupID)
-	if result.Error != nil {
+	var shift model.Shift
+	if err := database.DB.First(&shift, c.Params("id")).Error; err != nil {
 		response.Status = false
 		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
 		return c.JSON(response)
 	}
 
+	shift.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	shift.DeletedBy = getUsername(c)
 
-	results := database.DB.First(&group, "group_id = ?", groupID)
-	if results.Error != nil {
-		response.Status = false
-		response.Message = config.GetMessageCode("NOT_FOUND")
-		return c.JSON(response)
-	}
-
-	// Soft delete data: Update deleted_at field with the current time
-	group.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
-	group.DeletedBy = getUsername(c)
-
-	// Update data: update group by id from group table (database)
-	if err := database.DB.Model(&group).Updates(&group).Error; err != nil {
+	if err := database.DB.Model(&shift).Updates(&shift).Error; err != nil {
 		response.Status = false
 		response.Message = config.GetMessageCode("SYSTEM_ERROR")
 		return c.JSON(response)
 	}
 
-
 	response.Status = true
 	response.Message = config.GetMessageCode("DELETE_SUCCESS")
 	return c.JSON(response)
 }
 
-// RestoreGroup khôi phục một Group dựa trên ID
-// @Summary Khôi phục Group
-// @Description Khôi phục một Group dựa trên ID
-// @Tags Group
+// RestoreShift khôi phục một mẫu ca dựa trên ID
+// @Summary Restore Shift template
+// @Description Restores a soft-deleted Shift template based on its ID
+// @Tags Shift
 // @Accept json
 // @Produce json
-// @Param id path int true "ID của Group"
+// @Param id path int true "ID of the Shift template"
 // @Success 200 {object} config.DataResponse
 // @Failure 500 {object} config.DataResponse
-// @Router /group/{id}/restore [put]
+// @Router /shift/restore/{id} [put]
 // @Security ApiKeyAuth
 // @Security ApiTokenAuth
-func RestoreGroup(c *fiber.Ctx) error {
+func RestoreShift(c *fiber.Ctx) error {
 	response := new(config.DataResponse)
 
-	// Get the ID from the request parameters
-	groupID := c.Params("id")
-
-	// Check if the group exists
-	var group model.Group
-	result := database.DB.Unscoped().First(&group, groupID)
-	if result.Error != nil {
+	var shift model.Shift
+	if err := database.DB.Unscoped().Where("deleted_at IS NOT NULL").First(&shift, c.Params("id")).Error; err != nil {
 		response.Status = false
 		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
 		return c.JSON(response)
 	}
 
-
-	// Restore the group
-	if err := database.DB.Unscoped().Model(&group).Update("deleted_at", nil).Error; err != nil {
+	results := database.DB.Unscoped().Model(&shift).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"deleted_by": "",
+		"updated_by": getUsername(c),
+	})
+	if results.Error != nil {
 		response.Status = false
 		response.Message = config.GetMessageCode("RESTORE_FAIL")
 		return c.JSON(response)
@@ -377,74 +335,153 @@ func RestoreGroup(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
+func checkShift(item *model.CreateShiftModel) map[string]string {
+	vItem := map[string]string{
+		"ShiftNameVN":   item.ShiftNameVN,
+		"ShiftNameEN":   item.ShiftNameEN,
+		"ShiftNameJP":   item.ShiftNameJP,
+		"ShiftShortcut": item.ShiftShortcut,
+	}
+	errors := utils.RequireCheck([]string{"ShiftNameVN", "ShiftNameEN", "ShiftNameJP"}, vItem, map[string]string{})
+	errors = utils.MaxLengthCheck([]string{"ShiftNameVN:100", "ShiftNameEN:100", "ShiftNameJP:100", "ShiftShortcut:5"}, vItem, errors)
 
-
-// Hook được gọi sau khi thực hiện cập nhật (Update)
-func (g *updateGr) BeforeUpdate(tx *gorm.DB) (err error) {
-	if g.UpdatedBy == "1105" {
-		fmt.Println(">>>>  it's updated by Admin....")
+	if len(item.Children) == 0 {
+		errors["Children"] = config.GetMessageCode("REQUIRE")
+		return errors
 	}
 
-	var department modell.Department
-	results := database.DB.Select("*").Where("department_id = ?", g.DepartmentID).First(&department)
-	if results.Error != nil {
-		return results.Error
+	// Khung giờ sau tính theo mốc 00:00 ngày bắt đầu của khung giờ đầu tiên, cả ca không quá 24 giờ
+	firstStart, previousEnd := -1, -1
+	for i, child := range item.Children {
+		prefix := fmt.Sprintf("Children[%d].", i)
+		childErrors := checkShiftChild(child)
+		for key, message := range childErrors {
+			errors[prefix+key] = message
+		}
+		if len(childErrors) > 0 {
+			continue
+		}
+
+		start, end, _ := newChild(child).Span()
+		if firstStart < 0 {
+			firstStart = start
+		} else if start < firstStart {
+			start, end = start+model.DayMinutes, end+model.DayMinutes
+		}
+		if start < previousEnd || end-firstStart > model.DayMinutes {
+			errors[prefix+"TimeStart"] = config.GetMessageCode("SHIFT_OVERLAP")
+		}
+		previousEnd = end
 	}
 
-	if !tx.Statement.Changed("group_id", "group_name_vn", "group_name_en", "group_name_jp", "group_shortcut") {
-		WriteLog("DATA_NOT_CHANGED")
+	return errors
+}
+
+func checkShiftChild(child model.ShiftChildModel) map[string]string {
+	vItem := map[string]string{
+		"TimeStart":  child.TimeStart,
+		"TimeEnd":    child.TimeEnd,
+		"BreakStart": child.BreakStart,
+		"BreakEnd":   child.BreakEnd,
 	}
+	errors := utils.RequireCheck([]string{"TimeStart", "TimeEnd"}, vItem, map[string]string{})
 
-	if tx.Statement.Changed("LogVersion") {
-		return errors.New(config.Config("DATA_NOT_CHANGED"))
+	// Có giờ nghỉ thì phải đủ cả bắt đầu và kết thúc
+	listTime := []string{"TimeStart", "TimeEnd"}
+	if len(child.BreakStart) > 0 || len(child.BreakEnd) > 0 {
+		errors = utils.RequireCheck([]string{"BreakStart", "BreakEnd"}, vItem, errors)
+		listTime = append(listTime, "BreakStart", "BreakEnd")
+	}
+	for _, key := range listTime {
+		if _, ok := errors[key]; ok {
+			continue
+		}
+		if _, err := model.Clock(vItem[key]); err != nil {
+			errors[key] = config.GetMessageCode("FORMAT_TIME")
+		}
+	}
+	if child.OverTime < 0 {
+		errors["OverTime"] = config.GetMessageCode("VALUE_INVALID")
+	}
+	if len(errors) > 0 {
+		return errors
 	}
 
-	group := tx.Statement.Dest.(*model.Group)
-	group.LogVersion++
+	if child.TimeStart == child.TimeEnd {
+		errors["TimeEnd"] = config.GetMessageCode("VALUE_INVALID")
+		return errors
+	}
 
+	start, end, _ := newChild(child).Span()
+	breakStart, breakEnd, ok, _ := newChild(child).BreakSpan()
+	if ok && (breakStart >= breakEnd || breakStart < start || breakEnd > end) {
+		errors["BreakStart"] = config.GetMessageCode("BREAK_OUTSIDE_SHIFT")
+	}
 
-	return nil
+	return errors
 }
 
+// Kiểm tra luật lao động (labor.Check) cho các ca theo mẫu ca shiftID sau khi đổi khung giờ, đã lưu trong tx:
+// nhân viên của các roster dùng mẫu ca này hoặc có ngoại lệ swap sang mẫu ca này, trong labor.Window từ hôm nay
+func checkLabor(tx *gorm.DB, shiftID uint) (map[string]string, error) {
+	from, to, _ := labor.Window(time.Time{}, nil)
 
+	var rosters []rosterModel.Roster
+	err := tx.Where("start_date <= ? AND (end_date IS NULL OR end_date >= ?)", to, from).
+		Where("shift_id = ? OR roster_id IN (?)", shiftID,
+			tx.Model(&rosterModel.RosterException{}).Select("roster_id").Where("shift_id = ? AND date BETWEEN ? AND ?", shiftID, from, to)).
+		Find(&rosters).Error
+	if err != nil {
+		return nil, err
+	}
 
-func (g *tempGroup) AfterCreate(tx *gorm.DB) (err error) {
-    if g.CreatedBy == "1105" {
-        fmt.Println(">>>>  it's created by Admin....")
-		WriteLog(">>>>  it's created by Admin....")
-    }
-    return nil
-}
-
+	seen := map[uint]bool{}
+	employeeIDs := []uint{}
+	for _, current := range rosters {
+		ids, err := roster.Employees(tx, current, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				employeeIDs = append(employeeIDs, id)
+			}
+		}
+	}
 
-func WriteLog(logEntry string) error {
-	// Tạo một timestamp để thêm vào log
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-	logEntryWithTimestamp := fmt.Sprintf("[%s] %s\n", timestamp, logEntry)
+	return labor.Check(tx, labor.ByShift(shiftID), employeeIDs, from, to, map[string]string{})
+}
 
-	// Mở file log.txt trong chế độ append
-	file, err := os.OpenFile("assets/log/log.txt", os.O_WRONLY|os.O_APPEND, 0644)
-	if err != nil {
-		return err
+func newChild(item model.ShiftChildModel) model.ShiftChild {
+	return model.ShiftChild{
+		TimeStart:   item.TimeStart,
+		TimeEnd:     item.TimeEnd,
+		BreakStart:  item.BreakStart,
+		BreakEnd:    item.BreakEnd,
+		OverTime:    item.OverTime,
+		CheckStatus: item.CheckStatus,
 	}
-	defer file.Close()
+}
 
-	// Ghi log vào file
-	_, err = file.WriteString(logEntryWithTimestamp)
-	if err != nil {
-		return err
+func newChildren(items []model.ShiftChildModel) []model.ShiftChild {
+	children := make([]model.ShiftChild, len(items))
+	for i, item := range items {
+		children[i] = newChild(item)
 	}
 
-	return nil
+	return children
 }
 
-func getUsername(c *fiber.Ctx) string {
-	response := new(config.DataResponse)
+// Khung giờ theo thứ tự đã nhập
+func orderChild(db *gorm.DB) *gorm.DB {
+	return db.Order("shift_child_id")
+}
 
+func getUsername(c *fiber.Ctx) string {
 	tokenData, err := utils.ExtractTokenData(c)
 	if err != nil {
-		response.Status = false
-		response.Message = config.GetMessageCode("ERROR_GET_USERNAME")
+		return ""
 	}
 
 	return tokenData.Username
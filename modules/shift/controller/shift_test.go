@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"app/config"
+	"app/database/testdb"
+	"app/modules/shift/model"
+	"reflect"
+	"testing"
+)
+
+func TestCheckShift(t *testing.T) {
+	testdb.Chdir(t)
+
+	child := func(start, end, breakStart, breakEnd string) model.ShiftChildModel {
+		return model.ShiftChildModel{TimeStart: start, TimeEnd: end, BreakStart: breakStart, BreakEnd: breakEnd}
+	}
+	cases := []struct {
+		name     string
+		children []model.ShiftChildModel
+		want     map[string]string
+	}{
+		{"night shift with break", []model.ShiftChildModel{child("22:00", "06:00", "02:00", "03:00")}, map[string]string{}},
+		{"split shift", []model.ShiftChildModel{child("08:00", "12:00", "", ""), child("13:00", "17:00", "", "")}, map[string]string{}},
+		{"second block after midnight", []model.ShiftChildModel{child("22:00", "02:00", "", ""), child("03:00", "06:00", "", "")}, map[string]string{}},
+		{"no block", nil, map[string]string{"Children": "REQUIRE"}},
+		{"break outside the block", []model.ShiftChildModel{child("22:00", "06:00", "07:00", "08:00")}, map[string]string{"Children[0].BreakStart": "BREAK_OUTSIDE_SHIFT"}},
+		{"break past the end", []model.ShiftChildModel{child("08:00", "17:00", "16:30", "17:30")}, map[string]string{"Children[0].BreakStart": "BREAK_OUTSIDE_SHIFT"}},
+		{"break without end", []model.ShiftChildModel{child("08:00", "17:00", "12:00", "")}, map[string]string{"Children[0].BreakEnd": "REQUIRE"}},
+		{"start equals end", []model.ShiftChildModel{child("08:00", "08:00", "", "")}, map[string]string{"Children[0].TimeEnd": "VALUE_INVALID"}},
+		{"invalid time", []model.ShiftChildModel{child("24:30", "08:00", "", "")}, map[string]string{"Children[0].TimeStart": "FORMAT_TIME"}},
+		{"overlapping blocks", []model.ShiftChildModel{child("08:00", "12:00", "", ""), child("11:00", "17:00", "", "")}, map[string]string{"Children[1].TimeStart": "SHIFT_OVERLAP"}},
+		{"overlap across midnight", []model.ShiftChildModel{child("20:00", "23:00", "", ""), child("22:00", "02:00", "", "")}, map[string]string{"Children[1].TimeStart": "SHIFT_OVERLAP"}},
+		{"longer than a day", []model.ShiftChildModel{child("08:00", "20:00", "", ""), child("21:00", "09:00", "", "")}, map[string]string{"Children[1].TimeStart": "SHIFT_OVERLAP"}},
+	}
+
+	for _, item := range cases {
+		shift := &model.CreateShiftModel{ShiftNameVN: "Ca đêm", ShiftNameEN: "Night", ShiftNameJP: "夜勤", Children: item.children}
+		want := map[string]string{}
+		for key, code := range item.want {
+			want[key] = config.GetMessageCode(code)
+		}
+		if errors := checkShift(shift); !reflect.DeepEqual(errors, want) {
+			t.Errorf("%s: got %v, want %v", item.name, errors, want)
+		}
+	}
+}
@@ -1,14 +1,14 @@
-package groupMigrate
+package shiftMigrate
 
 import (
 	"app/database"
-	model "app/modules/group/model"
+	model "app/modules/shift/model"
 )
 
 func MigrateTbl() bool {
 	db := database.DB
 
-	db.AutoMigrate(&model.Group{})
+	db.AutoMigrate(&model.Shift{}, &model.ShiftChild{})
 
 	return true
 }
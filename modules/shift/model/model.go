@@ -1,7 +1,11 @@
 package model
 
 import (
+	"app/config"
+	"app/modules/translation"
+	translationModel "app/modules/translation/model"
 	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -12,26 +16,171 @@ type Model struct {
 	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
+// Số phút trong một ngày
+const DayMinutes = 24 * 60
 
+// Shift mẫu ca làm việc (vd: Morning 08:00–17:00, nghỉ 12:00–13:00), gồm một hoặc nhiều khung giờ ShiftChild
 type Shift struct {
 	Model
-	EmployeeID uint `gorm:"
+	ShiftNameVN   string                         `gorm:"column:shift_name_vn;size:100;not null"`
+	ShiftNameEN   string                         `gorm:"column:shift_name_en;size:100;not null"`
+	ShiftNameJP   string                         `gorm:"column:shift_name_jp;size:100;not null"`
+	Name          string                         `gorm:"-" json:"name"`            // tên theo ngôn ngữ của request, xem Localize
+	Names         map[string]string              `gorm:"-" json:"names,omitempty"` // mọi bản dịch của tên (ngôn ngữ → tên), chỉ trả về khi all
+	Translations  []translationModel.Translation `gorm:"polymorphic:Entity;polymorphicValue:shift;constraint:-" json:"-"`
+	ShiftShortcut string                         `gorm:"column:shift_shortcut;size:5"`
+	Children      []ShiftChild                   `gorm:"foreignKey:ShiftID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	LogVersion    int64                          `gorm:"column:log_version;default:0"`
+	CreatedBy     string                         `gorm:"column:created_by;size:15"`
+	UpdatedBy     string                         `gorm:"column:updated_by;size:15"`
+	DeletedBy     string                         `gorm:"column:deleted_by;size:15"`
 }
 
+// ShiftChild một khung giờ của ca, giờ dạng HH:MM.
+// TimeEnd <= TimeStart là ca qua nửa đêm (kết thúc ngày hôm sau). Giờ nghỉ (nếu có) phải nằm trong khung giờ.
+// OverTime số phút được tăng ca sau TimeEnd, CheckStatus bắt buộc chấm công vào / ra cho khung giờ này
 type ShiftChild struct {
-	timeStart time.Time  `gorm:"column:time_start;not null"`
-	timeEnd time.Time `gorm:"column:time_end;not null"`
-	breakStart time.Time `gorm:"column:break_start;not null"`
-	breakEnd time.Time `gorm:"column:break_end;not null"`
-	OverTime time.Time `gorm:"column:over_time;not null"`
-	checkStatus bool `gorm:"column:check_status;default:false"`
+	ID          uint   `gorm:"primarykey;column:shift_child_id;<-:create"`
+	ShiftID     uint   `gorm:"column:shift_id;not null;index"`
+	TimeStart   string `gorm:"column:time_start;size:5;not null"`
+	TimeEnd     string `gorm:"column:time_end;size:5;not null"`
+	BreakStart  string `gorm:"column:break_start;size:5"`
+	BreakEnd    string `gorm:"column:break_end;size:5"`
+	OverTime    int    `gorm:"column:over_time;not null;default:0"`
+	CheckStatus bool   `gorm:"column:check_status;default:false"`
+}
+
+// Localize điền Name theo ngôn ngữ lang (có fallback), all thì trả thêm Names
+func (s *Shift) Localize(lang string, all bool) {
+	names := s.names()
+	s.Name = config.LocalValue(lang, names)
+	if all {
+		s.Names = names
+	}
+}
+
+// LocalName tên theo ngôn ngữ lang
+func (s *Shift) LocalName(lang string) string {
+	return config.LocalValue(lang, s.names())
+}
+
+// Tên theo các cột vn / en / jp, ghi đè bằng Translations nếu đã preload
+func (s *Shift) names() map[string]string {
+	return translation.Values(s.Translations, "name", map[string]string{"vn": s.ShiftNameVN, "en": s.ShiftNameEN, "jp": s.ShiftNameJP})
+}
+
+// AfterSave đồng bộ các cột tên sang tbl_translation
+func (s *Shift) AfterSave(tx *gorm.DB) error {
+	return translation.Save(tx, "shift", s.ID, "name", map[string]string{"vn": s.ShiftNameVN, "en": s.ShiftNameEN, "jp": s.ShiftNameJP}, s.UpdatedBy)
+}
+
+// Clock giờ HH:MM → số phút từ 00:00
+func Clock(value string) (int, error) {
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+
+	return clock.Hour()*60 + clock.Minute(), nil
 }
 
+// Span phút bắt đầu / kết thúc của khung giờ tính từ 00:00 ngày bắt đầu ca, kết thúc > DayMinutes nếu qua nửa đêm
+func (c ShiftChild) Span() (int, int, error) {
+	start, err := Clock(c.TimeStart)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := Clock(c.TimeEnd)
+	if err != nil {
+		return 0, 0, err
+	}
+	if end <= start {
+		end += DayMinutes
+	}
 
+	return start, end, nil
+}
+
+// BreakSpan giờ nghỉ tính như Span (cùng mốc 00:00 ngày bắt đầu ca), ok = false nếu không có giờ nghỉ
+func (c ShiftChild) BreakSpan() (int, int, bool, error) {
+	if len(c.BreakStart) == 0 && len(c.BreakEnd) == 0 {
+		return 0, 0, false, nil
+	}
+
+	start, _, err := c.Span()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	breakStart, err := Clock(c.BreakStart)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	breakEnd, err := Clock(c.BreakEnd)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if breakStart < start {
+		breakStart += DayMinutes
+	}
+	if breakEnd <= breakStart {
+		breakEnd += DayMinutes
+	}
+
+	return breakStart, breakEnd, true, nil
+}
+
+// WorkMinutes số phút làm việc của khung giờ (trừ giờ nghỉ, không tính tăng ca)
+func (c ShiftChild) WorkMinutes() int {
+	start, end, err := c.Span()
+	if err != nil {
+		return 0
+	}
+	minutes := end - start
+	if breakStart, breakEnd, ok, err := c.BreakSpan(); err == nil && ok {
+		minutes -= breakEnd - breakStart
+	}
+
+	return minutes
+}
+
+// WorkMinutes tổng số phút làm việc của ca
+func (s *Shift) WorkMinutes() int {
+	minutes := 0
+	for _, child := range s.Children {
+		minutes += child.WorkMinutes()
+	}
+
+	return minutes
+}
+
+type ShiftChildModel struct {
+	TimeStart   string `json:"time_start" validate:"required"`
+	TimeEnd     string `json:"time_end" validate:"required"`
+	BreakStart  string `json:"break_start"`
+	BreakEnd    string `json:"break_end"`
+	OverTime    int    `json:"over_time"`
+	CheckStatus bool   `json:"check_status"`
+}
+
+type CreateShiftModel struct {
+	ShiftNameVN   string            `json:"shift_name_vn" validate:"required"`
+	ShiftNameEN   string            `json:"shift_name_en" validate:"required"`
+	ShiftNameJP   string            `json:"shift_name_jp" validate:"required"`
+	ShiftShortcut string            `json:"shift_shortcut"`
+	Children      []ShiftChildModel `json:"children" validate:"required"`
+}
+
+type UpdateShiftModel struct {
+	ShiftID uint `json:"shift_id" validate:"required"`
+	CreateShiftModel
+	IsDeleted bool `json:"is_deleted"`
+}
 
 // Tên bảng trong CSDL
 func (Shift) TableName() string {
 	return "tbl_shift"
 }
 
-
+func (ShiftChild) TableName() string {
+	return "tbl_shift_child"
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSpan(t *testing.T) {
+	cases := []struct {
+		child      ShiftChild
+		start, end int
+	}{
+		{ShiftChild{TimeStart: "08:00", TimeEnd: "17:00"}, 480, 1020},
+		{ShiftChild{TimeStart: "22:00", TimeEnd: "06:00"}, 1320, 1800},
+		{ShiftChild{TimeStart: "00:00", TimeEnd: "00:00"}, 0, DayMinutes},
+	}
+
+	for _, item := range cases {
+		start, end, err := item.child.Span()
+		if err != nil || start != item.start || end != item.end {
+			t.Errorf("%s–%s: got %d, %d, %v, want %d, %d", item.child.TimeStart, item.child.TimeEnd, start, end, err, item.start, item.end)
+		}
+	}
+
+	if _, _, err := (ShiftChild{TimeStart: "25:00", TimeEnd: "06:00"}).Span(); err == nil {
+		t.Error("25:00: want error")
+	}
+}
+
+func TestBreakSpan(t *testing.T) {
+	cases := []struct {
+		name       string
+		child      ShiftChild
+		start, end int
+		ok         bool
+	}{
+		{"no break", ShiftChild{TimeStart: "08:00", TimeEnd: "17:00"}, 0, 0, false},
+		{"day shift", ShiftChild{TimeStart: "08:00", TimeEnd: "17:00", BreakStart: "12:00", BreakEnd: "13:00"}, 720, 780, true},
+		{"after midnight", ShiftChild{TimeStart: "22:00", TimeEnd: "06:00", BreakStart: "02:00", BreakEnd: "03:00"}, 1560, 1620, true},
+		{"across midnight", ShiftChild{TimeStart: "22:00", TimeEnd: "06:00", BreakStart: "23:30", BreakEnd: "00:30"}, 1410, 1470, true},
+		// Trước giờ bắt đầu thì tính sang ngày hôm sau (checkShiftChild báo nằm ngoài khung giờ)
+		{"before start", ShiftChild{TimeStart: "08:00", TimeEnd: "17:00", BreakStart: "07:00", BreakEnd: "07:30"}, 1860, 1890, true},
+	}
+
+	for _, item := range cases {
+		start, end, ok, err := item.child.BreakSpan()
+		if err != nil || ok != item.ok || start != item.start || end != item.end {
+			t.Errorf("%s: got %d, %d, %v, %v, want %d, %d, %v", item.name, start, end, ok, err, item.start, item.end, item.ok)
+		}
+	}
+
+	if _, _, _, err := (ShiftChild{TimeStart: "08:00", TimeEnd: "17:00", BreakStart: "12:00", BreakEnd: "1300"}).BreakSpan(); err == nil {
+		t.Error("1300: want error")
+	}
+}
+
+func TestWorkMinutes(t *testing.T) {
+	night := ShiftChild{TimeStart: "22:00", TimeEnd: "06:00", BreakStart: "02:00", BreakEnd: "03:00", OverTime: 60}
+	if minutes := night.WorkMinutes(); minutes != 420 {
+		t.Errorf("22:00–06:00 with a 02:00–03:00 break: got %d, want 420", minutes)
+	}
+	if minutes := (ShiftChild{TimeStart: "bad", TimeEnd: "06:00"}).WorkMinutes(); minutes != 0 {
+		t.Errorf("invalid time: got %d, want 0", minutes)
+	}
+
+	// Ca chia hai khung giờ, không tính tăng ca
+	shift := Shift{Children: []ShiftChild{
+		{TimeStart: "08:00", TimeEnd: "12:00"},
+		night,
+	}}
+	if minutes := shift.WorkMinutes(); minutes != 660 {
+		t.Errorf("split shift: got %d, want 660", minutes)
+	}
+}
+
+func TestLocalizeJSON(t *testing.T) {
+	for _, all := range []bool{false, true} {
+		shift := Shift{ShiftNameVN: "Ca đêm", ShiftNameEN: "Night", ShiftNameJP: "夜勤"}
+		shift.Localize("en", all)
+		data, _ := json.Marshal(shift)
+
+		var got map[string]interface{}
+		json.Unmarshal(data, &got)
+		// Các cột tên cũ luôn có, names chỉ khi all
+		if got["name"] != "Night" || got["ShiftNameVN"] != "Ca đêm" || got["ShiftNameEN"] != "Night" || got["ShiftNameJP"] != "夜勤" {
+			t.Errorf("all = %v: got %s", all, data)
+		}
+		if _, ok := got["names"]; ok != all {
+			t.Errorf("all = %v: got %s", all, data)
+		}
+	}
+}
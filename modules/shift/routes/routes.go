@@ -3,23 +3,21 @@ package routes
 import (
 	"app/middleware"
 
-	"app/modules/group/controller"
+	"app/modules/shift/controller"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func InitGroupRoutes(app *fiber.App) {
-	group := app.Group("/group", middleware.AppInfo, middleware.AppAuthen)
+func InitShiftRoutes(app *fiber.App) {
+	shift := app.Group("/shift", middleware.AppInfo, middleware.AppAuthen)
 
-	getList := group.Group("")
-	getList.Get("/", controller.GetGroup)
-	getList.Get("/all",controller.GetAllGroup) //error nếu swap get :id trước
-	getList.Get("/:id", controller.GetGroupByID)
+	getList := shift.Group("")
+	getList.Get("/", middleware.Require("shift:read"), controller.GetShift)
+	getList.Get("/all", middleware.Require("shift:read"), controller.GetAllShift) // khai báo trước /:id
+	getList.Get("/:id", middleware.Require("shift:read"), controller.GetShiftByID)
 
-
-	
-	group.Post("/", controller.CreateGroup)
-	group.Put("/", controller.UpdateGroup)
-	group.Delete("/:id", controller.DeleteGroup)
-	group.Put("/restore/:id",controller.RestoreGroup)
+	shift.Post("/", middleware.Require("shift:write"), controller.CreateShift)
+	shift.Put("/", middleware.Require("shift:write"), controller.UpdateShift)
+	shift.Delete("/:id", middleware.Require("shift:delete"), controller.DeleteShift)
+	shift.Put("/restore/:id", middleware.Require("shift:restore"), controller.RestoreShift)
 }
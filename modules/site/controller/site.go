@@ -0,0 +1,326 @@
+package controller
+
+import (
+	"app/config"
+	"app/database"
+	departmentModel "app/modules/department/model"
+	"app/modules/site"
+	"app/modules/site/model"
+	teamModel "app/modules/team/model"
+	"app/utils"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// GetSite Lấy danh sách địa điểm chấm công
+// @Summary Get all Sites
+// @Description Returns a list of all attendance Sites with the Departments and Teams they are assigned to
+// @Tags Site
+// @Accept json
+// @Produce json
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /site [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetSite(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var sites []model.Site
+	results := database.DB.Preload("Assignments.Department.Translations").Preload("Assignments.Team.Translations").Order("site_id").Find(&sites)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	for i := range sites {
+		sites[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = sites
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetSiteByID Lấy thông tin địa điểm theo ID
+// @Summary Get a Site by ID
+// @Description Returns information about a Site based on its ID
+// @Tags Site
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Site"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /site/{id} [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetSiteByID(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var item model.Site
+	results := database.DB.Preload("Assignments.Department.Translations").Preload("Assignments.Team.Translations").Where("site_id = ?", c.Params("id")).First(&item)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	item.Localize(utils.Language(c), utils.AllTranslations(c))
+	response.Data = item
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// CreateSite Tạo mới địa điểm
+// @Summary Create new Sites
+// @Description Creates Sites with a center (latitude, longitude) and radius in meters, or a polygon of at least 3 points.
+// @Description policy reject refuses a check-in outside the Site, review stores it for review. Default reject
+// @Tags Site
+// @Accept json
+// @Produce json
+// @Param body body []model.CreateSiteModel true "New Site information"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /site [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func CreateSite(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.CreateSiteModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		errors := checkSite(tx, item)
+
+		if len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("MISSING_FIELDS")
+			response.ValidateError = errors
+			return c.JSON(response)
+		}
+
+		newSite := model.Site{CreatedBy: getUsername(c)}
+		setSite(&newSite, item)
+
+		if err := tx.Create(&newSite).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("CREATE_FAIL")
+			return c.JSON(response)
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("CREATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// UpdateSite cập nhật địa điểm
+// @Summary Update Sites
+// @Description Updates Sites based on their ID (the assignments are replaced), or soft deletes them when is_deleted is set
+// @Tags Site
+// @Accept json
+// @Produce json
+// @Param body body []model.UpdateSiteModel true "Site information to update"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /site [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func UpdateSite(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.UpdateSiteModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		var current model.Site
+		if err := tx.First(&current, item.SiteID).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+			return c.JSON(response)
+		}
+
+		if item.IsDeleted {
+			current.DeletedBy = getUsername(c)
+			current.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		} else {
+			errors := checkSite(tx, &item.CreateSiteModel)
+
+			if len(errors) > 0 {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("MISSING_FIELDS")
+				response.ValidateError = errors
+				return c.JSON(response)
+			}
+
+			// Thay toàn bộ department / team được gán
+			if err := tx.Where("site_id = ?", current.ID).Delete(&model.SiteAssignment{}).Error; err != nil {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("SYSTEM_ERROR")
+				return c.JSON(response)
+			}
+			setSite(&current, &item.CreateSiteModel)
+			current.UpdatedBy = getUsername(c)
+			current.LogVersion++
+		}
+
+		if err := tx.Save(&current).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			return c.JSON(response)
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// DeleteSite xóa một địa điểm dựa trên ID
+// @Summary Delete Site
+// @Description Soft deletes a Site based on its ID, check-ins are no longer checked against it
+// @Tags Site
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Site"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /site/{id} [delete]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func DeleteSite(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var current model.Site
+	if err := database.DB.First(&current, c.Params("id")).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	current.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	current.DeletedBy = getUsername(c)
+
+	if err := database.DB.Model(&current).Updates(&current).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	response.Status = true
+	response.Message = config.GetMessageCode("DELETE_SUCCESS")
+	return c.JSON(response)
+}
+
+func checkSite(tx *gorm.DB, item *model.CreateSiteModel) map[string]string {
+	vItem := map[string]string{
+		"SiteName": item.SiteName,
+		"Address":  item.Address,
+	}
+	errors := utils.RequireCheck([]string{"SiteName"}, vItem, map[string]string{})
+	errors = utils.MaxLengthCheck([]string{"SiteName:100", "Address:255"}, vItem, errors)
+
+	switch item.Policy {
+	case "", model.PolicyReject, model.PolicyReview:
+	default:
+		errors["Policy"] = config.GetMessageCode("VALUE_INVALID")
+	}
+
+	// Đa giác ít nhất 3 điểm, nếu không thì phải có tâm và bán kính
+	if len(item.Polygon) > 0 {
+		if len(item.Polygon) < 3 {
+			errors["Polygon"] = config.GetMessageCode("VALUE_INVALID")
+		}
+		for i, point := range item.Polygon {
+			if !site.Valid(point) {
+				errors[fmt.Sprintf("Polygon[%d]", i)] = config.GetMessageCode("VALUE_INVALID")
+			}
+		}
+	} else {
+		if item.Latitude == nil || item.Longitude == nil {
+			errors["Latitude"] = config.GetMessageCode("REQUIRE")
+		} else if !site.Valid(model.Point{Lat: *item.Latitude, Lng: *item.Longitude}) {
+			errors["Latitude"] = config.GetMessageCode("VALUE_INVALID")
+		}
+		if item.Radius <= 0 {
+			errors["Radius"] = config.GetMessageCode("VALUE_INVALID")
+		}
+	}
+
+	// Mỗi lần gán là đúng một department hoặc một team
+	for i, assignment := range item.Assignments {
+		prefix := fmt.Sprintf("Assignments[%d].", i)
+		switch {
+		case assignment.DepartmentID != nil && assignment.TeamID != nil:
+			errors[prefix+"TeamID"] = config.GetMessageCode("VALUE_INVALID")
+		case assignment.DepartmentID != nil:
+			if err := tx.First(&departmentModel.Department{}, *assignment.DepartmentID).Error; err != nil {
+				errors[prefix+"DepartmentID"] = config.GetMessageCode("NOT_ID_EXISTS")
+			}
+		case assignment.TeamID != nil:
+			if err := tx.First(&teamModel.Team{}, *assignment.TeamID).Error; err != nil {
+				errors[prefix+"TeamID"] = config.GetMessageCode("NOT_ID_EXISTS")
+			}
+		default:
+			errors[prefix+"DepartmentID"] = config.GetMessageCode("REQUIRE")
+		}
+	}
+
+	return errors
+}
+
+func setSite(current *model.Site, item *model.CreateSiteModel) {
+	current.SiteName = item.SiteName
+	current.Address = item.Address
+	current.Policy = item.Policy
+	if len(current.Policy) == 0 {
+		current.Policy = model.PolicyReject
+	}
+
+	current.Latitude, current.Longitude, current.Radius, current.Polygon = item.Latitude, item.Longitude, item.Radius, nil
+	if len(item.Polygon) > 0 {
+		current.Latitude, current.Longitude, current.Radius, current.Polygon = nil, nil, 0, item.Polygon
+	}
+
+	current.Assignments = make([]model.SiteAssignment, len(item.Assignments))
+	for i, assignment := range item.Assignments {
+		current.Assignments[i] = model.SiteAssignment{DepartmentID: assignment.DepartmentID, TeamID: assignment.TeamID}
+	}
+}
+
+func getUsername(c *fiber.Ctx) string {
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		return ""
+	}
+
+	return tokenData.Username
+}
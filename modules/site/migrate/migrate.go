@@ -0,0 +1,14 @@
+package siteMigrate
+
+import (
+	"app/database"
+	model "app/modules/site/model"
+)
+
+func MigrateTbl() bool {
+	db := database.DB
+
+	db.AutoMigrate(&model.Site{}, &model.SiteAssignment{})
+
+	return true
+}
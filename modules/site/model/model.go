@@ -0,0 +1,100 @@
+package model
+
+import (
+	"time"
+
+	departmentModel "app/modules/department/model"
+	teamModel "app/modules/team/model"
+
+	"gorm.io/gorm"
+)
+
+// Xử lý khi chấm công ngoài địa điểm
+const (
+	PolicyReject = "reject" // không nhận chấm công
+	PolicyReview = "review" // vẫn nhận, đánh dấu chờ duyệt
+)
+
+type Model struct {
+	ID        uint `gorm:"primarykey;column:site_id;<-:create"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// Point một điểm WGS84 (độ)
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// Site văn phòng / địa điểm được chấm công: hình tròn (Center + Radius mét) hoặc đa giác Polygon.
+// Nhân viên chấm công ở các địa điểm gán cho department hoặc team của mình
+type Site struct {
+	Model
+	SiteName    string           `gorm:"column:site_name;size:100;not null"`
+	Address     string           `gorm:"column:address;size:255"`
+	Latitude    *float64         `gorm:"column:latitude"`
+	Longitude   *float64         `gorm:"column:longitude"`
+	Radius      float64          `gorm:"column:radius;not null;default:0"`
+	Polygon     []Point          `gorm:"column:polygon;type:jsonb;serializer:json"`
+	Policy      string           `gorm:"column:policy;size:10;not null;default:reject"`
+	Assignments []SiteAssignment `gorm:"foreignKey:SiteID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	LogVersion  int64            `gorm:"column:log_version;default:0"`
+	CreatedBy   string           `gorm:"column:created_by;size:15"`
+	UpdatedBy   string           `gorm:"column:updated_by;size:15"`
+	DeletedBy   string           `gorm:"column:deleted_by;size:15"`
+}
+
+// SiteAssignment gán địa điểm cho một department hoặc một team
+type SiteAssignment struct {
+	ID           uint                        `gorm:"primarykey;column:site_assignment_id;<-:create" json:"site_assignment_id"`
+	SiteID       uint                        `gorm:"column:site_id;not null;index" json:"site_id"`
+	DepartmentID *uint                       `gorm:"column:department_id;index" json:"department_id"`
+	Department   *departmentModel.Department `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"department,omitempty"`
+	TeamID       *uint                       `gorm:"column:team_id;index" json:"team_id"`
+	Team         *teamModel.Team             `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"team,omitempty"`
+}
+
+// Localize điền Name của department / team đã preload
+func (s *Site) Localize(lang string, all bool) {
+	for i := range s.Assignments {
+		if s.Assignments[i].Department != nil {
+			s.Assignments[i].Department.Localize(lang, all)
+		}
+		if s.Assignments[i].Team != nil {
+			s.Assignments[i].Team.Localize(lang, all)
+		}
+	}
+}
+
+type SiteAssignmentModel struct {
+	DepartmentID *uint `json:"department_id"`
+	TeamID       *uint `json:"team_id"`
+}
+
+type CreateSiteModel struct {
+	SiteName    string                `json:"site_name" validate:"required"`
+	Address     string                `json:"address"`
+	Latitude    *float64              `json:"latitude"`
+	Longitude   *float64              `json:"longitude"`
+	Radius      float64               `json:"radius"`
+	Polygon     []Point               `json:"polygon"`
+	Policy      string                `json:"policy"`
+	Assignments []SiteAssignmentModel `json:"assignments"`
+}
+
+type UpdateSiteModel struct {
+	SiteID uint `json:"site_id" validate:"required"`
+	CreateSiteModel
+	IsDeleted bool `json:"is_deleted"`
+}
+
+// Tên bảng trong CSDL
+func (Site) TableName() string {
+	return "tbl_site"
+}
+
+func (SiteAssignment) TableName() string {
+	return "tbl_site_assignment"
+}
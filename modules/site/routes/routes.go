@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"app/middleware"
+
+	"app/modules/site/controller"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func InitSiteRoutes(app *fiber.App) {
+	site := app.Group("/site", middleware.AppInfo, middleware.AppAuthen)
+
+	getList := site.Group("")
+	getList.Get("/", middleware.Require("site:read"), controller.GetSite)
+	getList.Get("/:id", middleware.Require("site:read"), controller.GetSiteByID)
+
+	site.Post("/", middleware.Require("site:write"), controller.CreateSite)
+	site.Put("/", middleware.Require("site:write"), controller.UpdateSite)
+	site.Delete("/:id", middleware.Require("site:write"), controller.DeleteSite)
+}
@@ -0,0 +1,240 @@
+package site
+
+import (
+	"app/modules/site/model"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Kết quả kiểm tra vị trí chấm công
+const (
+	StatusInside    = "inside"    // trong một địa điểm được gán
+	StatusUnchecked = "unchecked" // nhân viên chưa được gán địa điểm nào, không kiểm tra
+	StatusReview    = "review"    // ngoài địa điểm, policy review
+	StatusReject    = "reject"    // ngoài địa điểm, policy reject
+)
+
+// Ellipsoid WGS84
+const (
+	wgs84A = 6378137.0
+	wgs84F = 1 / 298.257223563
+	wgs84B = wgs84A * (1 - wgs84F)
+
+	earthRadius = 6371008.8 // bán kính trung bình, dùng cho haversine
+)
+
+var errCoordinates = errors.New("coordinates must be \"latitude,longitude\" in degrees")
+
+// Result vị trí chấm công so với các địa điểm của nhân viên. Distance là số mét tới địa điểm gần nhất (0 nếu ở trong)
+type Result struct {
+	Status   string  `json:"status"`
+	SiteID   *uint   `json:"site_id"`
+	Distance float64 `json:"distance"`
+}
+
+// ParseCoordinates chuỗi "lat,lng" (độ, vd: "10.7769,106.7009") → Point
+func ParseCoordinates(value string) (model.Point, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 2 {
+		return model.Point{}, errCoordinates
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return model.Point{}, errCoordinates
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return model.Point{}, errCoordinates
+	}
+
+	point := model.Point{Lat: lat, Lng: lng}
+	if !Valid(point) {
+		return model.Point{}, errCoordinates
+	}
+
+	return point, nil
+}
+
+// Valid điểm nằm trong khoảng vĩ độ / kinh độ hợp lệ
+func Valid(point model.Point) bool {
+	return point.Lat >= -90 && point.Lat <= 90 && point.Lng >= -180 && point.Lng <= 180
+}
+
+// Distance khoảng cách trắc địa (mét) giữa hai điểm trên ellipsoid WGS84 theo công thức Vincenty,
+// không hội tụ (hai điểm gần như đối tâm) thì dùng haversine
+func Distance(from, to model.Point) float64 {
+	l := radian(to.Lng - from.Lng)
+	u1 := math.Atan((1 - wgs84F) * math.Tan(radian(from.Lat)))
+	u2 := math.Atan((1 - wgs84F) * math.Tan(radian(to.Lat)))
+	sinU1, cosU1 := math.Sincos(u1)
+	sinU2, cosU2 := math.Sincos(u2)
+
+	lambda := l
+	for i := 0; i < 200; i++ {
+		sinLambda, cosLambda := math.Sincos(lambda)
+		sinSigma := math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			return 0
+		}
+		cosSigma := sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma := math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cos2Alpha := 1 - sinAlpha*sinAlpha
+		cos2SigmaM := 0.0
+		if cos2Alpha != 0 {
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cos2Alpha
+		}
+		c := wgs84F / 16 * cos2Alpha * (4 + wgs84F*(4-3*cos2Alpha))
+
+		previous := lambda
+		lambda = l + (1-c)*wgs84F*sinAlpha*(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-previous) < 1e-12 {
+			u := cos2Alpha * (wgs84A*wgs84A - wgs84B*wgs84B) / (wgs84B * wgs84B)
+			a := 1 + u/16384*(4096+u*(-768+u*(320-175*u)))
+			b := u / 1024 * (256 + u*(-128+u*(74-47*u)))
+			deltaSigma := b * sinSigma * (cos2SigmaM + b/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+				b/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+			return wgs84B * a * (sigma - deltaSigma)
+		}
+	}
+
+	return haversine(from, to)
+}
+
+// DistanceTo số mét từ point tới địa điểm, 0 nếu ở trong. ok = false nếu địa điểm chưa có tâm / đa giác
+func DistanceTo(item model.Site, point model.Point) (float64, bool) {
+	if len(item.Polygon) >= 3 {
+		if contains(item.Polygon, point) {
+			return 0, true
+		}
+
+		nearest := math.Inf(1)
+		for i := range item.Polygon {
+			next := item.Polygon[(i+1)%len(item.Polygon)]
+			nearest = math.Min(nearest, Distance(point, closest(item.Polygon[i], next, point)))
+		}
+		return nearest, true
+	}
+
+	if item.Latitude == nil || item.Longitude == nil {
+		return 0, false
+	}
+
+	return math.Max(0, Distance(model.Point{Lat: *item.Latitude, Lng: *item.Longitude}, point)-item.Radius), true
+}
+
+// Locate kiểm tra vị trí coordinates của nhân viên với các địa điểm gán cho team hoặc department của nhân viên.
+// Ngoài mọi địa điểm thì theo policy của địa điểm gần nhất, coordinates không đọc được thì reject nếu có địa điểm policy reject
+func Locate(db *gorm.DB, employeeID uint, coordinates string) (Result, error) {
+	sites, err := Sites(db, employeeID)
+	if err != nil {
+		return Result{}, err
+	}
+	if len(sites) == 0 {
+		return Result{Status: StatusUnchecked}, nil
+	}
+
+	point, err := ParseCoordinates(coordinates)
+	if err != nil {
+		result := Result{Status: StatusReview}
+		for _, item := range sites {
+			if item.Policy == model.PolicyReject {
+				result.Status = StatusReject
+			}
+		}
+		return result, nil
+	}
+
+	var nearest *model.Site
+	result := Result{Distance: math.Inf(1)}
+	for i, item := range sites {
+		distance, ok := DistanceTo(item, point)
+		if !ok || distance >= result.Distance {
+			continue
+		}
+		nearest, result.Distance, result.SiteID = &sites[i], distance, &sites[i].ID
+	}
+
+	switch {
+	case nearest == nil:
+		result = Result{Status: StatusUnchecked}
+	case result.Distance == 0:
+		result.Status = StatusInside
+	case nearest.Policy == model.PolicyReview:
+		result.Status = StatusReview
+	default:
+		result.Status = StatusReject
+	}
+
+	return result, nil
+}
+
+// Sites các địa điểm gán cho team hiện tại hoặc department (qua group của team) của nhân viên
+func Sites(db *gorm.DB, employeeID uint) ([]model.Site, error) {
+	var unit struct {
+		TeamID       *uint
+		DepartmentID *uint
+	}
+	err := db.Table("tbl_employee e").
+		Select("e.team_id, g.department_id").
+		Joins("LEFT JOIN tbl_team t ON t.team_id = e.team_id").
+		Joins("LEFT JOIN tbl_group g ON g.group_id = t.group_id").
+		Where("e.employee_id = ?", employeeID).
+		Scan(&unit).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var sites []model.Site
+	err = db.Where("site_id IN (?)", db.Model(&model.SiteAssignment{}).Select("site_id").
+		Where("team_id = ? OR department_id = ?", unit.TeamID, unit.DepartmentID)).
+		Order("site_id").Find(&sites).Error
+
+	return sites, err
+}
+
+// Điểm trong đa giác (ray casting, kinh độ là trục x), đủ chính xác với đa giác nhỏ cỡ toà nhà / khu văn phòng
+func contains(polygon []model.Point, point model.Point) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Lat > point.Lat) != (b.Lat > point.Lat) &&
+			point.Lng < (b.Lng-a.Lng)*(point.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// Điểm gần point nhất trên cạnh [a, b], tính trên mặt phẳng chiếu cục bộ quanh point
+func closest(a, b, point model.Point) model.Point {
+	scale := math.Cos(radian(point.Lat))
+	ax, ay := (a.Lng-point.Lng)*scale, a.Lat-point.Lat
+	bx, by := (b.Lng-point.Lng)*scale, b.Lat-point.Lat
+
+	dx, dy := bx-ax, by-ay
+	t := 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/length))
+	}
+
+	return model.Point{Lat: a.Lat + t*(b.Lat-a.Lat), Lng: a.Lng + t*(b.Lng-a.Lng)}
+}
+
+func haversine(from, to model.Point) float64 {
+	dLat := radian(to.Lat - from.Lat)
+	dLng := radian(to.Lng - from.Lng)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(radian(from.Lat))*math.Cos(radian(to.Lat))*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func radian(degree float64) float64 {
+	return degree * math.Pi / 180
+}
@@ -0,0 +1,199 @@
+package site
+
+import (
+	"app/database/testdb"
+	departmentModel "app/modules/department/model"
+	employeeModel "app/modules/employee/model"
+	"app/modules/site/model"
+	teamModel "app/modules/team/model"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func float(value float64) *float64 {
+	return &value
+}
+
+func near(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance
+}
+
+// Khu văn phòng hình vuông cạnh 0.001 độ
+var square = []model.Point{{Lat: 10, Lng: 106}, {Lat: 10, Lng: 106.001}, {Lat: 10.001, Lng: 106.001}, {Lat: 10.001, Lng: 106}}
+
+func TestDistance(t *testing.T) {
+	cases := []struct {
+		name      string
+		from, to  model.Point
+		want      float64
+		tolerance float64
+	}{
+		// Ví dụ của Vincenty (1975): Flinders Peak → Buninyong
+		{"flinders peak to buninyong", model.Point{Lat: -37.95103341666667, Lng: 144.42486788888888}, model.Point{Lat: -37.65282113888889, Lng: 143.92649552777777}, 54972.271, 0.001},
+		{"one degree on the equator", model.Point{Lat: 0, Lng: 0}, model.Point{Lat: 0, Lng: 1}, 111319.491, 0.001},
+		{"same point", model.Point{Lat: 10.7769, Lng: 106.7009}, model.Point{Lat: 10.7769, Lng: 106.7009}, 0, 0},
+		// Gần như đối tâm: Vincenty không hội tụ, dùng haversine
+		{"nearly antipodal", model.Point{Lat: 0, Lng: 0}, model.Point{Lat: 0.5, Lng: 179.7}, 19936000, 50000},
+	}
+
+	for _, item := range cases {
+		got := Distance(item.from, item.to)
+		if math.IsNaN(got) || !near(got, item.want, item.tolerance) {
+			t.Errorf("%s: got %.3f, want %.3f", item.name, got, item.want)
+		}
+		if back := Distance(item.to, item.from); !near(back, got, 0.001) {
+			t.Errorf("%s: got %.3f back, %.3f forth", item.name, back, got)
+		}
+	}
+}
+
+func TestContains(t *testing.T) {
+	// Hình chữ L: bỏ góc trên bên phải của hình vuông cạnh 0.002 độ
+	shape := []model.Point{{Lat: 10, Lng: 106}, {Lat: 10, Lng: 106.002}, {Lat: 10.001, Lng: 106.002}, {Lat: 10.001, Lng: 106.001},
+		{Lat: 10.002, Lng: 106.001}, {Lat: 10.002, Lng: 106}}
+
+	cases := []struct {
+		name    string
+		polygon []model.Point
+		point   model.Point
+		want    bool
+	}{
+		{"inside", square, model.Point{Lat: 10.0005, Lng: 106.0005}, true},
+		{"outside", square, model.Point{Lat: 10.002, Lng: 106.0005}, false},
+		{"level with an edge but outside", square, model.Point{Lat: 10.0005, Lng: 106.002}, false},
+		{"inside the l", shape, model.Point{Lat: 10.0015, Lng: 106.0005}, true},
+		{"in the notch of the l", shape, model.Point{Lat: 10.0015, Lng: 106.0015}, false},
+	}
+
+	for _, item := range cases {
+		if got := contains(item.polygon, item.point); got != item.want {
+			t.Errorf("%s: got %v, want %v", item.name, got, item.want)
+		}
+	}
+}
+
+func TestClosest(t *testing.T) {
+	a, b := model.Point{Lat: 10, Lng: 106}, model.Point{Lat: 10, Lng: 106.001}
+
+	cases := []struct {
+		name  string
+		a, b  model.Point
+		point model.Point
+		want  model.Point
+	}{
+		{"perpendicular", a, b, model.Point{Lat: 10.001, Lng: 106.0004}, model.Point{Lat: 10, Lng: 106.0004}},
+		{"before a", a, b, model.Point{Lat: 10.001, Lng: 105.999}, a},
+		{"past b", a, b, model.Point{Lat: 9.999, Lng: 106.002}, b},
+		{"on the edge", a, b, model.Point{Lat: 10, Lng: 106.0007}, model.Point{Lat: 10, Lng: 106.0007}},
+		{"zero length", a, a, model.Point{Lat: 10.001, Lng: 106.001}, a},
+	}
+
+	for _, item := range cases {
+		got := closest(item.a, item.b, item.point)
+		if !near(got.Lat, item.want.Lat, 1e-9) || !near(got.Lng, item.want.Lng, 1e-9) {
+			t.Errorf("%s: got %+v, want %+v", item.name, got, item.want)
+		}
+	}
+}
+
+func TestDistanceTo(t *testing.T) {
+	circle := model.Site{Latitude: float(10), Longitude: float(106), Radius: 100}
+	// 0.002 độ vĩ ở vĩ độ 10 ≈ 221.2 mét
+	north := model.Point{Lat: 10.002, Lng: 106}
+
+	cases := []struct {
+		name  string
+		site  model.Site
+		point model.Point
+		want  float64
+		ok    bool
+	}{
+		{"circle center", circle, model.Point{Lat: 10, Lng: 106}, 0, true},
+		{"inside the radius", circle, model.Point{Lat: 10.0005, Lng: 106}, 0, true},
+		{"outside the radius", circle, north, Distance(model.Point{Lat: 10, Lng: 106}, north) - 100, true},
+		{"no radius", model.Site{Latitude: float(10), Longitude: float(106)}, north, Distance(model.Point{Lat: 10, Lng: 106}, north), true},
+		{"polygon inside", model.Site{Polygon: square}, model.Point{Lat: 10.0005, Lng: 106.0005}, 0, true},
+		{"polygon edge", model.Site{Polygon: square}, model.Point{Lat: 10, Lng: 106.0005}, 0, true},
+		{"polygon vertex", model.Site{Polygon: square}, model.Point{Lat: 10.001, Lng: 106.001}, 0, true},
+		{"polygon outside", model.Site{Polygon: square}, model.Point{Lat: 10.002, Lng: 106.0005}, Distance(model.Point{Lat: 10.001, Lng: 106.0005}, model.Point{Lat: 10.002, Lng: 106.0005}), true},
+		{"polygon corner", model.Site{Polygon: square}, model.Point{Lat: 10.002, Lng: 106.002}, Distance(model.Point{Lat: 10.001, Lng: 106.001}, model.Point{Lat: 10.002, Lng: 106.002}), true},
+		// Đa giác được ưu tiên hơn tâm / bán kính
+		{"polygon over circle", model.Site{Polygon: square, Latitude: float(10), Longitude: float(106), Radius: 1000}, north, Distance(model.Point{Lat: 10.001, Lng: 106}, north), true},
+		{"no center", model.Site{Radius: 100}, north, 0, false},
+		{"too few vertices", model.Site{Polygon: square[:2]}, north, 0, false},
+	}
+
+	for _, item := range cases {
+		got, ok := DistanceTo(item.site, item.point)
+		if ok != item.ok || !near(got, item.want, 0.01) {
+			t.Errorf("%s: got %.3f %v, want %.3f %v", item.name, got, ok, item.want, item.ok)
+		}
+	}
+
+	if got, _ := DistanceTo(circle, north); !near(got, 121.2, 0.5) {
+		t.Errorf("outside the radius: got %.3f, want about 121.2", got)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	db := testdb.Open(t, testdb.OrgModels, testdb.EmployeeModels, &model.Site{}, &model.SiteAssignment{})
+
+	// Mỗi nhân viên ở một department riêng: sản xuất, kinh doanh, nhân sự
+	orgs := []testdb.Org{testdb.SeedOrg(t, db), testdb.SeedOrg(t, db), testdb.SeedOrg(t, db)}
+	departments, teams, employees := []departmentModel.Department{}, []teamModel.Team{}, []employeeModel.Employee{}
+	for i, org := range orgs {
+		departments, teams = append(departments, org.Department), append(teams, org.Team)
+		employees = append(employees, testdb.SeedEmployee(t, db, fmt.Sprint(1001+i), &orgs[i].Team.ID))
+	}
+
+	// Nhà máy (reject) gán cho team sản xuất, văn phòng cách khoảng 2.2 km (review) gán cho cả department sản xuất,
+	// chi nhánh (review) gán cho department kinh doanh, nhân sự chưa có địa điểm
+	sites := []model.Site{
+		{SiteName: "Factory", Latitude: float(10), Longitude: float(106), Radius: 100, Policy: model.PolicyReject},
+		{SiteName: "Office", Polygon: []model.Point{{Lat: 10.02, Lng: 106}, {Lat: 10.02, Lng: 106.001}, {Lat: 10.021, Lng: 106.001}, {Lat: 10.021, Lng: 106}}, Policy: model.PolicyReview},
+		{SiteName: "Branch", Latitude: float(11), Longitude: float(107), Radius: 200, Policy: model.PolicyReview},
+	}
+	db.Create(&sites)
+	db.Create(&[]model.SiteAssignment{
+		{SiteID: sites[0].ID, TeamID: &teams[0].ID},
+		{SiteID: sites[1].ID, DepartmentID: &departments[0].ID},
+		{SiteID: sites[2].ID, DepartmentID: &departments[1].ID},
+	})
+
+	cases := []struct {
+		name        string
+		employee    employeeModel.Employee
+		coordinates string
+		status      string
+		siteID      uint
+	}{
+		{"inside the circle", employees[0], "10.0005,106", StatusInside, sites[0].ID},
+		{"inside the polygon", employees[0], "10.0205, 106.0005", StatusInside, sites[1].ID},
+		{"nearest site rejects", employees[0], "10.003,106", StatusReject, sites[0].ID},
+		{"nearest site reviews", employees[0], "10.018,106.0005", StatusReview, sites[1].ID},
+		{"bad coordinates with a reject site", employees[0], "10.0005", StatusReject, 0},
+		{"out of range coordinates", employees[0], "91,106", StatusReject, 0},
+		{"bad coordinates with review sites only", employees[1], "", StatusReview, 0},
+		{"outside a review site", employees[1], "11.01,107", StatusReview, sites[2].ID},
+		{"no site assigned", employees[2], "10,106", StatusUnchecked, 0},
+	}
+
+	for _, item := range cases {
+		result, err := Locate(db, item.employee.ID, item.coordinates)
+		if err != nil {
+			t.Fatal(err)
+		}
+		siteID := uint(0)
+		if result.SiteID != nil {
+			siteID = *result.SiteID
+		}
+		if result.Status != item.status || siteID != item.siteID {
+			t.Errorf("%s: got %s at site %d, want %s at site %d", item.name, result.Status, siteID, item.status, item.siteID)
+		}
+		// Distance chỉ có nghĩa khi tìm được địa điểm gần nhất
+		if siteID > 0 && (result.Status == StatusInside) != (result.Distance == 0) {
+			t.Errorf("%s: distance %.3f with status %s", item.name, result.Distance, result.Status)
+		}
+	}
+}
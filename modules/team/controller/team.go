@@ -0,0 +1,597 @@
+package controller
+
+import (
+	"app/config"
+	"app/database"
+	modell "app/modules/group/model"
+	"app/modules/history"
+	"app/modules/org"
+	"app/modules/team/model"
+	"app/utils"
+	"encoding/json"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"os"
+	"strconv"
+	"time"
+)
+
+type tempTeam model.Team
+
+// Tên loại bản ghi trong tbl_version_history
+const historyType = "team"
+
+// GetTeam Lấy danh sách tất cả team
+// @Summary Get all Teams
+// @Description Returns a list of all Teams
+// @Tags Team
+// @Accept json
+// @Produce json
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /team [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetTeam(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var teams []model.Team
+	results := database.DB.Select("*").Preload("Translations").Preload("Group.Translations").Order("team_id").Find(&teams)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	for i := range teams {
+		teams[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = teams
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetAllTeam Lấy danh sách các team đã bị xoá
+// @Summary Get all Teams (deleted)
+// @Description Returns a list of all Teams (soft-deleted)
+// @Tags Team
+// @Accept json
+// @Produce json
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /team/all [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetAllTeam(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	var teams []model.Team
+	results := database.DB.Select("*").Unscoped().Preload("Translations").Preload("Group.Translations").Where("deleted_at IS NOT NULL").Order("team_id").Find(&teams)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	for i := range teams {
+		teams[i].Localize(utils.Language(c), utils.AllTranslations(c))
+	}
+	response.Data = teams
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetTeamByID returns information about a Team based on its ID
+// @Summary Get a Team by ID
+// @Description Returns information about a Team based on its ID.
+// @Description With as_of (YYYY-MM-DD or RFC 3339) returns the version that was current at that time instead
+// @Tags Team
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Team"
+// @Param as_of query string false "Point in time"
+// @Param lang query string false "Locale from LANG_LIST, default from Accept-Language"
+// @Param translations query bool false "Also return every translation, not only Name"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /team/{id} [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetTeamByID(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	if asOf := c.Query("as_of"); len(asOf) > 0 {
+		return getTeamAsOf(c, asOf)
+	}
+
+	var team model.Team
+	results := database.DB.Select("*").Preload("Translations").Preload("Group.Translations").Where("team_id = ?", c.Params("id")).First(&team)
+	if results.Error != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	team.Localize(utils.Language(c), utils.AllTranslations(c))
+	response.Data = team
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetTeamHistory Lịch sử phiên bản của team
+// @Summary Get the version history of a Team
+// @Description Returns every version of the Team, newest first, with snapshot, diff, actor and timestamp
+// @Tags Team
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Team"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /team/{id}/history [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetTeamHistory(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	teamID, _ := strconv.Atoi(c.Params("id"))
+	rows, err := history.List(database.DB, historyType, uint(teamID))
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	response.Data = rows
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// CreateTeam Tạo mới 1 team
+// @Summary Create a new Team
+// @Description Creates a new Team
+// @Tags Team
+// @Accept json
+// @Produce json
+// @Param body body []model.CreateTeamModel true "New Team information"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /team [post]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func CreateTeam(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.CreateTeamModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		errors := checkTeam(tx, item.GroupID, item.TeamNameVN, item.TeamNameEN, item.TeamNameJP)
+
+		if len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("MISSING_FIELDS")
+			response.ValidateError = errors
+			return c.JSON(response)
+		}
+
+		newTeam := model.Team{
+			GroupID:      item.GroupID,
+			TeamNameVN:   item.TeamNameVN,
+			TeamNameEN:   item.TeamNameEN,
+			TeamNameJP:   item.TeamNameJP,
+			TeamShortcut: item.TeamShortcut,
+			CreatedBy:    getUsername(c),
+		}
+
+		if err := tx.Create(&newTeam).Error; err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("CREATE_FAIL")
+			return c.JSON(response)
+		}
+
+		if err := history.Record(tx, historyType, newTeam.ID, newTeam.LogVersion, history.ActionCreate, nil, newTeam, newTeam.CreatedBy); err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("CREATE_FAIL")
+			return c.JSON(response)
+		}
+
+		if err := org.SyncUnit(tx, historyType, newTeam.ID, newTeam.CreatedBy); err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("CREATE_FAIL")
+			return c.JSON(response)
+		}
+
+		tempNewTeam := tempTeam(newTeam)
+		if err := tempNewTeam.AfterCreate(tx); err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("AFTER_CREATE_FAIL")
+			return c.JSON(response)
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("CREATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// UpdateTeam cập nhật thông tin một Team dựa trên ID
+// @Summary Cập nhật thông tin Team
+// @Description Cập nhật thông tin một Team dựa trên ID. Mỗi lần cập nhật tăng log_version và ghi một dòng lịch sử
+// @Tags Team
+// @Accept json
+// @Produce json
+// @Param body body []model.UpdateTeamModel true "Thông tin Team cần cập nhật"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /team [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func UpdateTeam(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.UpdateTeamModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		// Không có team_id thì tạo mới
+		if item.TeamID == 0 {
+			newTeam := model.Team{
+				GroupID:      item.GroupID,
+				TeamNameVN:   item.TeamNameVN,
+				TeamNameEN:   item.TeamNameEN,
+				TeamNameJP:   item.TeamNameJP,
+				TeamShortcut: item.TeamShortcut,
+				CreatedBy:    getUsername(c),
+			}
+
+			if errors := checkTeam(tx, item.GroupID, item.TeamNameVN, item.TeamNameEN, item.TeamNameJP); len(errors) > 0 {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("MISSING_FIELDS")
+				response.ValidateError = errors
+				return c.JSON(response)
+			}
+
+			if err := tx.Create(&newTeam).Error; err != nil {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("CREATE_FAIL")
+				return c.JSON(response)
+			}
+
+			if err := history.Record(tx, historyType, newTeam.ID, newTeam.LogVersion, history.ActionCreate, nil, newTeam, newTeam.CreatedBy); err != nil {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("CREATE_FAIL")
+				return c.JSON(response)
+			}
+
+			if err := org.SyncUnit(tx, historyType, newTeam.ID, newTeam.CreatedBy); err != nil {
+				tx.Rollback()
+				response.Status = false
+				response.Message = config.GetMessageCode("CREATE_FAIL")
+				return c.JSON(response)
+			}
+			continue
+		}
+
+		action := history.ActionUpdate
+		if item.IsDeleted {
+			action = history.ActionDelete
+		}
+
+		if message, errors := updateTeam(tx, item, getUsername(c), action); len(message) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode(message)
+			response.ValidateError = errors
+			return c.JSON(response)
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// RevertTeam đưa team về phiên bản cũ
+// @Summary Revert a Team to a version
+// @Description Copies the fields of version N back through the normal update path, which creates a new version (action revert)
+// @Tags Team
+// @Accept json
+// @Produce json
+// @Param id path int true "ID of the Team"
+// @Param version path int true "Version to revert to"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /team/{id}/revert/{version} [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func RevertTeam(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	teamID, _ := strconv.Atoi(c.Params("id"))
+	version, _ := strconv.ParseInt(c.Params("version"), 10, 64)
+
+	row, err := history.Version(database.DB, historyType, uint(teamID), version)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	var snapshot model.Team
+	if err := json.Unmarshal([]byte(row.Snapshot), &snapshot); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("SYSTEM_ERROR")
+		return c.JSON(response)
+	}
+
+	item := &model.UpdateTeamModel{
+		TeamID:       teamID,
+		GroupID:      snapshot.GroupID,
+		TeamNameVN:   snapshot.TeamNameVN,
+		TeamNameEN:   snapshot.TeamNameEN,
+		TeamNameJP:   snapshot.TeamNameJP,
+		TeamShortcut: snapshot.TeamShortcut,
+	}
+
+	tx := database.DB.Begin()
+	if message, errors := updateTeam(tx, item, getUsername(c), history.ActionRevert); len(message) > 0 {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode(message)
+		response.ValidateError = errors
+		return c.JSON(response)
+	}
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+// DeleteTeam xóa một Team dựa trên ID
+// @Summary Xóa Team
+// @Description Xóa một Team dựa trên ID
+// @Tags Team
+// @Accept json
+// @Produce json
+// @Param id path int true "ID của Team"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /team/{id} [delete]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func DeleteTeam(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	teamID, _ := strconv.Atoi(c.Params("id"))
+
+	tx := database.DB.Begin()
+	if message, _ := updateTeam(tx, &model.UpdateTeamModel{TeamID: teamID, IsDeleted: true}, getUsername(c), history.ActionDelete); len(message) > 0 {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode(message)
+		return c.JSON(response)
+	}
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("DELETE_SUCCESS")
+	return c.JSON(response)
+}
+
+// RestoreTeam khôi phục một Team dựa trên ID
+// @Summary Khôi phục Team
+// @Description Khôi phục một Team dựa trên ID
+// @Tags Team
+// @Accept json
+// @Produce json
+// @Param id path int true "ID của Team"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /team/{id}/restore [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func RestoreTeam(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	tx := database.DB.Begin()
+
+	var team model.Team
+	result := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&team, c.Params("id"))
+	if result.Error != nil {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+		return c.JSON(response)
+	}
+
+	before := team
+	team.DeletedAt = gorm.DeletedAt{}
+	team.DeletedBy = ""
+	team.UpdatedBy = getUsername(c)
+	team.LogVersion++
+
+	if err := tx.Unscoped().Save(&team).Error; err != nil {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("RESTORE_FAIL")
+		return c.JSON(response)
+	}
+
+	if err := history.Record(tx, historyType, team.ID, team.LogVersion, history.ActionRestore, before, team, team.UpdatedBy); err != nil {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("RESTORE_FAIL")
+		return c.JSON(response)
+	}
+
+	if err := org.SyncUnit(tx, historyType, team.ID, team.UpdatedBy); err != nil {
+		tx.Rollback()
+		response.Status = false
+		response.Message = config.GetMessageCode("RESTORE_FAIL")
+		return c.JSON(response)
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("RESTORE_SUCCESS")
+	return c.JSON(response)
+}
+
+// Đường cập nhật chung của PUT /team, DELETE /team/:id và revert: tăng log_version và ghi lịch sử.
+// Trả về mã message (rỗng nếu thành công) và lỗi validate
+func updateTeam(tx *gorm.DB, item *model.UpdateTeamModel, username, action string) (string, map[string]string) {
+	var team model.Team
+	if err := tx.First(&team, item.TeamID).Error; err != nil {
+		return "NOT_ID_EXISTS", nil
+	}
+	before := team
+
+	if item.IsDeleted {
+		team.DeletedBy = username
+		team.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	} else {
+		errors := checkTeam(tx, item.GroupID, item.TeamNameVN, item.TeamNameEN, item.TeamNameJP)
+		if len(errors) > 0 {
+			return "MISSING_FIELDS", errors
+		}
+
+		team.GroupID = item.GroupID
+		team.TeamNameVN = item.TeamNameVN
+		team.TeamNameEN = item.TeamNameEN
+		team.TeamNameJP = item.TeamNameJP
+		team.TeamShortcut = item.TeamShortcut
+		team.UpdatedBy = username
+	}
+	team.LogVersion++
+
+	if err := tx.Save(&team).Error; err != nil {
+		return "SYSTEM_ERROR", nil
+	}
+
+	if err := history.Record(tx, historyType, team.ID, team.LogVersion, action, before, team, username); err != nil {
+		return "SYSTEM_ERROR", nil
+	}
+
+	if err := org.SyncUnit(tx, historyType, team.ID, username); err != nil {
+		return "SYSTEM_ERROR", nil
+	}
+
+	return "", nil
+}
+
+// Team tại thời điểm asOf, lấy từ snapshot trong lịch sử
+func getTeamAsOf(c *fiber.Ctx, asOf string) error {
+	response := new(config.DataResponse)
+
+	// Chỉ có ngày thì tính đến hết ngày đó
+	at, err := time.Parse(time.RFC3339, asOf)
+	if err != nil {
+		date, dateErr := time.Parse("2006-01-02", asOf)
+		if dateErr != nil {
+			response.Status = false
+			response.Message = config.GetMessageCode("FORMAT_DATE")
+			return c.JSON(response)
+		}
+		at = date.AddDate(0, 0, 1)
+	}
+
+	teamID, _ := strconv.Atoi(c.Params("id"))
+	row, err := history.AsOf(database.DB, historyType, uint(teamID), at)
+	if err != nil || row == nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+
+	response.Data = row
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+func checkTeam(tx *gorm.DB, groupID int, nameVN, nameEN, nameJP string) map[string]string {
+	listCheck := []string{"TeamNameVN", "TeamNameEN", "TeamNameJP"}
+	vItem := map[string]string{
+		"TeamNameVN": nameVN,
+		"TeamNameEN": nameEN,
+		"TeamNameJP": nameJP,
+	}
+	errors := utils.RequireCheck(listCheck, vItem, map[string]string{})
+
+	var group modell.Group
+	if err := tx.Where("group_id = ?", groupID).First(&group).Error; err != nil {
+		errors["GroupID"] = config.GetMessageCode("NOT_ID_EXISTS")
+	}
+
+	return errors
+}
+
+func (g *tempTeam) AfterCreate(tx *gorm.DB) (err error) {
+	if g.CreatedBy == "1105" {
+		fmt.Println(">>>>  it's created by Admin....")
+		WriteLog(">>>>  it's created by Admin....")
+	}
+	return nil
+}
+
+func WriteLog(logEntry string) error {
+	// Tạo một timestamp để thêm vào log
+	timestamp := time.Now().Format("2006-01-02 15:04:05")
+	logEntryWithTimestamp := fmt.Sprintf("[%s] %s\n", timestamp, logEntry)
+
+	// Mở file log.txt trong chế độ append
+	file, err := os.OpenFile("assets/log/log.txt", os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Ghi log vào file
+	_, err = file.WriteString(logEntryWithTimestamp)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func getUsername(c *fiber.Ctx) string {
+	response := new(config.DataResponse)
+
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("ERROR_GET_USERNAME")
+	}
+
+	return tokenData.Username
+}
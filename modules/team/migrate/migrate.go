@@ -0,0 +1,22 @@
+package teamMigrate
+
+import (
+	"app/database"
+	"app/modules/history"
+	model "app/modules/team/model"
+)
+
+func MigrateTbl() bool {
+	db := database.DB
+
+	db.AutoMigrate(&model.Team{})
+
+	// Team có từ trước khi bật lịch sử: ghi phiên bản hiện tại làm mốc
+	var teams []model.Team
+	db.Unscoped().Find(&teams)
+	for _, item := range teams {
+		history.Baseline(db, "team", item.ID, item.LogVersion, item, item.UpdatedAt)
+	}
+
+	return true
+}
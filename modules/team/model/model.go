@@ -0,0 +1,96 @@
+package model
+
+import (
+	"app/config"
+	"time"
+	"gorm.io/gorm"
+	"app/modules/group/model"
+	"app/modules/translation"
+	translationModel "app/modules/translation/model"
+)
+
+type Model struct {
+	ID        uint `gorm:"primarykey;column:team_id;<-:create"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+
+type Team struct {
+	Model
+	GroupID  int               `gorm:"column:group_id;not null"`
+	Group    model.Group  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	TeamNameVN   string            `gorm:"column:team_name_vn;size:100;not null"`
+	TeamNameEN   string            `gorm:"column:team_name_en;size:100;not null"`
+	TeamNameJP   string            `gorm:"column:team_name_jp;size:100;not null"`
+	Name         string            `gorm:"-" json:"name"` // tên theo ngôn ngữ của request, xem Localize
+	Names        map[string]string `gorm:"-" json:"names,omitempty"` // mọi bản dịch của tên (ngôn ngữ → tên), chỉ trả về khi all
+	Translations []translationModel.Translation `gorm:"polymorphic:Entity;polymorphicValue:team;constraint:-" json:"-"`
+	TeamShortcut string            `gorm:"column:team_shortcut;size:5"`
+	LogVersion    int64             `gorm:"column:log_version;default:0"`
+	CreatedBy  string     `gorm:"column:created_by;size:15"`
+	UpdatedBy  string     `gorm:"column:updated_by;size:15"`	
+	DeletedBy  string     `gorm:"column:deleted_by;size:15"`
+}
+
+// Localize điền Name theo ngôn ngữ lang (có fallback), all thì trả thêm Names
+func (t *Team) Localize(lang string, all bool) {
+	names := t.names()
+	t.Name = config.LocalValue(lang, names)
+	if all {
+		t.Names = names
+	}
+	if t.Group.ID != 0 {
+		t.Group.Localize(lang, all)
+	}
+}
+
+// LocalName tên theo ngôn ngữ lang
+func (t *Team) LocalName(lang string) string {
+	return config.LocalValue(lang, t.names())
+}
+
+// Tên theo các cột vn / en / jp, ghi đè bằng Translations nếu đã preload
+func (t *Team) names() map[string]string {
+	return translation.Values(t.Translations, "name", map[string]string{"vn": t.TeamNameVN, "en": t.TeamNameEN, "jp": t.TeamNameJP})
+}
+
+// AfterSave đồng bộ các cột tên sang tbl_translation
+func (t *Team) AfterSave(tx *gorm.DB) error {
+	return translation.Save(tx, "team", t.ID, "name", map[string]string{"vn": t.TeamNameVN, "en": t.TeamNameEN, "jp": t.TeamNameJP}, t.UpdatedBy)
+}
+
+
+type CreateTeamModel struct {
+	GroupID  int    `json:"group_id" validate:"required"`
+	TeamNameVN   string `json:"team_name_vn" validate:"required"`
+	TeamNameEN   string `json:"team_name_en" validate:"required"`
+	TeamNameJP   string `json:"team_name_jp" validate:"required"`
+	TeamShortcut string `json:"team_shortcut"`
+	CreatedBy     string `json:"created_by"`
+}
+
+type UpdateTeamModel struct {
+	TeamID       int    `json:"team_id" validate:"required"`
+	GroupID  int    `json:"group_id" validate:"required"`
+	TeamNameVN   string `json:"team_name_vn" validate:"required"`
+	TeamNameEN   string `json:"team_name_en" validate:"required"`
+	TeamNameJP   string `json:"team_name_jp" validate:"required"`
+	TeamShortcut string `json:"team_shortcut"`
+	IsDeleted     bool   `json:"is_deleted"`
+	UpdatedBy     string `json:"updated_by"`
+}
+
+// type HeaderTeam struct {
+// 	CreatedBy  string     `gorm:"column:created_by;size:15"`
+// 	UpdatedBy  string     `gorm:"column:updated_by;size:15"`	
+// 	DeletedBy  string     `gorm:"column:deleted_by;size:15"`
+// }
+
+
+
+// Tên bảng trong CSDL
+func (Team) TableName() string {
+	return "tbl_team"
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"app/middleware"
+
+	"app/modules/team/controller"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func InitTeamRoutes(app *fiber.App) {
+	team := app.Group("/team", middleware.AppInfo, middleware.AppAuthen)
+
+	getList := team.Group("")
+	getList.Get("/", middleware.Require("team:read"), controller.GetTeam)
+	getList.Get("/all", middleware.Require("team:read"), controller.GetAllTeam) //error nếu swap get :id trước
+	getList.Get("/:id", middleware.Require("team:read"), controller.GetTeamByID)
+	getList.Get("/:id/history", middleware.Require("team:read"), controller.GetTeamHistory)
+
+
+	
+	team.Post("/", middleware.Require("team:write"), controller.CreateTeam)
+	team.Put("/", middleware.Require("team:write"), controller.UpdateTeam)
+	team.Put("/:id/revert/:version", middleware.Require("team:write"), controller.RevertTeam)
+	team.Delete("/:id", middleware.Require("team:delete"), controller.DeleteTeam)
+	team.Put("/restore/:id", middleware.Require("team:restore"), controller.RestoreTeam)
+}
@@ -0,0 +1,254 @@
+package controller
+
+import (
+	"app/config"
+	"app/database"
+	"app/modules/org"
+	orgModel "app/modules/org/model"
+	"app/modules/translation"
+	"app/modules/translation/model"
+	"app/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// GetTranslation Lấy danh sách bản dịch
+// @Summary Get translations
+// @Description Returns the stored translations, filtered by entity, record, field and locale
+// @Tags Translation
+// @Accept json
+// @Produce json
+// @Param entity_type query string false "department | group | team | employee | org_unit | shift"
+// @Param entity_id query int false "ID of the record"
+// @Param field query string false "Translatable field, e.g. name"
+// @Param locale query string false "Locale from LANG_LIST"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /translation [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetTranslation(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	query := database.DB.Order("entity_type, entity_id, field, locale")
+	for _, key := range []string{"entity_type", "entity_id", "field", "locale"} {
+		if value := c.Query(key); len(value) > 0 {
+			query = query.Where(key+" = ?", value)
+		}
+	}
+
+	var translations []model.Translation
+	if err := query.Find(&translations).Error; err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("GET_DATA_FAIL")
+		return c.JSON(response)
+	}
+	response.Data = translations
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetLocale Danh sách ngôn ngữ
+// @Summary Get the configured locales
+// @Description Returns LANG_LIST and the default locale (LANG_DEFAULT), which every translatable field must have
+// @Tags Translation
+// @Accept json
+// @Produce json
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /translation/locale [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetLocale(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+	response.Data = model.LocaleModel{
+		Locales: config.Languages(),
+		Default: config.DefaultLanguage(),
+	}
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// GetMissingTranslation Bản ghi còn thiếu bản dịch
+// @Summary Get missing translations
+// @Description Returns the records (not deleted) that have no value for a translatable field in some locales
+// @Tags Translation
+// @Accept json
+// @Produce json
+// @Param entity_type query string false "department | group | team | employee | org_unit | shift (default all)"
+// @Param locale query string false "Locale from LANG_LIST (default all)"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /translation/missing [get]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func GetMissingTranslation(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	entityTypes := translation.EntityTypes()
+	if entityType := c.Query("entity_type"); len(entityType) > 0 {
+		if !translation.IsEntityType(entityType) {
+			response.Status = false
+			response.Message = config.GetMessageCode("VALUE_INVALID")
+			response.ValidateError = map[string]string{"EntityType": config.GetMessageCode("VALUE_INVALID")}
+			return c.JSON(response)
+		}
+		entityTypes = []string{entityType}
+	}
+
+	locales := config.Languages()
+	if locale := c.Query("locale"); len(locale) > 0 {
+		if !config.IsLanguage(locale) {
+			response.Status = false
+			response.Message = config.GetMessageCode("VALUE_INVALID")
+			response.ValidateError = map[string]string{"Locale": config.GetMessageCode("VALUE_INVALID")}
+			return c.JSON(response)
+		}
+		locales = []string{locale}
+	}
+
+	missing := []model.MissingModel{}
+	for _, entityType := range entityTypes {
+		rows, err := translation.Missing(database.DB, entityType, locales)
+		if err != nil {
+			response.Status = false
+			response.Message = config.GetMessageCode("GET_DATA_FAIL")
+			return c.JSON(response)
+		}
+		missing = append(missing, rows...)
+	}
+
+	response.Data = missing
+	response.Status = true
+	response.Message = config.GetMessageCode("GET_DATA_SUCCESS")
+	return c.JSON(response)
+}
+
+// SaveTranslation Thêm / sửa / xoá bản dịch
+// @Summary Save translations
+// @Description Upserts translations. vn / en / jp also update the name columns of the record
+// @Description (for department / group / team this is a rename: log_version is increased and a history version is recorded).
+// @Description An empty value removes the translation, except for the default locale and the vn / en / jp columns.
+// @Description Names of org units imported from department / group / team are saved on the department / group / team
+// @Tags Translation
+// @Accept json
+// @Produce json
+// @Param body body []model.SaveTranslationModel true "Translations"
+// @Success 200 {object} config.DataResponse
+// @Failure 500 {object} config.DataResponse
+// @Router /translation [put]
+// @Security ApiKeyAuth
+// @Security ApiTokenAuth
+func SaveTranslation(c *fiber.Ctx) error {
+	response := new(config.DataResponse)
+
+	var payload []*model.SaveTranslationModel
+	if err := c.BodyParser(&payload); err != nil {
+		response.Status = false
+		response.Message = config.GetMessageCode("PARAM_ERROR")
+		return c.JSON(response)
+	}
+
+	tx := database.DB.Begin()
+
+	for _, item := range payload {
+		errors := checkTranslation(item)
+		if len(errors) > 0 {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("MISSING_FIELDS")
+			response.ValidateError = errors
+			return c.JSON(response)
+		}
+
+		if !translation.Exists(tx, item.EntityType, item.EntityID) {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("NOT_ID_EXISTS")
+			return c.JSON(response)
+		}
+
+		// Tên đơn vị chuyển từ department / group / team sửa qua bản ghi cũ để hai bên không lệch nhau
+		if item.EntityType == "org_unit" && isLegacyUnit(tx, item.EntityID) {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("VALUE_INVALID")
+			response.ValidateError = map[string]string{"EntityType": config.GetMessageCode("VALUE_INVALID")}
+			return c.JSON(response)
+		}
+
+		var err error
+		switch {
+		case org.IsUnitType(item.EntityType) && len(translation.Column(item.EntityType, item.Field, item.Locale)) > 0:
+			// Cột tên của department / group / team: tăng log_version, ghi lịch sử như đổi tên qua API
+			err = org.RenameUnit(tx, item.EntityType, item.EntityID, item.Locale, item.Value, getUsername(c))
+		case org.IsUnitType(item.EntityType):
+			if err = translation.SaveValue(tx, item.EntityType, item.EntityID, item.Field, item.Locale, item.Value, getUsername(c)); err == nil {
+				err = org.SyncUnit(tx, item.EntityType, item.EntityID, getUsername(c))
+			}
+		default:
+			err = translation.SaveValue(tx, item.EntityType, item.EntityID, item.Field, item.Locale, item.Value, getUsername(c))
+		}
+		if err != nil {
+			tx.Rollback()
+			response.Status = false
+			response.Message = config.GetMessageCode("SYSTEM_ERROR")
+			return c.JSON(response)
+		}
+	}
+
+	tx.Commit()
+
+	response.Status = true
+	response.Message = config.GetMessageCode("UPDATE_SUCCESS")
+	return c.JSON(response)
+}
+
+func checkTranslation(item *model.SaveTranslationModel) map[string]string {
+	vItem := map[string]string{
+		"EntityType": item.EntityType,
+		"Field":      item.Field,
+		"Locale":     item.Locale,
+		"Value":      item.Value,
+	}
+	errors := utils.RequireCheck([]string{"EntityType", "Field", "Locale"}, vItem, map[string]string{})
+
+	if _, ok := errors["EntityType"]; !ok && !translation.IsEntityType(item.EntityType) {
+		errors["EntityType"] = config.GetMessageCode("VALUE_INVALID")
+	}
+	if _, ok := errors["Field"]; !ok && !translation.IsField(item.EntityType, item.Field) {
+		errors["Field"] = config.GetMessageCode("VALUE_INVALID")
+	}
+	if _, ok := errors["Locale"]; !ok && !config.IsLanguage(item.Locale) {
+		errors["Locale"] = config.GetMessageCode("VALUE_INVALID")
+	}
+
+	// Ngôn ngữ mặc định và các cột cũ (NOT NULL) luôn phải có giá trị
+	column := translation.Column(item.EntityType, item.Field, item.Locale)
+	if item.Locale == config.DefaultLanguage() || len(column) > 0 {
+		errors = utils.RequireCheck([]string{"Value"}, vItem, errors)
+	}
+	if len(column) > 0 {
+		errors = utils.MaxLengthCheck([]string{"Value:100"}, vItem, errors)
+	}
+
+	return errors
+}
+
+func isLegacyUnit(tx *gorm.DB, unitID uint) bool {
+	var count int64
+	tx.Model(&orgModel.OrgUnit{}).Where("org_unit_id = ? AND legacy_type <> ''", unitID).Count(&count)
+	return count > 0
+}
+
+func getUsername(c *fiber.Ctx) string {
+	tokenData, err := utils.ExtractTokenData(c)
+	if err != nil {
+		return ""
+	}
+
+	return tokenData.Username
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"app/config"
+	"app/database/testdb"
+	historyModel "app/modules/history/model"
+	"app/modules/org"
+	orgModel "app/modules/org/model"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func putTranslation(t *testing.T, app *fiber.App, body string) config.DataResponse {
+	t.Helper()
+
+	request := httptest.NewRequest(http.MethodPut, "/translation", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var response config.DataResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+
+	return response
+}
+
+func TestSaveTranslationTeamName(t *testing.T) {
+	t.Setenv("LANG_LIST", "vn,en,jp,ko")
+	t.Setenv("LANG_DEFAULT", "vn")
+	db := testdb.Open(t, testdb.OrgModels, &historyModel.VersionHistory{}, &orgModel.OrgUnitType{}, &orgModel.OrgUnit{}, &orgModel.OrgUnitPath{})
+	team := testdb.SeedOrg(t, db).Team
+	if err := org.SyncUnit(db, "team", team.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New()
+	app.Put("/translation", SaveTranslation)
+
+	body := fmt.Sprintf(`[{"entity_type":"team","entity_id":%d,"field":"name","locale":"en","value":"Morning shift"},
+		{"entity_type":"team","entity_id":%d,"field":"name","locale":"ko","value":"오전조"}]`, team.ID, team.ID)
+	if response := putTranslation(t, app, body); !response.Status {
+		t.Fatalf("got %s %v", response.Message, response.ValidateError)
+	}
+
+	// Cột tên đổi qua đường rename: tăng log_version và có một phiên bản lịch sử
+	db.First(&team, team.ID)
+	var versions []historyModel.VersionHistory
+	db.Where("entity_type = 'team' AND entity_id = ?", team.ID).Find(&versions)
+	if team.TeamNameEN != "Morning shift" || team.LogVersion != 1 || len(versions) != 1 || versions[0].Version != 1 {
+		t.Fatalf("team %+v, %d versions", team, len(versions))
+	}
+
+	// Đơn vị tbl_org_unit nhận cả tên mới và bản dịch ko
+	var unit orgModel.OrgUnit
+	db.Preload("Translations").Where("legacy_type = 'team' AND legacy_id = ?", team.ID).First(&unit)
+	unit.Localize("ko", true)
+	if unit.UnitNameEN != "Morning shift" || unit.Name != "오전조" {
+		t.Fatalf("org unit not synced: %+v", unit)
+	}
+
+	// Không sửa trực tiếp tên của đơn vị gắn với team
+	body = fmt.Sprintf(`[{"entity_type":"org_unit","entity_id":%d,"field":"name","locale":"en","value":"Other"}]`, unit.ID)
+	if response := putTranslation(t, app, body); response.Status || response.Message != config.GetMessageCode("VALUE_INVALID") {
+		t.Fatalf("org_unit edit: got %v %s, want VALUE_INVALID", response.Status, response.Message)
+	}
+}
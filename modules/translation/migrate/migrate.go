@@ -0,0 +1,22 @@
+package translationMigrate
+
+import (
+	"app/core"
+	"app/database"
+	"app/modules/translation"
+	model "app/modules/translation/model"
+)
+
+// Chạy trước các module có tên dịch được: bảng chưa có thì Import bỏ qua, bản ghi tạo sau được ghi qua hook AfterSave
+func MigrateTbl() bool {
+	db := database.DB
+
+	db.AutoMigrate(&model.Translation{})
+
+	// Chép các cột tên vn / en / jp sang tbl_translation
+	if err := translation.Import(db); err != nil {
+		core.WriteLog("ERROR | IMPORT TRANSLATION | " + err.Error())
+	}
+
+	return true
+}
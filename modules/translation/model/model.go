@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// Translation bản dịch của một trường (Field) của bản ghi EntityType / EntityID theo ngôn ngữ Locale.
+// Các trường tên cũ (vd: team_name_vn / en / jp) được đồng bộ sang bảng này, ngôn ngữ khác chỉ lưu ở đây
+type Translation struct {
+	ID         uint      `gorm:"primarykey;column:translation_id;<-:create" json:"translation_id"`
+	EntityType string    `gorm:"column:entity_type;size:30;not null;uniqueIndex:idx_translation_key" json:"entity_type"`
+	EntityID   uint      `gorm:"column:entity_id;not null;uniqueIndex:idx_translation_key" json:"entity_id"`
+	Field      string    `gorm:"column:field;size:50;not null;uniqueIndex:idx_translation_key" json:"field"`
+	Locale     string    `gorm:"column:locale;size:10;not null;uniqueIndex:idx_translation_key;index" json:"locale"`
+	Value      string    `gorm:"column:value;type:text;not null" json:"value"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	UpdatedBy  string    `gorm:"column:updated_by;size:15" json:"updated_by"`
+}
+
+type SaveTranslationModel struct {
+	EntityType string `json:"entity_type" validate:"required"`
+	EntityID   uint   `json:"entity_id" validate:"required"`
+	Field      string `json:"field" validate:"required"`
+	Locale     string `json:"locale" validate:"required"`
+	Value      string `json:"value"`
+}
+
+// MissingModel bản ghi còn thiếu bản dịch của Field ở các ngôn ngữ Locales
+type MissingModel struct {
+	EntityType string   `json:"entity_type"`
+	EntityID   uint     `json:"entity_id"`
+	Field      string   `json:"field"`
+	Locales    []string `json:"locales"`
+}
+
+// LocaleModel cấu hình ngôn ngữ (LANG_LIST, LANG_DEFAULT)
+type LocaleModel struct {
+	Locales []string `json:"locales"`
+	Default string   `json:"default"`
+}
+
+// Tên bảng trong CSDL
+func (Translation) TableName() string {
+	return "tbl_translation"
+}
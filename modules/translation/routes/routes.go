@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"app/middleware"
+
+	"app/modules/translation/controller"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func InitTranslationRoutes(app *fiber.App) {
+	translation := app.Group("/translation", middleware.AppInfo, middleware.AppAuthen)
+
+	getList := translation.Group("")
+	getList.Get("/", middleware.Require("translation:read"), controller.GetTranslation)
+	getList.Get("/locale", middleware.Require("translation:read"), controller.GetLocale)
+	getList.Get("/missing", middleware.Require("translation:read"), controller.GetMissingTranslation)
+
+	translation.Put("/", middleware.Require("translation:write"), controller.SaveTranslation)
+}
@@ -0,0 +1,262 @@
+package translation
+
+import (
+	"app/modules/translation/model"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Bản ghi có thể dịch: bảng, cột khoá và các trường dịch được.
+// columns: trường → ngôn ngữ → cột cũ còn lưu trên bảng (vn / en / jp), ngôn ngữ khác chỉ có trong tbl_translation
+type entity struct {
+	table    string
+	idColumn string
+	columns  map[string]map[string]string
+}
+
+var entities = map[string]entity{
+	"department": {table: "tbl_department", idColumn: "department_id", columns: map[string]map[string]string{"name": nameColumns("department")}},
+	"group":      {table: "tbl_group", idColumn: "group_id", columns: map[string]map[string]string{"name": nameColumns("group")}},
+	"team":       {table: "tbl_team", idColumn: "team_id", columns: map[string]map[string]string{"name": nameColumns("team")}},
+	"employee":   {table: "tbl_employee", idColumn: "employee_id", columns: map[string]map[string]string{"name": nameColumns("employee")}},
+	"org_unit":   {table: "tbl_org_unit", idColumn: "org_unit_id", columns: map[string]map[string]string{"name": nameColumns("unit")}},
+	"shift":      {table: "tbl_shift", idColumn: "shift_id", columns: map[string]map[string]string{"name": nameColumns("shift")}},
+}
+
+func nameColumns(prefix string) map[string]string {
+	return map[string]string{"vn": prefix + "_name_vn", "en": prefix + "_name_en", "jp": prefix + "_name_jp"}
+}
+
+// EntityTypes loại bản ghi có thể dịch
+func EntityTypes() []string {
+	return []string{"department", "group", "team", "employee", "org_unit", "shift"}
+}
+
+// IsEntityType true nếu entityType có thể dịch
+func IsEntityType(entityType string) bool {
+	_, ok := entities[entityType]
+	return ok
+}
+
+// Fields các trường dịch được của entityType, nil nếu không hỗ trợ
+func Fields(entityType string) []string {
+	var fields []string
+	for field := range entities[entityType].columns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	return fields
+}
+
+// IsField true nếu field là trường dịch được của entityType
+func IsField(entityType, field string) bool {
+	_, ok := entities[entityType].columns[field]
+	return ok
+}
+
+// Column cột cũ lưu bản dịch locale của trường field, rỗng nếu chỉ lưu ở tbl_translation
+func Column(entityType, field, locale string) string {
+	return entities[entityType].columns[field][locale]
+}
+
+// Exists true nếu bản ghi entityType / entityID tồn tại và chưa bị xoá
+func Exists(tx *gorm.DB, entityType string, entityID uint) bool {
+	item, ok := entities[entityType]
+	if !ok {
+		return false
+	}
+
+	var count int64
+	tx.Table(item.table).Where(item.idColumn+" = ? AND deleted_at IS NULL", entityID).Count(&count)
+	return count > 0
+}
+
+// Save ghi các bản dịch (ngôn ngữ → giá trị) của một trường, bỏ qua giá trị rỗng
+func Save(tx *gorm.DB, entityType string, entityID uint, field string, values map[string]string, username string) error {
+	now := time.Now()
+	var rows []model.Translation
+	for locale, value := range values {
+		if len(value) == 0 {
+			continue
+		}
+		rows = append(rows, model.Translation{
+			EntityType: entityType,
+			EntityID:   entityID,
+			Field:      field,
+			Locale:     locale,
+			Value:      value,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+			UpdatedBy:  username,
+		})
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "entity_type"}, {Name: "entity_id"}, {Name: "field"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at", "updated_by"}),
+	}).Create(&rows).Error
+}
+
+// SaveValue ghi bản dịch locale của một trường. Ngôn ngữ còn cột cũ thì cập nhật cả cột đó,
+// value rỗng thì xoá bản dịch (caller không cho xoá ngôn ngữ mặc định / ngôn ngữ có cột cũ)
+func SaveValue(tx *gorm.DB, entityType string, entityID uint, field, locale, value, username string) error {
+	if len(value) == 0 {
+		return tx.Where("entity_type = ? AND entity_id = ? AND field = ? AND locale = ?", entityType, entityID, field, locale).
+			Delete(&model.Translation{}).Error
+	}
+
+	if column := Column(entityType, field, locale); len(column) > 0 {
+		item := entities[entityType]
+		err := tx.Table(item.table).Where(item.idColumn+" = ?", entityID).Updates(map[string]interface{}{
+			column:       value,
+			"updated_by": username,
+			"updated_at": time.Now(),
+		}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return Save(tx, entityType, entityID, field, map[string]string{locale: value}, username)
+}
+
+// SyncLegacy đọc lại các cột tên cũ của bản ghi và ghi sang tbl_translation
+// (dùng khi bảng được cập nhật bằng SQL, không qua hook AfterSave của model)
+func SyncLegacy(tx *gorm.DB, entityType string, entityID uint, username string) error {
+	item, ok := entities[entityType]
+	if !ok {
+		return nil
+	}
+
+	for field, columns := range item.columns {
+		var selects []string
+		for _, column := range columns {
+			selects = append(selects, column)
+		}
+
+		row := map[string]interface{}{}
+		if err := tx.Table(item.table).Select(selects).Where(item.idColumn+" = ?", entityID).Take(&row).Error; err != nil {
+			return err
+		}
+
+		values := map[string]string{}
+		for locale, column := range columns {
+			values[locale], _ = row[column].(string)
+		}
+		if err := Save(tx, entityType, entityID, field, values, username); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Values bản dịch của trường field: lấy từ các cột cũ (legacy) rồi ghi đè bằng các dòng tbl_translation đã preload
+func Values(rows []model.Translation, field string, legacy map[string]string) map[string]string {
+	values := map[string]string{}
+	for locale, value := range legacy {
+		if len(value) > 0 {
+			values[locale] = value
+		}
+	}
+	for _, row := range rows {
+		if row.Field == field {
+			values[row.Locale] = row.Value
+		}
+	}
+
+	return values
+}
+
+// Find các bản dịch của nhiều bản ghi, theo entity_id (dùng khi không Preload được, vd: Scan)
+func Find(tx *gorm.DB, entityType string, ids []uint) map[uint][]model.Translation {
+	result := map[uint][]model.Translation{}
+	if len(ids) == 0 {
+		return result
+	}
+
+	var rows []model.Translation
+	tx.Where("entity_type = ? AND entity_id IN ?", entityType, ids).Find(&rows)
+	for _, row := range rows {
+		result[row.EntityID] = append(result[row.EntityID], row)
+	}
+
+	return result
+}
+
+// Import chép các cột tên cũ của mọi bản ghi sang tbl_translation, bỏ qua bản dịch đã có
+func Import(tx *gorm.DB) error {
+	for _, entityType := range EntityTypes() {
+		item := entities[entityType]
+		if !tx.Migrator().HasTable(item.table) {
+			continue
+		}
+		for field, columns := range item.columns {
+			for locale, column := range columns {
+				query := fmt.Sprintf(`INSERT INTO tbl_translation (entity_type, entity_id, field, locale, value, created_at, updated_at)
+					SELECT ?, %s, ?, ?, %s, NOW(), NOW() FROM %s WHERE COALESCE(%s, '') <> ''
+					ON CONFLICT DO NOTHING`, item.idColumn, column, item.table, column)
+				if err := tx.Exec(query, entityType, field, locale).Error; err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Missing các bản ghi chưa xoá còn thiếu bản dịch ở một trong các ngôn ngữ locales
+func Missing(tx *gorm.DB, entityType string, locales []string) ([]model.MissingModel, error) {
+	item, ok := entities[entityType]
+	if !ok || len(locales) == 0 {
+		return nil, nil
+	}
+
+	values := make([]string, len(locales))
+	args := []interface{}{}
+	for i, locale := range locales {
+		values[i] = fmt.Sprintf("(?, %d)", i)
+		args = append(args, locale)
+	}
+
+	var result []model.MissingModel
+	for _, field := range Fields(entityType) {
+		query := fmt.Sprintf(`SELECT e.%s AS entity_id, l.locale FROM %s AS e CROSS JOIN (VALUES %s) AS l(locale, ord)
+			WHERE e.deleted_at IS NULL AND NOT EXISTS (
+				SELECT 1 FROM tbl_translation AS t
+				WHERE t.entity_type = ? AND t.entity_id = e.%s AND t.field = ? AND t.locale = l.locale AND t.value <> ''
+			)
+			ORDER BY e.%s, l.ord`, item.idColumn, item.table, strings.Join(values, ", "), item.idColumn, item.idColumn)
+
+		var rows []struct {
+			EntityID uint
+			Locale   string
+		}
+		if err := tx.Raw(query, append(args, entityType, field)...).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+
+		index := map[uint]int{}
+		for _, row := range rows {
+			i, ok := index[row.EntityID]
+			if !ok {
+				i = len(result)
+				index[row.EntityID] = i
+				result = append(result, model.MissingModel{EntityType: entityType, EntityID: row.EntityID, Field: field})
+			}
+			result[i].Locales = append(result[i].Locales, row.Locale)
+		}
+	}
+
+	return result, nil
+}
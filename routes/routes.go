@@ -12,5 +12,6 @@ func InitRoutes(app *fiber.App) {
 	// Document
 	app.Get("/document/*", swagger.HandlerDefault)
 	app.Get("/test", controller.Test)
+	app.Get("/.well-known/jwks.json", controller.Jwks)
 
 }
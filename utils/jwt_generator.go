@@ -8,32 +8,42 @@ import (
 	"github.com/golang-jwt/jwt"
 )
 
-func GenerateAccessToken(username, userAgent, ipAddress string) (string, error) {
-	secret := config.Config("JWT_SECRET_KEY")
+func GenerateAccessToken(sessionID, username, userAgent, ipAddress string, permission int) (string, error) {
 	timeExpire := config.Config("JWT_EXPIRED_TIME")
 
 	minutesCount, _ := strconv.Atoi(timeExpire)
 
 	claims := jwt.MapClaims{}
 
+	claims["sid"] = sessionID
 	claims["username"] = username
 	claims["useragent"] = userAgent
 	claims["ipaddress"] = ipAddress
+	claims["permission"] = permission
 	claims["createdat"] = time.Now().Unix()
 	claims["exp"] = time.Now().Add(time.Minute * time.Duration(minutesCount)).Unix()
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return SignToken(claims)
+}
 
-	t, err := token.SignedString([]byte(secret))
-	if err != nil {
-		return "", err
-	}
+// GenerateImpersonationToken token "act as": username là user bị giả danh, claim `act` là admin thật.
+// Không có refresh token, hết hạn sau `minutes` phút.
+func GenerateImpersonationToken(sessionID, username, actor, userAgent, ipAddress string, permission, minutes int) (string, error) {
+	claims := jwt.MapClaims{}
 
-	return t, nil
+	claims["sid"] = sessionID
+	claims["username"] = username
+	claims["act"] = actor
+	claims["useragent"] = userAgent
+	claims["ipaddress"] = ipAddress
+	claims["permission"] = permission
+	claims["createdat"] = time.Now().Unix()
+	claims["exp"] = time.Now().Add(time.Minute * time.Duration(minutes)).Unix()
+
+	return SignToken(claims)
 }
 
 func GenerateAccessTokenMobile(username, userAgent, ipAddress string, permission int) (string, error) {
-	secret := config.Config("JWT_SECRET_KEY")
 	timeExpire := config.Config("JWT_EXPIRED_TIME")
 
 	minutesCount, _ := strconv.Atoi(timeExpire)
@@ -47,30 +57,28 @@ func GenerateAccessTokenMobile(username, userAgent, ipAddress string, permission
 	claims["createdat"] = time.Now().Unix()
 	claims["exp"] = time.Now().Add(time.Minute * time.Duration(minutesCount)).Unix()
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-
-	t, err := token.SignedString([]byte(secret))
-	if err != nil {
-		return "", err
-	}
-
-	return t, nil
+	return SignToken(claims)
 }
 
-func EncodeDataTokenMobile(employeeId string, dateInSeconds int64, coordinates string, shiftId int) (string, error) {
+// EncodeDataTokenMobile ký payload chấm công cho app mobile, tên claim trùng với tag json của Data (DecodeData).
+// jti ngẫu nhiên để mỗi payload chỉ được dùng một lần, hết hạn sau `minutes` phút
+func EncodeDataTokenMobile(employeeId string, dateInSeconds int64, coordinates string, shiftId, minutes int) (string, error) {
 	secret := config.Config("JWT_DATA_SECRET_KEY")
-	timeExpire := config.Config("JWT_DATA_EXPIRED_TIME")
 
-	minutesCount, _ := strconv.Atoi(timeExpire)
+	tokenID, err := RandomString(16)
+	if err != nil {
+		return "", err
+	}
 
 	claims := jwt.MapClaims{}
 
-	claims["employeeId"] = employeeId
-	claims["dateInSeconds"] = dateInSeconds
+	claims["jti"] = tokenID
+	claims["employee_id"] = employeeId
+	claims["date_in_seconds"] = dateInSeconds
 	claims["coordinates"] = coordinates
-	claims["shiftId"] = shiftId
+	claims["shift_id"] = shiftId
 	claims["createdat"] = time.Now().Unix()
-	claims["exp"] = time.Now().Add(time.Minute * time.Duration(minutesCount)).Unix()
+	claims["exp"] = time.Now().Add(time.Minute * time.Duration(minutes)).Unix()
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
@@ -0,0 +1,235 @@
+package utils
+
+import (
+	"app/config"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// Thuật toán được chấp nhận khi ký / xác thực access token
+var validMethods = []string{"HS256", "RS256", "EdDSA"}
+
+// SigningKey một khoá trong key ring.
+// Status "active": dùng để ký và xác thực, "verify": chỉ xác thực token cũ.
+type SigningKey struct {
+	Kid        string `json:"kid"`
+	Alg        string `json:"alg"`
+	Status     string `json:"status"`
+	Secret     string `json:"secret"`
+	PrivateKey string `json:"private_key"`
+	PublicKey  string `json:"public_key"`
+
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+type Jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+var (
+	keyRing     []*SigningKey
+	keyRingErr  error
+	keyRingOnce sync.Once
+)
+
+// Đọc key ring từ JWT_KEY_FILE (JSON), để trống thì dùng JWT_SECRET_KEY với kid "default"
+func loadKeyRing() ([]*SigningKey, error) {
+	keyRingOnce.Do(func() {
+		keyFile := config.Config("JWT_KEY_FILE")
+		if len(keyFile) == 0 {
+			keyRing = []*SigningKey{{
+				Kid:       "default",
+				Alg:       "HS256",
+				Status:    "active",
+				signKey:   []byte(config.Config("JWT_SECRET_KEY")),
+				verifyKey: []byte(config.Config("JWT_SECRET_KEY")),
+			}}
+			return
+		}
+
+		content, err := os.ReadFile(keyFile)
+		if err != nil {
+			keyRingErr = err
+			return
+		}
+
+		var keys []*SigningKey
+		if err := json.Unmarshal(content, &keys); err != nil {
+			keyRingErr = err
+			return
+		}
+
+		for _, key := range keys {
+			if err := key.load(); err != nil {
+				keyRingErr = fmt.Errorf("jwt key %s: %w", key.Kid, err)
+				return
+			}
+		}
+
+		keyRing = keys
+	})
+
+	return keyRing, keyRingErr
+}
+
+func (k *SigningKey) load() error {
+	switch k.Alg {
+	case "HS256":
+		k.signKey = []byte(k.Secret)
+		k.verifyKey = []byte(k.Secret)
+	case "RS256":
+		if len(k.PrivateKey) > 0 {
+			content, err := os.ReadFile(k.PrivateKey)
+			if err != nil {
+				return err
+			}
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(content)
+			if err != nil {
+				return err
+			}
+			k.signKey = private
+			k.verifyKey = &private.PublicKey
+		} else {
+			content, err := os.ReadFile(k.PublicKey)
+			if err != nil {
+				return err
+			}
+			public, err := jwt.ParseRSAPublicKeyFromPEM(content)
+			if err != nil {
+				return err
+			}
+			k.verifyKey = public
+		}
+	case "EdDSA":
+		if len(k.PrivateKey) > 0 {
+			content, err := os.ReadFile(k.PrivateKey)
+			if err != nil {
+				return err
+			}
+			private, err := jwt.ParseEdPrivateKeyFromPEM(content)
+			if err != nil {
+				return err
+			}
+			k.signKey = private
+			k.verifyKey = private.(ed25519.PrivateKey).Public()
+		} else {
+			content, err := os.ReadFile(k.PublicKey)
+			if err != nil {
+				return err
+			}
+			public, err := jwt.ParseEdPublicKeyFromPEM(content)
+			if err != nil {
+				return err
+			}
+			k.verifyKey = public
+		}
+	default:
+		return fmt.Errorf("unsupported alg %s", k.Alg)
+	}
+
+	if k.Status == "active" && k.signKey == nil {
+		return errors.New("active key needs a private key")
+	}
+
+	return nil
+}
+
+// SignToken ký claims bằng khoá active đầu tiên và gắn header `kid`
+func SignToken(claims jwt.MapClaims) (string, error) {
+	keys, err := loadKeyRing()
+	if err != nil {
+		return "", err
+	}
+
+	for _, key := range keys {
+		if key.Status != "active" {
+			continue
+		}
+
+		token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Alg), claims)
+		token.Header["kid"] = key.Kid
+
+		return token.SignedString(key.signKey)
+	}
+
+	return "", errors.New("no active jwt key")
+}
+
+// Chọn khoá theo `kid`, token cũ không có kid thì dùng khoá "default".
+// Thuật toán của token phải trùng với thuật toán của khoá.
+func jwtKeyFunc(token *jwt.Token) (interface{}, error) {
+	keys, err := loadKeyRing()
+	if err != nil {
+		return nil, err
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if len(kid) == 0 {
+		kid = "default"
+	}
+
+	for _, key := range keys {
+		if key.Kid != kid {
+			continue
+		}
+
+		if token.Method.Alg() != key.Alg {
+			return nil, fmt.Errorf("unexpected alg %s for kid %s", token.Method.Alg(), kid)
+		}
+
+		return key.verifyKey, nil
+	}
+
+	return nil, fmt.Errorf("unknown kid %s", kid)
+}
+
+// Jwks danh sách khoá công khai (RS256, EdDSA) để service khác xác thực token
+func Jwks() ([]Jwk, error) {
+	keys, err := loadKeyRing()
+	if err != nil {
+		return nil, err
+	}
+
+	jwks := []Jwk{}
+	for _, key := range keys {
+		switch public := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			jwks = append(jwks, Jwk{
+				Kty: "RSA",
+				Kid: key.Kid,
+				Use: "sig",
+				Alg: key.Alg,
+				N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks = append(jwks, Jwk{
+				Kty: "OKP",
+				Kid: key.Kid,
+				Use: "sig",
+				Alg: key.Alg,
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(public),
+			})
+		}
+	}
+
+	return jwks, nil
+}
@@ -11,11 +11,19 @@ import (
 )
 
 type TokenData struct {
-	Username  string
-	Useragent string
-	IPAdress  string
-	Createdat int64
-	Expires   int64
+	SessionID  string
+	Username   string
+	Actor      string // admin đang giả danh Username, rỗng nếu là session thường
+	Useragent  string
+	IPAdress   string
+	Permission int
+	Createdat  int64
+	Expires    int64
+}
+
+// Impersonated token được cấp qua /authen/impersonate
+func (t *TokenData) Impersonated() bool {
+	return len(t.Actor) > 0
 }
 
 func extractToken(c *fiber.Ctx) string {
@@ -36,7 +44,8 @@ func VerifyToken(c *fiber.Ctx) (*jwt.Token, error) {
 		return nil, errors.New(msg)
 	}
 
-	token, err := jwt.Parse(tokenString, jwtKeyFunc)
+	parser := &jwt.Parser{ValidMethods: validMethods}
+	token, err := parser.Parse(tokenString, jwtKeyFunc)
 	if err != nil {
 		return nil, err
 	}
@@ -44,10 +53,6 @@ func VerifyToken(c *fiber.Ctx) (*jwt.Token, error) {
 	return token, nil
 }
 
-func jwtKeyFunc(token *jwt.Token) (interface{}, error) {
-	return []byte(config.Config("JWT_SECRET_KEY")), nil
-}
-
 func ExtractTokenData(c *fiber.Ctx) (*TokenData, error) {
 	token, err := VerifyToken(c)
 	if err != nil {
@@ -57,10 +62,20 @@ func ExtractTokenData(c *fiber.Ctx) (*TokenData, error) {
 	// Setting and checking token and credentials.
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if ok && token.Valid {
+		// Thiếu claim hoặc sai kiểu thì lấy giá trị 0 thay vì panic
+		permission, _ := claims["permission"].(float64)
+		actor, _ := claims["act"].(string)
+		createdat, _ := claims["createdat"].(float64)
+		expires, _ := claims["exp"].(float64)
 		return &TokenData{
-			Username:  fmt.Sprint(claims["username"]),
-			Createdat: int64((claims["iat"]).(float64)),
-			Expires:   int64((claims["exp"]).(float64)),
+			SessionID:  fmt.Sprint(claims["sid"]),
+			Username:   fmt.Sprint(claims["username"]),
+			Actor:      actor,
+			Useragent:  fmt.Sprint(claims["useragent"]),
+			IPAdress:   fmt.Sprint(claims["ipaddress"]),
+			Permission: int(permission),
+			Createdat:  int64(createdat),
+			Expires:    int64(expires),
 		}, nil
 	}
 
@@ -87,13 +102,16 @@ func ExtractTokenDataMobile(c *fiber.Ctx) (*TokenDataMobile, error) {
 	// Setting and checking token and credentials.
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if ok && token.Valid {
+		permission, _ := claims["permission"].(float64)
+		createdat, _ := claims["createdat"].(float64)
+		expires, _ := claims["exp"].(float64)
 		return &TokenDataMobile{
 			Username:   fmt.Sprint(claims["username"]),
 			Useragent:  fmt.Sprint(claims["useragent"]),
 			IPAdress:   fmt.Sprint(claims["ipaddress"]),
-			Permission: int(claims["permission"].(float64)),
-			Createdat:  int64((claims["createdat"]).(float64)),
-			Expires:    int64((claims["exp"]).(float64)),
+			Permission: int(permission),
+			Createdat:  int64(createdat),
+			Expires:    int64(expires),
 		}, nil
 	}
 
@@ -105,6 +123,7 @@ EncodedData
 */
 
 type Data struct {
+	TokenID       string `json:"jti"`
 	EmployeeId    string `json:"employee_id"`
 	DateInSeconds int64  `json:"date_in_seconds"`
 	Coordinates   string `json:"coordinates"`
@@ -116,7 +135,8 @@ func DecodeData(encodedData string) (*Data, error) {
 		return nil, errors.New("data is incorrect")
 	}
 
-	data, err := jwt.Parse(encodedData, func(token *jwt.Token) (interface{}, error) {
+	parser := &jwt.Parser{ValidMethods: []string{"HS256"}}
+	data, err := parser.Parse(encodedData, func(token *jwt.Token) (interface{}, error) {
 		return []byte(config.Config("JWT_DATA_SECRET_KEY")), nil
 	})
 
@@ -126,14 +146,24 @@ func DecodeData(encodedData string) (*Data, error) {
 
 	// Setting and checking token and credentials.
 	claims, ok := data.Claims.(jwt.MapClaims)
-	if ok && data.Valid {
-		return &Data{
-			EmployeeId:    fmt.Sprint(claims["employee_id"]),
-			DateInSeconds: int64((claims["date_in_seconds"]).(float64)),
-			Coordinates:   fmt.Sprint(claims["coordinates"]),
-			ShiftId:       int64((claims["shift_id"]).(float64)),
-		}, nil
+	if !ok || !data.Valid {
+		return nil, errors.New("data is incorrect")
 	}
 
-	return nil, err
+	// Thiếu claim hoặc sai kiểu (vd: payload cũ employeeId / shiftId) thì báo lỗi thay vì panic
+	dateInSeconds, okDate := claims["date_in_seconds"].(float64)
+	shiftId, okShift := claims["shift_id"].(float64)
+	employeeId, okEmployee := claims["employee_id"].(string)
+	tokenID, okToken := claims["jti"].(string)
+	if !okDate || !okShift || !okEmployee || !okToken {
+		return nil, errors.New("data is incorrect")
+	}
+
+	return &Data{
+		TokenID:       tokenID,
+		EmployeeId:    employeeId,
+		DateInSeconds: int64(dateInSeconds),
+		Coordinates:   fmt.Sprint(claims["coordinates"]),
+		ShiftId:       int64(shiftId),
+	}, nil
 }
@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"app/config"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// EncodePurposeToken ký token ngắn hạn cho một mục đích (password_reset, email_verify...).
+// fingerprint gắn với trạng thái hiện tại của user để token chỉ dùng được một lần.
+// Ký bằng JWT_DATA_SECRET_KEY nên không thể dùng thay access token.
+func EncodePurposeToken(purpose, subject, fingerprint string, minutes int) (string, error) {
+	claims := jwt.MapClaims{}
+
+	claims["purpose"] = purpose
+	claims["sub"] = subject
+	claims["fp"] = fingerprint
+	claims["createdat"] = time.Now().Unix()
+	claims["exp"] = time.Now().Add(time.Minute * time.Duration(minutes)).Unix()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	return token.SignedString([]byte(config.Config("JWT_DATA_SECRET_KEY")))
+}
+
+// DecodePurposeToken trả về subject và fingerprint nếu token hợp lệ và đúng mục đích
+func DecodePurposeToken(tokenString, purpose string) (string, string, error) {
+	parser := &jwt.Parser{ValidMethods: []string{"HS256"}}
+	token, err := parser.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		return []byte(config.Config("JWT_DATA_SECRET_KEY")), nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid || claims["purpose"] != purpose {
+		return "", "", errors.New(config.GetMessageCode("TOKEN_INCORRECT"))
+	}
+
+	return fmt.Sprint(claims["sub"]), fmt.Sprint(claims["fp"]), nil
+}
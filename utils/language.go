@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"app/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Language ngôn ngữ của request (middleware.Language lưu trong c.Locals("lang"))
+func Language(c *fiber.Ctx) string {
+	if lang, ok := c.Locals("lang").(string); ok {
+		return lang
+	}
+
+	return config.NegotiateLanguage(c.Query("lang"), c.Get("Accept-Language"))
+}
+
+// AllTranslations true khi client yêu cầu trả về đủ các bản dịch (?translations=true) ngoài name
+func AllTranslations(c *fiber.Ctx) bool {
+	return c.QueryBool("translations")
+}
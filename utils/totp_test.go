@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+// Khoá ASCII "12345678901234567890" của RFC 6238 phụ lục B (SHA1), mã 6 chữ số là 6 chữ số cuối của mã 8 chữ số
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTotpCodeRFC6238(t *testing.T) {
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},          // 94287082
+		{1111111109, "081804"},  // 07081804
+		{1111111111, "050471"},  // 14050471
+		{1234567890, "005924"},  // 89005924
+		{2000000000, "279037"},  // 69279037
+		{20000000000, "353130"}, // 65353130
+	}
+
+	for _, item := range cases {
+		code, err := totpCode(rfcSecret, TotpStep(time.Unix(item.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != item.code {
+			t.Errorf("T=%d: got %s, want %s", item.unix, code, item.code)
+		}
+	}
+
+	// Secret chữ thường vẫn đọc được
+	if code, _ := totpCode("gezdgnbvgy3tqojqgezdgnbvgy3tqojq", TotpStep(time.Unix(59, 0))); code != "287082" {
+		t.Errorf("lowercase secret: got %s", code)
+	}
+}
+
+func TestVerifyTotp(t *testing.T) {
+	at := time.Unix(1111111111, 0)
+	step := TotpStep(at)
+	code := func(step int64) string {
+		value, _ := totpCode(rfcSecret, step)
+		return value
+	}
+
+	cases := []struct {
+		name     string
+		code     string
+		lastStep int64
+		want     bool
+	}{
+		{"current step", code(step), 0, true},
+		{"previous step", code(step - 1), 0, true},
+		{"next step", code(step + 1), 0, true},
+		{"two steps ago", code(step - 2), 0, false},
+		{"already used", code(step), step, false},
+		{"wrong code", "000000", 0, false},
+	}
+
+	for _, item := range cases {
+		matched, ok := VerifyTotp(rfcSecret, item.code, at, item.lastStep)
+		if ok != item.want {
+			t.Errorf("%s: got %v, want %v", item.name, ok, item.want)
+		}
+		if ok && code(matched) != item.code {
+			t.Errorf("%s: returned step %d does not match the code", item.name, matched)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"app/config"
+	"net/mail"
 	"strconv"
 	"strings"
 	"time"
@@ -68,6 +69,22 @@ func DateFormatCheck(listCheck []string, item, errors map[string]string) map[str
 
 }
 
+// Email dạng a@b.c, không kèm tên hiển thị
+func EmailFormatCheck(listCheck []string, item, errors map[string]string) map[string]string {
+	for _, key := range listCheck {
+		_, ok := errors[key]
+
+		address, err := mail.ParseAddress(item[key])
+
+		if !ok && (err != nil || address.Name != "" || address.Address != item[key]) {
+			errors[key] = config.GetMessageCode("FORMAT_EMAIL")
+		}
+	}
+
+	return errors
+
+}
+
 func NumberCheck(listCheck []string, item, errors map[string]string) map[string]string {
 	for _, key := range listCheck {
 		_, ok := errors[key]